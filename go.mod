@@ -8,6 +8,8 @@ require (
 	github.com/mailru/easyjson v0.7.6
 	github.com/stretchr/testify v1.7.0
 	golang.org/x/exp v0.0.0-20220823124025-807a23277127
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v3 v3.0.0
 )
 
 require (
@@ -16,5 +18,4 @@ require (
 	github.com/kr/text v0.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v3 v3.0.0 // indirect
 )
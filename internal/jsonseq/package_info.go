@@ -0,0 +1,4 @@
+// Package jsonseq splits a stream of JSON data into separate JSON values, so that each of them can be
+// parsed with jreader without reading the entire stream into memory. It is used by ldcontext.Decoder
+// and ldvalue.Decoder.
+package jsonseq
@@ -0,0 +1,210 @@
+package jsonseq
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+)
+
+// Scanner reads JSON values one at a time from a stream.
+//
+// The Scanner only finds the boundaries of each value, by tracking brackets and string quotes; it
+// does not otherwise check whether the value is valid JSON, since that will be done by whatever
+// parses the value. Syntax errors in the structure of the stream itself, such as a missing comma
+// between array elements, are reported as jreader.SyntaxError.
+type Scanner struct {
+	r           *bufio.Reader
+	array       bool
+	started     bool
+	done        bool
+	offset      int
+	value       []byte
+	valueOffset int
+	err         error
+}
+
+// NewArrayScanner creates a Scanner for a stream that contains a single JSON array. Each element of
+// the array is a separate value.
+func NewArrayScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r), array: true}
+}
+
+// NewSequenceScanner creates a Scanner for a stream that contains any number of JSON values
+// separated by whitespace, such as newline-delimited JSON.
+func NewSequenceScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r)}
+}
+
+// Next advances to the next value, returning true if there is one or false if the end of the
+// stream has been reached or there was an error.
+func (s *Scanner) Next() bool {
+	if s.done {
+		return false
+	}
+	found, err := s.next()
+	if err != nil {
+		s.err = err
+		found = false
+	}
+	if !found {
+		s.done = true
+		s.value = nil
+	}
+	return found
+}
+
+// Bytes returns the current value. The slice is only valid until the next call to Next.
+func (s *Scanner) Bytes() []byte {
+	return s.value
+}
+
+// Offset returns the zero-based byte offset of the current value within the stream.
+func (s *Scanner) Offset() int {
+	return s.valueOffset
+}
+
+// Err returns the error that stopped the Scanner, if any. If the end of the stream was reached
+// normally, it returns nil.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+func (s *Scanner) next() (bool, error) {
+	b, err := s.readNonWhitespace()
+	if !s.array {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		s.unreadByte()
+		return true, s.readValue()
+	}
+	if !s.started {
+		if err == nil && b != '[' {
+			return false, s.syntaxError("expected a JSON array")
+		}
+		s.started = true
+		if err == nil {
+			if b, err = s.readNonWhitespace(); err == nil && b != ']' {
+				s.unreadByte()
+				return true, s.readValue()
+			}
+		}
+	} else if err == nil && b == ',' {
+		return true, s.readValue()
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		return false, s.syntaxError("unexpected end of input")
+	case err != nil:
+		return false, err
+	case b == ']':
+		return false, s.requireEOF()
+	default:
+		return false, s.syntaxError("expected comma or end of array")
+	}
+}
+
+func (s *Scanner) readValue() error {
+	if _, err := s.readNonWhitespace(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return s.syntaxError("unexpected end of input")
+		}
+		return err
+	}
+	s.unreadByte()
+	s.value = s.value[:0]
+	s.valueOffset = s.offset
+	depth := 0
+	inString, escaped := false, false
+	for {
+		b, err := s.readByte()
+		if errors.Is(err, io.EOF) && depth == 0 && !inString && len(s.value) != 0 {
+			return nil // a number or literal at the very end of the stream
+		}
+		if errors.Is(err, io.EOF) {
+			return s.syntaxError("unexpected end of input")
+		}
+		if err != nil {
+			return err
+		}
+		if inString {
+			s.value = append(s.value, b)
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+				if depth == 0 {
+					return nil
+				}
+			}
+			continue
+		}
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']', ',', ' ', '\t', '\r', '\n':
+			if depth == 0 {
+				// This is the end of a number or literal, and the character belongs to whatever follows it.
+				s.unreadByte()
+				if len(s.value) == 0 {
+					return s.syntaxError("expected a value")
+				}
+				return nil
+			}
+			if b == '}' || b == ']' {
+				depth--
+				if depth == 0 {
+					s.value = append(s.value, b)
+					return nil
+				}
+			}
+		}
+		s.value = append(s.value, b)
+	}
+}
+
+func (s *Scanner) requireEOF() error {
+	if _, err := s.readNonWhitespace(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return err
+		}
+		return s.syntaxError("unexpected data after end of array")
+	}
+	return nil
+}
+
+func (s *Scanner) readNonWhitespace() (byte, error) {
+	for {
+		b, err := s.readByte()
+		if err != nil || (b != ' ' && b != '\t' && b != '\r' && b != '\n') {
+			return b, err
+		}
+	}
+}
+
+func (s *Scanner) readByte() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err == nil {
+		s.offset++
+	}
+	return b, err
+}
+
+func (s *Scanner) unreadByte() {
+	_ = s.r.UnreadByte() // can't fail, since it is only called after a successful ReadByte
+	s.offset--
+}
+
+func (s *Scanner) syntaxError(message string) error {
+	return jreader.SyntaxError{Message: message, Offset: s.offset}
+}
@@ -0,0 +1,111 @@
+package jsonseq
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scannedValue struct {
+	value  string
+	offset int
+}
+
+func scanAll(s *Scanner) []scannedValue {
+	var ret []scannedValue
+	for s.Next() {
+		ret = append(ret, scannedValue{string(s.Bytes()), s.Offset()})
+	}
+	return ret
+}
+
+func TestArrayScanner(t *testing.T) {
+	for _, p := range []struct {
+		input    string
+		expected []scannedValue
+	}{
+		{"[]", nil},
+		{" [ ] \n", nil},
+		{"[1]", []scannedValue{{"1", 1}}},
+		{`[1,"a",true,null,-2.5e3]`, []scannedValue{{"1", 1}, {`"a"`, 3}, {"true", 7}, {"null", 12}, {"-2.5e3", 17}}},
+		{"[ {\"a\": [1, {}]} ,\n [\"]\", \"\\\"[\"] ]",
+			[]scannedValue{{`{"a": [1, {}]}`, 2}, {`["]", "\"["]`, 20}}},
+	} {
+		t.Run(p.input, func(t *testing.T) {
+			for _, r := range []io.Reader{strings.NewReader(p.input), iotest.OneByteReader(strings.NewReader(p.input))} {
+				s := NewArrayScanner(r)
+				assert.Equal(t, p.expected, scanAll(s))
+				assert.NoError(t, s.Err())
+				assert.False(t, s.Next())
+			}
+		})
+	}
+}
+
+func TestSequenceScanner(t *testing.T) {
+	for _, p := range []struct {
+		input    string
+		expected []scannedValue
+	}{
+		{"", nil},
+		{"\n\n", nil},
+		{"1", []scannedValue{{"1", 0}}},
+		{"{\"a\":1}\n{\"b\":[2]}\n", []scannedValue{{`{"a":1}`, 0}, {`{"b":[2]}`, 8}}},
+		{"true\r\n\"x\" [] {\n}", []scannedValue{{"true", 0}, {`"x"`, 6}, {"[]", 10}, {"{\n}", 13}}},
+	} {
+		t.Run(p.input, func(t *testing.T) {
+			s := NewSequenceScanner(strings.NewReader(p.input))
+			assert.Equal(t, p.expected, scanAll(s))
+			assert.NoError(t, s.Err())
+		})
+	}
+}
+
+func TestScannerSyntaxErrors(t *testing.T) {
+	for _, p := range []struct {
+		name     string
+		scanner  *Scanner
+		expected []scannedValue
+		message  string
+	}{
+		{"empty array stream", NewArrayScanner(strings.NewReader("")), nil, "unexpected end of input"},
+		{"not an array", NewArrayScanner(strings.NewReader(`{"a":1}`)), nil, "expected a JSON array"},
+		{"unclosed array", NewArrayScanner(strings.NewReader("[1, 2")), []scannedValue{{"1", 1}, {"2", 4}},
+			"unexpected end of input"},
+		{"unclosed element", NewArrayScanner(strings.NewReader(`[{"a":[1}`)), nil, "unexpected end of input"},
+		{"unterminated string", NewArrayScanner(strings.NewReader(`["a`)), nil, "unexpected end of input"},
+		{"missing comma", NewArrayScanner(strings.NewReader("[1 2]")), []scannedValue{{"1", 1}},
+			"expected comma or end of array"},
+		{"trailing comma", NewArrayScanner(strings.NewReader("[1,]")), []scannedValue{{"1", 1}}, "expected a value"},
+		{"data after array", NewArrayScanner(strings.NewReader("[1] 2")), []scannedValue{{"1", 1}},
+			"unexpected data after end of array"},
+		{"comma in sequence", NewSequenceScanner(strings.NewReader("1,2")), []scannedValue{{"1", 0}}, "expected a value"},
+		{"unclosed object in sequence", NewSequenceScanner(strings.NewReader("{}\n{")), []scannedValue{{"{}", 0}},
+			"unexpected end of input"},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			assert.Equal(t, p.expected, scanAll(p.scanner))
+			require.IsType(t, jreader.SyntaxError{}, p.scanner.Err())
+			assert.Equal(t, p.message, p.scanner.Err().(jreader.SyntaxError).Message)
+			assert.False(t, p.scanner.Next())
+		})
+	}
+}
+
+func TestScannerReadError(t *testing.T) {
+	fakeError := errors.New("sorry")
+	s := NewArrayScanner(io.MultiReader(strings.NewReader("[1, "), iotest.ErrReader(fakeError)))
+	assert.Equal(t, []scannedValue{{"1", 1}}, scanAll(s))
+	assert.Equal(t, fakeError, s.Err())
+
+	s = NewSequenceScanner(io.MultiReader(strings.NewReader(`"a`), iotest.ErrReader(fakeError)))
+	assert.Nil(t, scanAll(s))
+	assert.Equal(t, fakeError, s.Err())
+}
@@ -0,0 +1,9 @@
+// Package valuereuse gives ldcontext access to ldvalue functionality for reusing the storage of
+// values that were already built. This is not part of the public API of ldvalue, because it breaks
+// the immutability of those values; ldcontext.PooledUnmarshaler uses it only for values that it
+// controls every use of.
+//
+// Since ldvalue cannot refer to ldcontext, and this package cannot refer to ldvalue without causing
+// an import cycle, the functionality is provided by exported ldvalue methods whose parameter is of
+// a type defined here, which only packages in this module can use.
+package valuereuse
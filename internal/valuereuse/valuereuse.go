@@ -0,0 +1,7 @@
+package valuereuse
+
+// Permission is the parameter type of ldvalue methods that reuse the storage of values that were
+// already built, such as ValueMapBuilder.ResetForReuse. Those methods are exported so that other
+// packages in this module can call them, but since this package is internal, code outside of the
+// module cannot refer to this type and so cannot call them.
+type Permission struct{}
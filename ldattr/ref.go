@@ -124,10 +124,40 @@ func NewLiteralRef(attrName string) Ref {
 	}
 	// If there is a leading slash, then the attribute name actually starts with a slash. To represent it
 	// as an Ref, it'll need to be escaped.
-	escapedPath := "/" + strings.ReplaceAll(strings.ReplaceAll(attrName, "~", "~0"), "/", "~1")
+	escapedPath := "/" + escapePathComponent(attrName)
 	return Ref{singlePathComponent: attrName, rawPath: escapedPath}
 }
 
+// NewRefFromComponents creates a Ref from a list of path components, such as the property names in
+// a path through nested JSON objects. Each component is used literally, as in [NewLiteralRef]; any
+// '/' or '~' characters are escaped as necessary in the resulting path string.
+//
+// For example: ldattr.NewRefFromComponents([]string{"address", "street"}) is exactly equivalent to
+// ldattr.NewRef("/address/street"). A single component is exactly equivalent to NewLiteralRef.
+//
+// The slice is copied, so it can be safely reused by the caller afterward. If the slice is empty, the
+// Ref is invalid with the error [lderrors.ErrAttributeEmpty]; if any component is an empty string,
+// the Ref is invalid with the error [lderrors.ErrAttributeExtraSlash].
+func NewRefFromComponents(components []string) Ref {
+	switch len(components) {
+	case 0:
+		return Ref{err: lderrors.ErrAttributeEmpty{}}
+	case 1:
+		return NewLiteralRef(components[0])
+	}
+	var sb strings.Builder
+	for _, c := range components {
+		if c == "" {
+			return Ref{err: lderrors.ErrAttributeExtraSlash{}}
+		}
+		sb.WriteByte('/')
+		sb.WriteString(escapePathComponent(c))
+	}
+	copied := make([]string, len(components))
+	copy(copied, components)
+	return Ref{rawPath: sb.String(), components: copied}
+}
+
 // IsDefined returns true if the Ref has a value, meaning that it is not an uninitialized Ref{}.
 // That does not guarantee that the value is valid; use [Ref.Err] to test that.
 func (a Ref) IsDefined() bool {
@@ -278,3 +308,12 @@ func unescapePath(path string) (string, bool) {
 	}
 	return string(out), true
 }
+
+// Performs escaping of a literal attribute name or property name so that it can be used as one
+// component of an attribute reference path; this is the reverse of unescapePath.
+func escapePathComponent(name string) string {
+	if !strings.ContainsAny(name, "~/") {
+		return name
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
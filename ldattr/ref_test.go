@@ -104,6 +104,20 @@ func TestNewLiteralRef(t *testing.T) {
 	assert.Equal(t, lderrors.ErrAttributeEmpty{}, a4.Err())
 }
 
+func TestNewRefFromComponents(t *testing.T) {
+	assert.Equal(t, NewRef("/address/street"), NewRefFromComponents([]string{"address", "street"}))
+	assert.Equal(t, NewRef("/a~1b/c~0d"), NewRefFromComponents([]string{"a/b", "c~d"}))
+	assert.Equal(t, NewLiteralRef("a/b"), NewRefFromComponents([]string{"a/b"}))
+
+	components := []string{"a", "b"}
+	a := NewRefFromComponents(components)
+	components[1] = "c"
+	assert.Equal(t, "b", a.Component(1))
+
+	assert.Equal(t, lderrors.ErrAttributeEmpty{}, NewRefFromComponents(nil).Err())
+	assert.Equal(t, lderrors.ErrAttributeExtraSlash{}, NewRefFromComponents([]string{"a", ""}).Err())
+}
+
 func TestRefComponents(t *testing.T) {
 	for _, params := range []struct {
 		input        string
@@ -31,6 +31,8 @@ const defaultMultiBuilderCapacity = 3 // arbitrary value based on presumed likel
 type MultiBuilder struct {
 	contexts            []Context
 	contextsCopyOnWrite bool
+	keyPolicy           KeyPolicy
+	kindKeyPolicies     map[Kind]KeyPolicy
 }
 
 // NewMultiBuilder creates a MultiBuilder for building a multi-context.
@@ -55,25 +57,33 @@ func NewMultiBuilder() *MultiBuilder {
 // multi-context-- since there is no logical difference in LaunchDarkly between a single context and
 // a multi-context that only contains one context.
 func (m *MultiBuilder) Build() Context {
-	if len(m.contexts) == 0 {
+	contexts := m.contexts
+	if m.keyPolicy != (KeyPolicy{}) || len(m.kindKeyPolicies) != 0 {
+		contexts = make([]Context, len(m.contexts))
+		for i, c := range m.contexts {
+			contexts[i] = m.applyKeyPolicy(c)
+		}
+	}
+
+	if len(contexts) == 0 {
 		return Context{defined: true, err: lderrors.ErrContextKindMultiWithNoKinds{}}
 	}
 
-	if len(m.contexts) == 1 {
+	if len(contexts) == 1 {
 		// If only one context was added, the result is just the same as that one
-		return m.contexts[0]
+		return contexts[0]
 	}
 
 	m.contextsCopyOnWrite = true // see note on ___CopyOnWrite in Builder.Build()
 
 	// Sort the list by kind - this makes our output deterministic and will also be important when we
 	// compute a fully qualified key.
-	sort.Slice(m.contexts, func(i, j int) bool { return m.contexts[i].Kind() < m.contexts[j].Kind() })
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Kind() < contexts[j].Kind() })
 
 	// Check for conditions that could make a multi-context invalid
 	var individualErrors map[string]error
 	duplicates := false
-	for i, c := range m.contexts {
+	for i, c := range contexts {
 		err := c.Err()
 		switch {
 		case err != nil: // one of the individual contexts already had an error
@@ -82,8 +92,8 @@ func (m *MultiBuilder) Build() Context {
 			}
 			individualErrors[string(c.Kind())] = err
 		default:
-			// duplicate check's correctness relies on m.contexts being sorted by kind.
-			if i > 0 && m.contexts[i-1].Kind() == c.Kind() {
+			// duplicate check's correctness relies on contexts being sorted by kind.
+			if i > 0 && contexts[i-1].Kind() == c.Kind() {
 				duplicates = true
 			}
 		}
@@ -105,13 +115,13 @@ func (m *MultiBuilder) Build() Context {
 	ret := Context{
 		defined:       true,
 		kind:          MultiKind,
-		multiContexts: m.contexts,
+		multiContexts: contexts,
 	}
 
 	// Fully-qualified key for multi-context is defined as "kind1:key1:kind2:key2" etc., where kinds are in
 	// alphabetical order (we have already sorted them above) and keys are URL-encoded. In this case we
 	// do _not_ omit a default kind of "user".
-	for _, c := range m.contexts {
+	for _, c := range contexts {
 		if ret.fullyQualifiedKey != "" {
 			ret.fullyQualifiedKey += ":"
 		}
@@ -186,3 +196,53 @@ func (m *MultiBuilder) Add(context Context) *MultiBuilder {
 	}
 	return m
 }
+
+// KeyPolicy sets normalization and validation rules that will be applied to the key of every
+// individual context when the multi-context is built, unless a different policy was set for that
+// kind with [MultiBuilder.KeyPolicyForKind]. See [KeyPolicy] for details.
+//
+// If the policy's validation fails for any individual context, [MultiBuilder.Build] returns an
+// invalid Context. If there is more than one individual context, its error is an
+// [lderrors.ErrContextPerKindErrors] containing the validation error for each kind that failed. If
+// only one context was added, Build returns that context with the policy applied (see
+// [MultiBuilder.Build]), so its error is the validation error itself, such as
+// [lderrors.ErrContextKeyControlChars].
+func (m *MultiBuilder) KeyPolicy(policy KeyPolicy) *MultiBuilder {
+	if m != nil {
+		m.keyPolicy = policy
+	}
+	return m
+}
+
+// KeyPolicyForKind is the same as [MultiBuilder.KeyPolicy], except that the policy only applies to
+// the individual context with the specified kind, replacing any policy set by KeyPolicy. For
+// instance, this could be used to apply case folding only to the keys of a kind that represents
+// email addresses.
+func (m *MultiBuilder) KeyPolicyForKind(kind Kind, policy KeyPolicy) *MultiBuilder {
+	if m != nil {
+		if m.kindKeyPolicies == nil {
+			m.kindKeyPolicies = make(map[Kind]KeyPolicy)
+		}
+		m.kindKeyPolicies[kind] = policy
+	}
+	return m
+}
+
+func (m *MultiBuilder) applyKeyPolicy(c Context) Context {
+	policy, ok := m.kindKeyPolicies[c.kind]
+	if !ok {
+		policy = m.keyPolicy
+	}
+	if policy == (KeyPolicy{}) || c.Err() != nil {
+		return c
+	}
+	key, err := policy.Apply(c.key)
+	switch {
+	case err != nil:
+		return Context{defined: true, err: err, kind: c.kind}
+	case key == c.key:
+		return c
+	default:
+		return NewBuilderFromContext(c).Key(key).Build()
+	}
+}
@@ -96,6 +96,9 @@ type Builder struct {
 	anonymous          bool
 	privateAttrs       []ldattr.Ref
 	privateCopyOnWrite bool
+	limits             ContextLimits
+	keyPolicy          KeyPolicy
+	spareStorage       []ldattr.Ref // see resetForReuse
 }
 
 // NewBuilder creates a Builder for building a Context, initializing its Key property and
@@ -161,7 +164,13 @@ func (b *Builder) Build() Context {
 	if err != nil {
 		return Context{defined: true, err: err, kind: b.kind}
 	}
-	if b.key == "" && !b.allowEmptyKey {
+	key := b.key
+	if b.keyPolicy != (KeyPolicy{}) {
+		if key, err = b.keyPolicy.Apply(key); err != nil {
+			return Context{defined: true, err: err, kind: b.kind}
+		}
+	}
+	if key == "" && !b.allowEmptyKey {
 		return Context{defined: true, err: lderrors.ErrContextKeyEmpty{}, kind: b.kind}
 	}
 	// We set the kind in the error cases above because that improves error reporting if this
@@ -170,7 +179,7 @@ func (b *Builder) Build() Context {
 	ret := Context{
 		defined:   true,
 		kind:      actualKind,
-		key:       b.key,
+		key:       key,
 		name:      b.name,
 		anonymous: b.anonymous,
 	}
@@ -189,6 +198,9 @@ func (b *Builder) Build() Context {
 		// is not supported anyway.
 	}
 
+	if b.limits != (ContextLimits{}) {
+		return b.limits.applyToBuild(ret)
+	}
 	return ret
 }
 
@@ -448,6 +460,130 @@ func (b *Builder) TrySetValue(attributeName string, value ldvalue.Value) bool {
 	return true
 }
 
+// SetValueForRef is equivalent to [Builder.SetValue], but uses the [ldattr.Ref] type, so it can also
+// set a property within a JSON object. Any intermediate objects that do not exist yet are created; for
+// instance, b.SetValueForRef(ldattr.NewRef("/address/city"), ldvalue.String("Oakland")) sets the
+// "address" attribute to {"city": "Oakland"} if it was not already set, or adds the "city" property
+// to it if it was already an object.
+//
+// Setting a nested property to [ldvalue.Null]() removes it, as if by [Builder.RemoveRef].
+//
+// If the value could not be set, SetValueForRef does nothing. To find out whether this happened, use
+// [Builder.TrySetValueForRef].
+func (b *Builder) SetValueForRef(attrRef ldattr.Ref, value ldvalue.Value) *Builder {
+	_ = b.TrySetValueForRef(attrRef, value)
+	return b
+}
+
+// TrySetValueForRef is the same as [Builder.SetValueForRef], except that it returns an error if the
+// value could not be set:
+//
+//   - If the Ref is invalid, the error is the same as [ldattr.Ref.Err].
+//   - If the Ref has a single path component and SetValue would have rejected the value (for
+//     instance, a non-string value for "key"), the error is [lderrors.ErrContextAttributeInvalidValue].
+//   - If one of the path components refers to an existing value that is not a JSON object, including
+//     any of the built-in attributes such as "name", the error is [lderrors.ErrValuePathNotObject].
+//
+// In all of these cases, the builder is left unchanged.
+func (b *Builder) TrySetValueForRef(attrRef ldattr.Ref, value ldvalue.Value) error {
+	if err := attrRef.Err(); err != nil {
+		return err
+	}
+	if b == nil {
+		return nil
+	}
+	attrName := attrRef.Component(0)
+	if attrRef.Depth() == 1 {
+		if !b.TrySetValue(attrName, value) {
+			return lderrors.ErrContextAttributeInvalidValue{Attribute: attrName}
+		}
+		return nil
+	}
+	switch attrName {
+	case ldattr.KindAttr, ldattr.KeyAttr, ldattr.NameAttr, ldattr.AnonymousAttr:
+		return lderrors.ErrValuePathNotObject{Property: attrName}
+	case jsonPropMeta:
+		return lderrors.ErrContextAttributeInvalidValue{Attribute: attrName}
+	}
+	if value.IsNull() {
+		b.RemoveRef(attrRef)
+		return nil
+	}
+	path := make([]string, attrRef.Depth())
+	for i := range path {
+		path[i] = attrRef.Component(i)
+	}
+	return b.attributes.TrySetPath(path, value)
+}
+
+// Remove removes an attribute that was previously set.
+//
+// For a custom attribute, this is equivalent to calling b.SetValue(attributeName, ldvalue.Null()).
+// For the "name" attribute, it clears the name as if by b.OptName(ldvalue.OptionalString{}). The
+// attributes "kind", "key", and "anonymous" cannot be removed, since every Context has a value for
+// them; for these, Remove has no effect.
+//
+// Removing an attribute does not affect any private attribute references to it (see [Builder.Private]).
+func (b *Builder) Remove(attributeName string) *Builder {
+	if b == nil {
+		return b
+	}
+	switch attributeName {
+	case ldattr.KindAttr, ldattr.KeyAttr, ldattr.AnonymousAttr, jsonPropMeta:
+	case ldattr.NameAttr:
+		b.name = ldvalue.OptionalString{}
+	default:
+		b.attributes.Remove(attributeName)
+	}
+	return b
+}
+
+// RemoveRef is equivalent to [Builder.Remove], but uses the [ldattr.Ref] type, so it can also remove
+// a property within a JSON object. For instance, if the "address" attribute has the value
+// {"street": "abc", "city": "def"}, then b.RemoveRef(ldattr.NewRef("/address/street")) changes it to
+// {"city": "def"}.
+//
+// If the Ref is invalid, or if the path does not exist in the Context (for instance, if one of the
+// path components refers to a value that is not a JSON object), RemoveRef has no effect.
+func (b *Builder) RemoveRef(attrRef ldattr.Ref) *Builder {
+	if b == nil || attrRef.Err() != nil {
+		return b
+	}
+	attrName := attrRef.Component(0)
+	if attrRef.Depth() == 1 {
+		return b.Remove(attrName)
+	}
+	if updated, ok := removeNestedProperty(b.attributes.Get(attrName), attrRef, 1); ok {
+		b.attributes.Set(attrName, updated)
+	}
+	return b
+}
+
+func removeNestedProperty(value ldvalue.Value, attrRef ldattr.Ref, depth int) (ldvalue.Value, bool) {
+	if value.Type() == ldvalue.RawType {
+		value = ldvalue.Parse(value.AsRaw())
+	}
+	if value.Type() != ldvalue.ObjectType {
+		return value, false
+	}
+	name := attrRef.Component(depth)
+	child, ok := value.TryGetByKey(name)
+	if !ok {
+		return value, false
+	}
+	mb := ldvalue.ValueMapBuildFromMap(value.AsValueMap())
+	if depth == attrRef.Depth()-1 {
+		mb.Remove(name)
+	} else {
+		updatedChild, ok := removeNestedProperty(child, attrRef, depth+1)
+		if !ok {
+			return value, false
+		}
+		mb.Set(name, updatedChild)
+	}
+	return mb.Build().AsValue(), true
+}
+
 // Anonymous sets whether the Context is only intended for flag evaluations and should not be indexed by
 // LaunchDarkly.
 //
@@ -535,9 +671,12 @@ func (b *Builder) PrivateRef(attrRefs ...ldattr.Ref) *Builder {
 	if b == nil {
 		return b
 	}
-	if b.privateAttrs == nil {
+	switch {
+	case b.privateAttrs == nil && b.spareStorage != nil:
+		b.privateAttrs, b.spareStorage = b.spareStorage, nil
+	case b.privateAttrs == nil:
 		b.privateAttrs = make([]ldattr.Ref, 0, len(attrRefs))
-	} else if b.privateCopyOnWrite {
+	case b.privateCopyOnWrite:
 		// See note in Build() on ___CopyOnWrite
 		b.privateAttrs = slices.Clone(b.privateAttrs)
 		b.privateCopyOnWrite = false
@@ -582,6 +721,37 @@ func (b *Builder) RemovePrivateRef(attrRefs ...ldattr.Ref) *Builder {
 	return b
 }
 
+// KeyPolicy sets normalization and validation rules that will be applied to the key when the
+// Context is built. See [KeyPolicy] for details.
+//
+// The Builder's own key property is not changed, so calling KeyPolicy before or after [Builder.Key]
+// has the same effect. If the policy's validation fails, [Builder.Build] returns an invalid Context.
+// If the normalized key is an empty string, the Context is invalid just as if the key had been set
+// to an empty string.
+func (b *Builder) KeyPolicy(policy KeyPolicy) *Builder {
+	if b != nil {
+		b.keyPolicy = policy
+	}
+	return b
+}
+
+// Limits sets size limits that will be applied when the Context is built. See [ContextLimits] for
+// the available limits.
+//
+// If limits.Truncate is true, [Builder.Build] removes or truncates attributes as described in
+// [ContextLimits.Enforce]. Otherwise, if the limits are exceeded, Build returns an invalid Context
+// whose [Context.Err] is an [lderrors.ErrContextLimitViolations]. In either case, if the Context
+// cannot be brought within the limits, it is invalid.
+//
+// Passing ContextLimits{} removes any previously set limits. Limits set on a Builder do not apply to
+// a multi-context that the resulting Context is later added to.
+func (b *Builder) Limits(limits ContextLimits) *Builder {
+	if b != nil {
+		b.limits = limits
+	}
+	return b
+}
+
 func (b *Builder) copyFrom(fromContext Context) {
 	if fromContext.Multiple() || b == nil {
 		return
@@ -454,3 +454,117 @@ func TestBuilderSafety(t *testing.T) {
 	assert.Nil(t, nilPtr.RemovePrivate("a"))
 	assert.Equal(t, Context{}, nilPtr.Build())
 }
+
+func TestBuilderRemove(t *testing.T) {
+	c := NewBuilder("my-key").Name("n").Anonymous(true).SetString("a", "1").SetString("b", "2").
+		Remove("a").Remove("name").Remove("key").Remove("kind").Remove("anonymous").Remove("_meta").
+		Remove("nonexistent").
+		Build()
+	assert.Equal(t, NewBuilder("my-key").Anonymous(true).SetString("b", "2").Build(), c)
+
+	assert.Nil(t, (*Builder)(nil).Remove("a"))
+}
+
+func TestBuilderRemoveRef(t *testing.T) {
+	address := ldvalue.Parse([]byte(`{"street":{"line1":"x","line2":"y"},"city":"z"}`))
+
+	t.Run("top-level attribute", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValue("address", address).SetString("b", "2").
+			RemoveRef(ldattr.NewRef("/address")).Build()
+		assert.Equal(t, NewBuilder("my-key").SetString("b", "2").Build(), c)
+	})
+
+	t.Run("nested property", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValue("address", address).
+			RemoveRef(ldattr.NewRef("/address/street/line2")).
+			RemoveRef(ldattr.NewRef("/address/city")).
+			Build()
+		jsonhelpers.AssertEqual(t, `{"street":{"line1":"x"}}`, c.GetValue("address"))
+	})
+
+	t.Run("raw value", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValue("address", ldvalue.Raw([]byte(`{"city":"z","zip":"1"}`))).
+			RemoveRef(ldattr.NewRef("/address/zip")).
+			Build()
+		jsonhelpers.AssertEqual(t, `{"city":"z"}`, c.GetValue("address"))
+	})
+
+	t.Run("nonexistent paths", func(t *testing.T) {
+		b := NewBuilder("my-key").SetValue("address", address).SetString("s", "x")
+		for _, ref := range []string{"/address/zip", "/address/city/x", "/address/street/line3", "/s/x", "/other/x", "/a//b"} {
+			b.RemoveRef(ldattr.NewRef(ref))
+		}
+		assert.Equal(t, NewBuilder("my-key").SetValue("address", address).SetString("s", "x").Build(), b.Build())
+	})
+
+	t.Run("does not modify previously built context", func(t *testing.T) {
+		b := NewBuilder("my-key").SetValue("address", address)
+		c1 := b.Build()
+		c2 := b.RemoveRef(ldattr.NewRef("/address/city")).Build()
+		assert.Equal(t, address, c1.GetValue("address"))
+		jsonhelpers.AssertEqual(t, `{"street":{"line1":"x","line2":"y"}}`, c2.GetValue("address"))
+	})
+}
+
+func TestBuilderSetValueForRef(t *testing.T) {
+	t.Run("top-level attribute", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValueForRef(ldattr.NewRef("/a"), ldvalue.Int(1)).Build()
+		assert.Equal(t, NewBuilder("my-key").SetInt("a", 1).Build(), c)
+	})
+
+	t.Run("built-in attribute", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValueForRef(ldattr.NewRef("name"), ldvalue.String("x")).Build()
+		assert.Equal(t, NewBuilder("my-key").Name("x").Build(), c)
+	})
+
+	t.Run("creates intermediate objects", func(t *testing.T) {
+		c := NewBuilder("my-key").
+			SetValueForRef(ldattr.NewRef("/address/street/line1"), ldvalue.String("x")).
+			SetValueForRef(ldattr.NewRef("/address/city"), ldvalue.String("y")).
+			Build()
+		jsonhelpers.AssertEqual(t, `{"street":{"line1":"x"},"city":"y"}`, c.GetValue("address"))
+	})
+
+	t.Run("escaped path components", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValueForRef(ldattr.NewRef("/a~1b/c~0d"), ldvalue.Int(1)).Build()
+		jsonhelpers.AssertEqual(t, `{"c~d":1}`, c.GetValue("a/b"))
+	})
+
+	t.Run("null removes nested property", func(t *testing.T) {
+		c := NewBuilder("my-key").SetValue("address", ldvalue.Parse([]byte(`{"city":"y","zip":"1"}`))).
+			SetValueForRef(ldattr.NewRef("/address/zip"), ldvalue.Null()).
+			Build()
+		jsonhelpers.AssertEqual(t, `{"city":"y"}`, c.GetValue("address"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		address := ldvalue.Parse([]byte(`{"city":"y"}`))
+		for _, p := range []struct {
+			ref         string
+			value       ldvalue.Value
+			expectedErr error
+		}{
+			{"/", ldvalue.Int(1), lderrors.ErrAttributeEmpty{}},
+			{"/a//b", ldvalue.Int(1), lderrors.ErrAttributeExtraSlash{}},
+			{"key", ldvalue.Int(1), lderrors.ErrContextAttributeInvalidValue{Attribute: "key"}},
+			{"_meta", ldvalue.Int(1), lderrors.ErrContextAttributeInvalidValue{Attribute: "_meta"}},
+			{"/_meta/x", ldvalue.Int(1), lderrors.ErrContextAttributeInvalidValue{Attribute: "_meta"}},
+			{"/name/x", ldvalue.Int(1), lderrors.ErrValuePathNotObject{Property: "name"}},
+			{"/address/city/x", ldvalue.Int(1), lderrors.ErrValuePathNotObject{Property: "city"}},
+		} {
+			t.Run(p.ref, func(t *testing.T) {
+				b := NewBuilder("my-key").SetValue("address", address)
+				assert.Equal(t, p.expectedErr, b.TrySetValueForRef(ldattr.NewRef(p.ref), p.value))
+				assert.Equal(t, NewBuilder("my-key").SetValue("address", address).Build(), b.Build())
+			})
+		}
+	})
+
+	t.Run("does not modify previously built context", func(t *testing.T) {
+		b := NewBuilder("my-key").SetValueForRef(ldattr.NewRef("/address/city"), ldvalue.String("y"))
+		c1 := b.Build()
+		c2 := b.SetValueForRef(ldattr.NewRef("/address/zip"), ldvalue.String("1")).Build()
+		jsonhelpers.AssertEqual(t, `{"city":"y"}`, c1.GetValue("address"))
+		jsonhelpers.AssertEqual(t, `{"city":"y","zip":"1"}`, c2.GetValue("address"))
+	})
+}
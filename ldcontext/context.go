@@ -110,6 +110,7 @@ func (c Context) Key() string {
 //
 // This value is used whenever LaunchDarkly needs a string identifier based on all of the Kind and
 // Key values in the context; the SDK may use this for caching previously seen contexts, for instance.
+// To reconstruct a Context containing only those Kind and Key values, use [ParseFullyQualifiedKey].
 func (c Context) FullyQualifiedKey() string {
 	return c.fullyQualifiedKey
 }
@@ -218,11 +219,8 @@ func (c Context) GetValueForRef(ref ldattr.Ref) ldvalue.Value {
 	}
 	for i := 1; i < ref.Depth(); i++ {
 		name := ref.Component(i)
-		if value.Type() == ldvalue.RawType {
-			// The "raw" type in ldvalue.Value is for unparsed JSON data, but we do need to parse it if
-			// we're going to look for a property within it.
-			value = ldvalue.Parse(value.AsRaw())
-		}
+		// If this is unparsed JSON data of the "raw" type in ldvalue.Value, GetByKey will parse it as
+		// needed; a value created with ldvalue.LazyRaw will only parse the property we are looking for.
 		value = value.GetByKey(name)
 		// The defined behavior of GetByKey is that it sets value to ldvalue.Null() if the key was not
 		// found, or if the value was not an object.
@@ -397,6 +395,72 @@ func (c Context) String() string {
 	return string(data)
 }
 
+// Transform applies a transformation function to the optional attributes of a Context, returning a new
+// Context. This is similar to [ldvalue.ValueMap.Transform].
+//
+// The function is called for each attribute that would be returned by [Context.GetOptionalAttributeNames]:
+// that is, every custom attribute, and the "name" attribute if it is set. For a multi-context, it is
+// called for the attributes of each individual context, and the kind parameter indicates which one.
+// The function should return the new value and true, or else return false if the attribute should be
+// removed; returning [ldvalue.Null]() also removes the attribute. If it returns a value that is not
+// allowed for the attribute (such as a non-string value for "name"), the attribute is unchanged.
+//
+// The kind, key, anonymous, and private attribute properties are not affected.
+//
+// If the function does not change anything, or if the Context is invalid, the same Context is returned.
+func (c Context) Transform(fn func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool)) Context {
+	if c.Err() != nil {
+		return c
+	}
+	if !c.Multiple() {
+		ret, _ := c.transformSingleKind(fn)
+		return ret
+	}
+	var mb *MultiBuilder
+	for i, mc := range c.multiContexts {
+		transformed, changed := mc.transformSingleKind(fn)
+		if mb == nil {
+			if !changed {
+				continue
+			}
+			mb = NewMultiBuilder()
+			for _, unchanged := range c.multiContexts[:i] {
+				mb.Add(unchanged)
+			}
+		}
+		mb.Add(transformed)
+	}
+	if mb == nil {
+		return c
+	}
+	return mb.Build()
+}
+
+func (c Context) transformSingleKind(
+	fn func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool),
+) (Context, bool) {
+	var b *Builder
+	for _, name := range c.GetOptionalAttributeNames(nil) {
+		value := c.GetValue(name)
+		newValue, ok := fn(c.kind, name, value)
+		if ok && newValue.Equal(value) {
+			continue
+		}
+		if b == nil {
+			b = NewBuilderFromContext(c)
+		}
+		if ok {
+			b.TrySetValue(name, newValue)
+		} else {
+			b.Remove(name)
+		}
+	}
+	if b == nil {
+		return c, false
+	}
+	return b.Build(), true
+}
+
 func (c Context) getTopLevelAddressableAttributeSingleKind(name string) (ldvalue.Value, bool) {
 	switch name {
 	case ldattr.KindAttr:
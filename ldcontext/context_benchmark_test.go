@@ -50,3 +50,40 @@ func BenchmarkContextGetCustomAttrNestedPropertyNoAlloc(b *testing.B) {
 		benchmarkValue = c.Build().GetValueForRef(attrRef)
 	}
 }
+
+func BenchmarkContextWalkNoAlloc(b *testing.B) {
+	for _, n := range []int{sharedtest.SmallNumberOfCustomAttributes, sharedtest.LargeNumberOfCustomAttributes} {
+		b.Run(fmt.Sprintf("with %d attributes", n), func(b *testing.B) {
+			builder := NewBuilder("key")
+			for _, a := range sharedtest.MakeCustomAttributeNamesAndValues(n) {
+				builder.SetValue(a.Name, a.Value)
+			}
+			c := builder.Build()
+			fn := func(kind Kind, ref ldattr.Ref, value ldvalue.Value) bool {
+				benchmarkValue = value
+				return true
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Walk(fn)
+			}
+		})
+	}
+}
+
+func BenchmarkContextWalkObjectAttributeNoAlloc(b *testing.B) {
+	address := ldvalue.ObjectBuild().
+		SetString("street", "17 Highbrow Street").
+		SetString("city", "London").
+		Set("geo", ldvalue.ObjectBuild().SetFloat64("lat", 51.5).SetFloat64("lon", -0.1).Build()).
+		Build()
+	c := NewBuilder("key").SetValue("address", address).Build()
+	fn := func(kind Kind, ref ldattr.Ref, value ldvalue.Value) bool {
+		benchmarkValue = value
+		return true
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Walk(fn)
+	}
+}
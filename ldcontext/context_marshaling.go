@@ -1,7 +1,10 @@
 package ldcontext
 
 import (
+	"strconv"
+
 	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
 
 	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
 )
@@ -29,6 +32,24 @@ func (c Context) MarshalJSON() ([]byte, error) {
 	return w.Bytes(), w.Error()
 }
 
+// CanonicalJSON returns the canonical JSON representation of the Context, as defined by the JSON
+// Canonicalization Scheme (JCS) in RFC 8785.
+//
+// This represents the same properties as [Context.MarshalJSON], but the output is always exactly the
+// same for Contexts that have the same properties, so it is suitable for computing a hash of the
+// Context or for comparing JSON output in tests. Attribute values are formatted as described for
+// [ldvalue.Value.CanonicalJSON]; the order of private attribute references is preserved.
+//
+// If the Context is invalid (that is, it has a non-nil [Context.Err]) then this fails with the same
+// error. It can also fail if an attribute value cannot be represented in canonical JSON.
+func (c Context) CanonicalJSON() ([]byte, error) {
+	data, err := c.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return ldvalue.Raw(data).CanonicalJSON()
+}
+
 func (c *Context) writeToJSONWriterInternalSingle(w *jwriter.Writer, withinKind Kind, usingEventFormat bool) {
 	obj := w.Object()
 	if withinKind == "" {
@@ -79,3 +100,128 @@ func (c Context) writeToJSONWriterInternalMulti(w *jwriter.Writer, usingEventFor
 
 	obj.End()
 }
+
+// EstimatedJSONSize returns the number of bytes in the JSON representation of the Context, as
+// produced by [Context.MarshalJSON], without actually serializing it.
+//
+// The result is exact for the default JSON implementation. It is an estimate in two cases: if the
+// Context contains a value created with [ldvalue.Raw], the raw JSON is counted as-is; and if the
+// easyjson build tag is enabled, easyjson may escape some characters differently.
+//
+// An invalid Context cannot be represented in JSON, so its size is zero.
+func (c Context) EstimatedJSONSize() int {
+	if c.Err() != nil {
+		return 0
+	}
+	if c.multiContexts == nil {
+		return c.estimatedJSONSizeSingle(false)
+	}
+	size := len(`{"kind":"multi"}`)
+	for _, mc := range c.multiContexts {
+		size += 1 + jsonStringSize(string(mc.kind)) + 1 + mc.estimatedJSONSizeSingle(true)
+	}
+	return size
+}
+
+// This must be kept consistent with writeToJSONWriterInternalSingle.
+func (c *Context) estimatedJSONSizeSingle(withinMulti bool) int {
+	size := len(`{"key":}`) + jsonStringSize(c.key)
+	if !withinMulti {
+		size += len(`"kind":,`) + jsonStringSize(string(c.kind))
+	}
+	if c.name.IsDefined() {
+		size += len(`,"name":`) + jsonStringSize(c.name.StringValue())
+	}
+	c.attributes.Range(func(name string, value ldvalue.Value) bool {
+		size += estimatedAttributeJSONSize(name, value)
+		return true
+	})
+	if c.anonymous {
+		size += len(`,"anonymous":true`)
+	}
+	if len(c.privateAttrs) != 0 {
+		size += len(`,"_meta":{"privateAttributes":[]}`) + len(c.privateAttrs) - 1
+		for _, a := range c.privateAttrs {
+			size += jsonStringSize(a.String())
+		}
+	}
+	return size
+}
+
+// estimatedAttributeJSONSize returns the number of bytes that a custom attribute adds to the JSON
+// representation of a single context, including the preceding comma.
+func estimatedAttributeJSONSize(name string, value ldvalue.Value) int {
+	return 1 + jsonStringSize(name) + 1 + estimatedValueJSONSize(value)
+}
+
+func estimatedValueJSONSize(value ldvalue.Value) int {
+	switch value.Type() {
+	case ldvalue.BoolType:
+		if value.BoolValue() {
+			return len("true")
+		}
+		return len("false")
+	case ldvalue.NumberType:
+		return jsonNumberSize(value)
+	case ldvalue.StringType:
+		return jsonStringSize(value.StringValue())
+	case ldvalue.ArrayType:
+		count := value.Count()
+		size := len("[]")
+		if count > 1 {
+			size += count - 1
+		}
+		for i := 0; i < count; i++ {
+			size += estimatedValueJSONSize(value.GetByIndex(i))
+		}
+		return size
+	case ldvalue.ObjectType:
+		m := value.AsValueMap()
+		size := len("{}")
+		if m.Count() > 1 {
+			size += m.Count() - 1
+		}
+		m.Range(func(k string, v ldvalue.Value) bool {
+			size += jsonStringSize(k) + 1 + estimatedValueJSONSize(v)
+			return true
+		})
+		return size
+	case ldvalue.RawType:
+		return len(value.AsRaw())
+	default:
+		return len("null")
+	}
+}
+
+// jsonNumberSize returns the length of a number as formatted by jwriter.
+func jsonNumberSize(n ldvalue.Value) int {
+	var buf [32]byte
+	if n.IsInt() {
+		if i, ok := n.Int64Value(); ok {
+			return len(strconv.AppendInt(buf[:0], i, 10))
+		}
+		u, _ := n.Uint64Value()
+		return len(strconv.AppendUint(buf[:0], u, 10))
+	}
+	// A number created by ldvalue.Number retains its original JSON representation, which is written
+	// as is; we can only detect this by comparing with the default representation.
+	f := n.Float64Value()
+	if text := n.JSONNumber(); string(text) != string(strconv.AppendFloat(buf[:0], f, 'f', -1, 64)) {
+		return len(text)
+	}
+	return len(strconv.AppendFloat(buf[:0], f, 'g', -1, 64))
+}
+
+// jsonStringSize returns the length of a quoted and escaped string as formatted by jwriter.
+func jsonStringSize(s string) int {
+	size := len(s) + 2
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '"', ch == '\\', ch == '\b', ch == '\t', ch == '\n', ch == '\f', ch == '\r':
+			size++
+		case ch < ' ':
+			size += 5
+		}
+	}
+	return size
+}
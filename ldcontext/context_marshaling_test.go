@@ -153,3 +153,56 @@ func TestContextJSONStringIsEquivalentToJSONMarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestContextCanonicalJSON(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		c := NewBuilder("key").Kind("org").Name("n").Anonymous(true).Private("z", "a").
+			SetValue("z", ldvalue.Parse([]byte(`{"y":1.50,"x":[true]}`))).SetString("b", "\u00e9").Build()
+		output, err := c.CanonicalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"_meta":{"privateAttributes":["z","a"]},"anonymous":true,"b":"`+"\u00e9"+`","key":"key",`+
+			`"kind":"org","name":"n","z":{"x":[true],"y":1.5}}`, string(output))
+	})
+
+	t.Run("multi", func(t *testing.T) {
+		c := NewMulti(NewWithKind("org", "b"), NewWithKind("acct", "a"))
+		output, err := c.CanonicalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `{"acct":{"key":"a"},"kind":"multi","org":{"key":"b"}}`, string(output))
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		output, err := New("").CanonicalJSON()
+		assert.Equal(t, lderrors.ErrContextKeyEmpty{}, err)
+		assert.Nil(t, output)
+	})
+}
+
+func TestContextEstimatedJSONSize(t *testing.T) {
+	contexts := []Context{
+		NewBuilder("key").Name("a \"quoted\"\tname\x01").Anonymous(true).Private("x", "/a~1b").
+			SetValue("x", ldvalue.Parse([]byte(`{"a":[1,2.5,-3e20,true,false,null,"\\"],"b":{},"c":[]}`))).
+			SetValue("y", ldvalue.Raw([]byte(`{"raw": true}`))).
+			SetFloat64("z", 0.1).SetString("ü", "é").
+			Build(),
+		NewMulti(NewWithKind("org", "org-key"), NewBuilder("user-key").SetInt("n", 1).Private("n").Build()),
+		NewBuilder("key").SetValue("big", ldvalue.Int64(-9007199254740993)).
+			SetValue("bigger", ldvalue.Uint64(18446744073709551615)).
+			SetValue("lossless", ldvalue.ParseLossless([]byte(`[0.50,1e-7,1e400,0.0000001]`))).
+			SetFloat64("small", 1e-7).
+			Build(),
+	}
+	for _, p := range makeContextMarshalingAndUnmarshalingParams() {
+		contexts = append(contexts, p.context)
+	}
+	for _, c := range contexts {
+		t.Run(c.String(), func(t *testing.T) {
+			bytes, err := c.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, len(bytes), c.EstimatedJSONSize())
+		})
+	}
+
+	assert.Equal(t, 0, New("").EstimatedJSONSize())
+	assert.Equal(t, 0, Context{}.EstimatedJSONSize())
+}
@@ -18,10 +18,14 @@ var ContextSerialization ContextSerializationMethods //nolint:gochecknoglobals
 
 // UnmarshalFromJSONReader unmarshals a Context with the jsonstream Reader API.
 //
+// Since the Reader provides every number as a float64, integer attribute values that cannot be
+// exactly represented as a float64 lose precision when they are read this way; see
+// [ldvalue.Value.ReadFromJSONReader]. [Context.UnmarshalJSON] does not have this limitation.
+//
 // In case of failure, the error is both returned from the method and stored as a failure state in
 // the Reader.
 func (s ContextSerializationMethods) UnmarshalFromJSONReader(r *jreader.Reader, c *Context) error {
-	unmarshalFromJSONReader(r, c, false)
+	unmarshalFromJSONReader(r, c, false, nil, nil)
 	return r.Error()
 }
 
@@ -30,7 +34,7 @@ func (s ContextSerializationMethods) UnmarshalFromJSONReader(r *jreader.Reader,
 // In case of failure, the error is both returned from the method and stored as a failure state in
 // the Reader.
 func (s ContextSerializationMethods) UnmarshalFromJSONReaderEventOutput(r *jreader.Reader, c *EventOutputContext) {
-	unmarshalFromJSONReader(r, &c.Context, true)
+	unmarshalFromJSONReader(r, &c.Context, true, nil, nil)
 }
 
 // UnmarshalWithKindAndKeyOnly is a special unmarshaling mode where all properties except kind and
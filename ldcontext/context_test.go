@@ -3,6 +3,7 @@ package ldcontext
 import (
 	"encoding/json"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
@@ -241,6 +242,14 @@ func TestGetValueForRefCustomAttributeSingleKind(t *testing.T) {
 		expectAttributeFoundForRef(t, expected, c, "/my-attr/my-prop")
 	})
 
+	t.Run("property in lazily indexed raw JSON object", func(t *testing.T) {
+		expected := ldvalue.String("abc")
+		object := ldvalue.LazyRaw(json.RawMessage(`{"other": [1], "my-prop": {"sub-prop": "abc"}}`))
+		c := makeBasicBuilder().SetValue("my-attr", object).Build()
+		expectAttributeFoundForRef(t, expected, c, "/my-attr/my-prop/sub-prop")
+		expectAttributeNotFoundForRef(t, c, "/my-attr/my-prop/other-prop")
+	})
+
 	t.Run("property in object not found", func(t *testing.T) {
 		expected := ldvalue.String("abc")
 		object := ldvalue.ObjectBuild().Set("my-prop", expected).Build()
@@ -511,3 +520,73 @@ func expectAttributeNotFoundForRef(t *testing.T, c Context, attrRefString string
 	assert.False(t, value.IsDefined(), "attribute %q should not have been found, but was", attrRefString)
 	jsonhelpers.AssertEqual(t, `null`, value)
 }
+
+func TestContextTransform(t *testing.T) {
+	upper := func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool) {
+		if value.IsString() {
+			return ldvalue.String(strings.ToUpper(value.StringValue())), true
+		}
+		return value, true
+	}
+
+	t.Run("single context", func(t *testing.T) {
+		c := NewBuilder("my-key").Name("n").SetString("a", "x").SetInt("b", 1).Private("a").Build()
+		c1 := c.Transform(upper)
+		assert.Equal(t, NewBuilder("my-key").Name("N").SetString("a", "X").SetInt("b", 1).Private("a").Build(), c1)
+		assert.Equal(t, ldvalue.String("x"), c.GetValue("a"))
+	})
+
+	t.Run("drop attributes", func(t *testing.T) {
+		c := NewBuilder("my-key").Name("n").SetString("a", "x").SetInt("b", 1).Build()
+		c1 := c.Transform(func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool) {
+			if name == "b" {
+				return ldvalue.Null(), true
+			}
+			return value, name != "name"
+		})
+		assert.Equal(t, NewBuilder("my-key").SetString("a", "x").Build(), c1)
+	})
+
+	t.Run("invalid value for built-in attribute is ignored", func(t *testing.T) {
+		c := NewBuilder("my-key").Name("n").Build()
+		c1 := c.Transform(func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool) {
+			return ldvalue.Int(1), true
+		})
+		assert.Equal(t, c, c1)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		c := NewBuilder("my-key").SetInt("b", 1).Build()
+		assert.Equal(t, c, c.Transform(upper))
+	})
+
+	t.Run("multi-context", func(t *testing.T) {
+		c := NewMulti(
+			NewBuilder("u").SetString("a", "x").Build(),
+			NewBuilder("o").Kind("org").SetString("a", "y").Build(),
+			NewBuilder("d").Kind("device").SetInt("a", 1).Build(),
+		)
+		var kinds []Kind
+		c1 := c.Transform(func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool) {
+			kinds = append(kinds, kind)
+			if kind == "device" {
+				return value, true
+			}
+			return upper(kind, name, value)
+		})
+		assert.ElementsMatch(t, []Kind{"user", "org", "device"}, kinds)
+		assert.Equal(t, NewMulti(
+			NewBuilder("u").SetString("a", "X").Build(),
+			NewBuilder("o").Kind("org").SetString("a", "Y").Build(),
+			NewBuilder("d").Kind("device").SetInt("a", 1).Build(),
+		), c1)
+		assert.Equal(t, c, c.Transform(func(kind Kind, name string, value ldvalue.Value) (ldvalue.Value, bool) {
+			return value, true
+		}))
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		c := New("")
+		assert.Equal(t, c, c.Transform(upper))
+	})
+}
@@ -1,6 +1,8 @@
 package ldcontext
 
 import (
+	"math"
+
 	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
 	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
@@ -36,10 +38,21 @@ func makeInternCommonAttributeNamesMap() map[string]string {
 // a **Context rather than a *Context to json.Unmarshal.
 func (c *Context) UnmarshalJSON(data []byte) error {
 	r := jreader.NewReader(data)
-	return ContextSerialization.UnmarshalFromJSONReader(&r, c)
+	unmarshalFromJSONReader(&r, c, false, nil, &exactNumberSource{data: data})
+	return r.Error()
 }
 
-func unmarshalFromJSONReader(r *jreader.Reader, c *Context, usingEventFormat bool) {
+// The u parameter of the unmarshaling functions below is nil, except when they are called from
+// PooledUnmarshaler; all of its methods have default behavior for a nil receiver. Similarly, the
+// s parameter is nil unless the caller has the JSON data that the Reader is reading from.
+
+func unmarshalFromJSONReader(
+	r *jreader.Reader,
+	c *Context,
+	usingEventFormat bool,
+	u *PooledUnmarshaler,
+	s *exactNumberSource,
+) {
 	// Do a first pass where we just check for the "kind" property, because that determines what
 	// schema we use to parse everything else.
 	kind, hasKind, err := parseKindOnly(r)
@@ -49,11 +62,11 @@ func unmarshalFromJSONReader(r *jreader.Reader, c *Context, usingEventFormat boo
 	}
 	switch {
 	case !hasKind:
-		err = unmarshalOldUserSchema(c, r, usingEventFormat)
+		err = unmarshalOldUserSchema(c, r, usingEventFormat, u, s)
 	case kind == MultiKind:
-		err = unmarshalMultiKind(c, r, usingEventFormat)
+		err = unmarshalMultiKind(c, r, usingEventFormat, u, s)
 	default:
-		err = unmarshalSingleKind(c, r, "", usingEventFormat)
+		err = unmarshalSingleKind(c, r, "", usingEventFormat, u, s)
 	}
 	if err != nil {
 		r.AddError(err)
@@ -91,8 +104,16 @@ func readOptString(r *jreader.Reader) ldvalue.OptionalString {
 	return ldvalue.OptionalString{}
 }
 
-func unmarshalSingleKind(c *Context, r *jreader.Reader, knownKind Kind, usingEventFormat bool) error {
-	var b Builder
+func unmarshalSingleKind(
+	c *Context,
+	r *jreader.Reader,
+	knownKind Kind,
+	usingEventFormat bool,
+	u *PooledUnmarshaler,
+	s *exactNumberSource,
+) error {
+	var localBuilder Builder
+	b := u.builder(&localBuilder)
 	if knownKind != "" {
 		b.Kind(knownKind)
 	}
@@ -124,13 +145,13 @@ func unmarshalSingleKind(c *Context, r *jreader.Reader, knownKind Kind, usingEve
 						_ = r.SkipValue()
 						continue
 					}
-					readPrivateAttributes(r, &b, false)
+					readPrivateAttributes(r, b, false, u)
 				case jsonPropRedacted:
 					if !usingEventFormat {
 						_ = r.SkipValue()
 						continue
 					}
-					readPrivateAttributes(r, &b, false)
+					readPrivateAttributes(r, b, false, u)
 				default:
 					// Unrecognized property names within _meta are ignored. Calling SkipValue makes the Reader
 					// consume and discard the property value so we can advance to the next object property.
@@ -140,7 +161,13 @@ func unmarshalSingleKind(c *Context, r *jreader.Reader, knownKind Kind, usingEve
 		default:
 			var v ldvalue.Value
 			v.ReadFromJSONReader(r)
-			b.SetValue(internAttributeNameIfPossible(obj.Name()), v)
+			name := u.attributeName(obj.Name())
+			if knownKind == "" {
+				v = s.exactValue(v, name)
+			} else {
+				v = s.exactValue(v, string(knownKind), name)
+			}
+			b.SetValue(name, v)
 		}
 	}
 	if r.Error() != nil {
@@ -153,16 +180,23 @@ func unmarshalSingleKind(c *Context, r *jreader.Reader, knownKind Kind, usingEve
 	return c.Err()
 }
 
-func unmarshalMultiKind(c *Context, r *jreader.Reader, usingEventFormat bool) error {
-	var b MultiBuilder
+func unmarshalMultiKind(
+	c *Context,
+	r *jreader.Reader,
+	usingEventFormat bool,
+	u *PooledUnmarshaler,
+	s *exactNumberSource,
+) error {
+	var localBuilder MultiBuilder
+	b := u.multiBuilder(&localBuilder)
 	for obj := r.Object(); obj.Next(); {
-		name := string(obj.Name())
+		name := u.attributeName(obj.Name())
 		if name == ldattr.KindAttr {
 			_ = r.SkipValue()
 			continue
 		}
 		var subContext Context
-		if err := unmarshalSingleKind(&subContext, r, Kind(name), usingEventFormat); err != nil {
+		if err := unmarshalSingleKind(&subContext, r, Kind(name), usingEventFormat, u, s); err != nil {
 			return err
 		}
 		b.Add(subContext)
@@ -171,8 +205,15 @@ func unmarshalMultiKind(c *Context, r *jreader.Reader, usingEventFormat bool) er
 	return c.Err()
 }
 
-func unmarshalOldUserSchema(c *Context, r *jreader.Reader, usingEventFormat bool) error {
-	var b Builder
+func unmarshalOldUserSchema(
+	c *Context,
+	r *jreader.Reader,
+	usingEventFormat bool,
+	u *PooledUnmarshaler,
+	s *exactNumberSource,
+) error {
+	var localBuilder Builder
+	b := u.builder(&localBuilder)
 	b.setAllowEmptyKey(true)
 	var secondary ldvalue.OptionalString
 	hasKey := false
@@ -190,11 +231,11 @@ func unmarshalOldUserSchema(c *Context, r *jreader.Reader, usingEventFormat bool
 			b.Anonymous(value)
 		case jsonPropOldUserCustom:
 			for customObj := r.ObjectOrNull(); customObj.Next(); {
-				name := string(customObj.Name())
+				name := u.attributeName(customObj.Name())
 				var value ldvalue.Value
 				value.ReadFromJSONReader(r)
 				if isOldUserCustomAttributeNameAllowed(name) {
-					b.SetValue(name, value)
+					b.SetValue(name, s.exactValue(value, jsonPropOldUserCustom, name))
 				}
 			}
 		case jsonPropOldUserPrivate:
@@ -202,7 +243,7 @@ func unmarshalOldUserSchema(c *Context, r *jreader.Reader, usingEventFormat bool
 				_ = r.SkipValue()
 				continue
 			}
-			readPrivateAttributes(r, &b, true)
+			readPrivateAttributes(r, b, true, u)
 			// The "true" here means to interpret the strings as literal attribute names, since the
 			// attribute reference path syntax was not used in the old user schema.
 		case jsonPropOldUserRedacted:
@@ -210,10 +251,10 @@ func unmarshalOldUserSchema(c *Context, r *jreader.Reader, usingEventFormat bool
 				_ = r.SkipValue()
 				continue
 			}
-			readPrivateAttributes(r, &b, true)
+			readPrivateAttributes(r, b, true, u)
 		case "firstName", "lastName", "email", "country", "avatar", "ip":
 			if s := readOptString(r); s.IsDefined() {
-				b.SetString(internAttributeNameIfPossible(obj.Name()), s.StringValue())
+				b.SetString(u.attributeName(obj.Name()), s.StringValue())
 			}
 		default:
 			// In the old user schema, unrecognized top-level property names are ignored. Calling SkipValue
@@ -338,9 +379,9 @@ func unmarshalWithKindAndKeyOnlyOldUser(r *jreader.Reader, c *Context) error {
 	return lderrors.ErrContextKeyMissing{}
 }
 
-func readPrivateAttributes(r *jreader.Reader, b *Builder, asLiterals bool) {
+func readPrivateAttributes(r *jreader.Reader, b *Builder, asLiterals bool, u *PooledUnmarshaler) {
 	for privateArr := r.ArrayOrNull(); privateArr.Next(); {
-		b.PrivateRef(refOrLiteralRef(r.String(), asLiterals))
+		b.PrivateRef(u.ref(r.String(), asLiterals))
 	}
 }
 
@@ -350,3 +391,95 @@ func refOrLiteralRef(s string, asLiteral bool) ldattr.Ref {
 	}
 	return ldattr.NewRef(s)
 }
+
+// exactNumberSource provides the original JSON data for a Context that is being unmarshaled.
+//
+// The jsonstream Reader provides every number as a float64, so an integer attribute value such as
+// 9007199254740993 would lose precision if we kept the Value that was read from the Reader; see
+// [ldvalue.Value.ReadFromJSONReader]. When we have the original data, we instead take any value
+// that contains such a number from the result of parsing the same data with [ldvalue.Parse], which
+// reads all numbers exactly. The data is parsed at most once, and only if there is such a value.
+type exactNumberSource struct {
+	data            []byte
+	parsed          ldvalue.Value
+	didParse        bool
+	hasLongIntegers bool
+	didScan         bool
+}
+
+// exactValue returns the value at the given path of object property names within the original
+// data, if v contains any numbers that might not have been read exactly; otherwise, or if s is nil,
+// it returns v.
+func (s *exactNumberSource) exactValue(v ldvalue.Value, path ...string) ldvalue.Value {
+	if s == nil || !s.mightBeInexact(v) {
+		return v
+	}
+	if !s.didParse {
+		s.parsed = ldvalue.Parse(s.data)
+		s.didParse = true
+	}
+	exact := s.parsed
+	for _, name := range path {
+		exact = exact.GetByKey(name)
+	}
+	if exact.IsNull() {
+		// The data was not valid JSON, or it contained duplicate property names that were resolved
+		// differently by the Reader.
+		return v
+	}
+	return exact
+}
+
+// mightBeInexact returns true if v is, or contains, a number that the Reader might not have read
+// exactly. All such numbers are integers.
+func (s *exactNumberSource) mightBeInexact(v ldvalue.Value) bool {
+	switch v.Type() {
+	case ldvalue.NumberType:
+		// Every integer of magnitude less than 2^53 can be represented exactly by a float64, and a
+		// float64 that is not within that range is always an integer. The exception is that the
+		// default Reader implementation wraps integers that are too large for int64 around, rather
+		// than rounding them, so it could have turned one of those into a small number.
+		if math.Abs(v.Float64Value()) >= 1<<53 {
+			return true
+		}
+		return v.IsInt() && s.mightHaveWrappedIntegers()
+	case ldvalue.ArrayType:
+		for i := 0; i < v.Count(); i++ {
+			if s.mightBeInexact(v.GetByIndex(i)) {
+				return true
+			}
+		}
+		return false
+	case ldvalue.ObjectType:
+		found := false
+		v.AsValueMap().Range(func(_ string, value ldvalue.Value) bool {
+			found = s.mightBeInexact(value)
+			return !found
+		})
+		return found
+	default:
+		return false
+	}
+}
+
+// mightHaveWrappedIntegers returns true if the data contains a run of at least 19 digits, which is
+// the shortest integer that is too large for int64. This could also be part of a string or of a
+// non-integer number, in which case we will parse the data unnecessarily, but that is harmless.
+func (s *exactNumberSource) mightHaveWrappedIntegers() bool {
+	if !s.didScan {
+		digits := 0
+		for _, ch := range s.data {
+			if ch < '0' || ch > '9' {
+				digits = 0
+				continue
+			}
+			digits++
+			if digits >= 19 {
+				s.hasLongIntegers = true
+				break
+			}
+		}
+		s.didScan = true
+	}
+	return s.hasLongIntegers
+}
@@ -6,7 +6,8 @@ import (
 
 // BenchmarkJSONUnmarshal uses json.Unmarshal; BenchmarkJSONStreamUnmarshal uses the jsonstream API via
 // ReadFromJSONReader. They both end up calling the same underlying logic, but json.Unmarshal has some
-// extra indirection.
+// extra indirection. BenchmarkJSONStreamUnmarshalPooled uses the same logic via PooledUnmarshaler, which
+// should show fewer allocations than BenchmarkJSONStreamUnmarshal.
 //
 // Unmarshaling via EasyJSON is covered in the conditionally-compiled file context_easyjson_benchmark_test.go.
 
@@ -29,3 +30,8 @@ func BenchmarkJSONUnmarshal(b *testing.B) {
 func BenchmarkJSONStreamUnmarshal(b *testing.B) {
 	doUnmarshalBenchmark(b, jsonStreamUnmarshalTestFn)
 }
+
+func BenchmarkJSONStreamUnmarshalPooled(b *testing.B) {
+	var u PooledUnmarshaler
+	doUnmarshalBenchmark(b, func(c *Context, data []byte) error { return u.Unmarshal(data, c) })
+}
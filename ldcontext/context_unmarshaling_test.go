@@ -379,6 +379,67 @@ func TestContextUnmarshalEventOutputFormat(t *testing.T) {
 	})
 }
 
+func TestContextUnmarshalLargeIntegers(t *testing.T) {
+	big, bigger := ldvalue.Int64(-9007199254740993), ldvalue.Uint64(18446744073709551615)
+	single := NewBuilder("a").SetValue("big", big).SetValue("list", ldvalue.ArrayOf(bigger)).Build()
+	multi := NewMulti(NewWithKind("org", "b"), single)
+	inputs := []struct {
+		name, json string
+		context    Context
+	}{
+		{"single kind", `{"kind": "user", "key": "a", "big": -9007199254740993, "list": [18446744073709551615]}`, single},
+		{"multi-kind", `{"kind": "multi", "org": {"key": "b"},
+			"user": {"key": "a", "big": -9007199254740993, "list": [18446744073709551615]}}`, multi},
+		{"old user schema", `{"key": "a", "custom": {"big": -9007199254740993, "list": [18446744073709551615]}}`, single},
+	}
+	unmarshalers := []struct {
+		name string
+		fn   func(*Context, []byte) error
+	}{
+		{"json.Unmarshal", jsonUnmarshalTestFn},
+		{"PooledUnmarshaler", func(c *Context, data []byte) error {
+			var u PooledUnmarshaler
+			return u.Unmarshal(data, c)
+		}},
+		{"Decoder", func(c *Context, data []byte) error {
+			d := NewNDJSONDecoder(strings.NewReader(strings.ReplaceAll(string(data), "\n", "")))
+			d.Next()
+			*c = d.Context()
+			return d.Err()
+		}},
+	}
+	for _, u := range unmarshalers {
+		t.Run(u.name, func(t *testing.T) {
+			for _, p := range inputs {
+				t.Run(p.name, func(t *testing.T) {
+					var c Context
+					require.NoError(t, u.fn(&c, []byte(p.json)))
+					assert.Equal(t, p.context, c)
+				})
+			}
+		})
+	}
+
+	t.Run("integers that are represented exactly as float64 do not need the original data", func(t *testing.T) {
+		data := []byte(`{"kind": "user", "key": "a", "n": 2, "f": 1.5}`)
+		var c1, c2 Context
+		require.NoError(t, jsonUnmarshalTestFn(&c1, data))
+		require.NoError(t, jsonStreamUnmarshalTestFn(&c2, data))
+		assert.Equal(t, c1, c2)
+		assert.Equal(t, ldvalue.Int(2), c1.GetValue("n"))
+	})
+
+	t.Run("data is not parsed again unless it might contain an inexact number", func(t *testing.T) {
+		s := &exactNumberSource{data: []byte(`{"kind": "user", "key": "a", "n": 2, "id": "123456789012345678"}`)}
+		assert.Equal(t, ldvalue.Int(2), s.exactValue(ldvalue.Int(2), "n"))
+		assert.False(t, s.didParse)
+
+		s = &exactNumberSource{data: []byte(`{"kind": "user", "key": "a", "n": 18446744073709551615}`)}
+		assert.Equal(t, ldvalue.Uint64(18446744073709551615), s.exactValue(ldvalue.Int(-1), "n"))
+		assert.True(t, s.didParse)
+	})
+}
+
 func TestContextReadKindAndKeyOnly(t *testing.T) {
 	t.Run("valid data", func(t *testing.T) {
 		for _, p := range makeAllContextUnmarshalingParams() {
@@ -0,0 +1,87 @@
+package ldcontext
+
+import (
+	"io"
+
+	"github.com/launchdarkly/go-sdk-common/v3/internal/jsonseq"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+)
+
+// Decoder reads JSON-encoded Contexts from a stream one at a time, so that a large number of them
+// can be processed without holding all of them in memory.
+//
+// Each Context is unmarshaled in the same way as [Context.UnmarshalJSON]. A Decoder is used like
+// this:
+//
+//	decoder := ldcontext.NewDecoder(file)
+//	for decoder.Next() {
+//		c := decoder.Context()
+//		// ...
+//	}
+//	if err := decoder.Err(); err != nil {
+//		// ...
+//	}
+//
+// A Decoder is not safe for concurrent use by multiple goroutines.
+type Decoder struct {
+	scanner *jsonseq.Scanner
+	context Context
+	index   int
+	err     error
+}
+
+// NewDecoder creates a Decoder for a stream containing a JSON array, where each array element is a
+// Context.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{scanner: jsonseq.NewArrayScanner(r)}
+}
+
+// NewNDJSONDecoder creates a Decoder for a stream containing newline-delimited JSON, where each line
+// is a Context. Any whitespace between Contexts is allowed, so a JSON object that spans multiple
+// lines is also accepted.
+func NewNDJSONDecoder(r io.Reader) *Decoder {
+	return &Decoder{scanner: jsonseq.NewSequenceScanner(r)}
+}
+
+// Next reads the next Context from the stream. It returns true if successful, or false if the end of
+// the stream has been reached or there was an error; in that case, [Decoder.Err] returns the error.
+//
+// If the stream is not well-formed JSON, the error is a jreader.SyntaxError. If an individual Context
+// could not be unmarshaled, the error is [lderrors.ErrStreamElementInvalid], which contains the
+// underlying unmarshaling error. The Decoder does not continue after an error.
+func (d *Decoder) Next() bool {
+	d.context = Context{}
+	if d.err != nil || !d.scanner.Next() {
+		if d.err == nil {
+			d.err = d.scanner.Err()
+		}
+		return false
+	}
+	data := d.scanner.Bytes()
+	r := jreader.NewReader(data)
+	unmarshalFromJSONReader(&r, &d.context, false, nil, &exactNumberSource{data: data})
+	err := r.Error()
+	if err == nil {
+		err = r.RequireEOF()
+	}
+	if err != nil {
+		d.context = Context{}
+		d.err = lderrors.ErrStreamElementInvalid{Index: d.index, Offset: d.scanner.Offset(), Err: err}
+		return false
+	}
+	d.index++
+	return true
+}
+
+// Context returns the Context that was read by the last successful call to [Decoder.Next].
+func (d *Decoder) Context() Context {
+	return d.context
+}
+
+// Err returns the error, if any, that caused [Decoder.Next] to return false. If the end of the
+// stream was reached normally, it returns nil.
+func (d *Decoder) Err() error {
+	return d.err
+}
@@ -0,0 +1,102 @@
+package ldcontext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAll(d *Decoder) []Context {
+	var ret []Context
+	for d.Next() {
+		ret = append(ret, d.Context())
+	}
+	return ret
+}
+
+func TestDecoderReadsArray(t *testing.T) {
+	params := makeAllContextUnmarshalingParams()
+	jsonParts := make([]string, 0, len(params))
+	expected := make([]Context, 0, len(params))
+	for _, p := range params {
+		jsonParts = append(jsonParts, p.json)
+		expected = append(expected, p.context)
+	}
+	input := "[\n" + strings.Join(jsonParts, ",\n") + "\n]\n"
+
+	d := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+	assert.Equal(t, expected, decodeAll(d))
+	assert.NoError(t, d.Err())
+	assert.False(t, d.Next())
+	assert.Equal(t, Context{}, d.Context())
+}
+
+func TestDecoderReadsNDJSON(t *testing.T) {
+	input := `{"kind": "user", "key": "a"}
+{"kind": "multi", "org": {"key": "b"}, "user": {"key": "c", "name": "x"}}
+{"key": "d", "custom": {"email": "d@example.com"}}
+`
+	d := NewNDJSONDecoder(strings.NewReader(input))
+	assert.Equal(t, []Context{
+		New("a"),
+		NewMulti(NewWithKind("org", "b"), NewBuilder("c").Name("x").Build()),
+		NewBuilder("d").SetString("email", "d@example.com").Build(),
+	}, decodeAll(d))
+	assert.NoError(t, d.Err())
+}
+
+func TestDecoderEmptyStreams(t *testing.T) {
+	d := NewDecoder(strings.NewReader(" [ ] "))
+	assert.False(t, d.Next())
+	assert.NoError(t, d.Err())
+
+	d = NewNDJSONDecoder(strings.NewReader(""))
+	assert.False(t, d.Next())
+	assert.NoError(t, d.Err())
+}
+
+func TestDecoderInvalidContext(t *testing.T) {
+	input := `[{"kind": "user", "key": "a"}, {"kind": "user", "key": ""}, {"kind": "user", "key": "c"}]`
+	d := NewDecoder(strings.NewReader(input))
+	assert.Equal(t, []Context{New("a")}, decodeAll(d))
+	require.IsType(t, lderrors.ErrStreamElementInvalid{}, d.Err())
+	e := d.Err().(lderrors.ErrStreamElementInvalid)
+	assert.Equal(t, 1, e.Index)
+	assert.Equal(t, 31, e.Offset)
+	assert.IsType(t, lderrors.ErrContextKeyEmpty{}, e.Err)
+	assert.Equal(t, Context{}, d.Context())
+	assert.False(t, d.Next())
+}
+
+func TestDecoderMalformedElement(t *testing.T) {
+	d := NewNDJSONDecoder(strings.NewReader("{\"key\": \"a\"}\n{\"key\" \"b\"}\n"))
+	assert.Equal(t, []Context{New("a")}, decodeAll(d))
+	require.IsType(t, lderrors.ErrStreamElementInvalid{}, d.Err())
+	e := d.Err().(lderrors.ErrStreamElementInvalid)
+	assert.Equal(t, 1, e.Index)
+	assert.Equal(t, 13, e.Offset)
+	assert.IsType(t, jreader.SyntaxError{}, e.Err)
+
+	d = NewNDJSONDecoder(strings.NewReader(`{"key": "a"}{"key": "b"}x`))
+	assert.Len(t, decodeAll(d), 2)
+	assert.IsType(t, lderrors.ErrStreamElementInvalid{}, d.Err())
+}
+
+func TestDecoderMalformedStream(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`[{"key": "a"} {"key": "b"}]`))
+	assert.Equal(t, []Context{New("a")}, decodeAll(d))
+	assert.IsType(t, jreader.SyntaxError{}, d.Err())
+
+	fakeError := errors.New("sorry")
+	d = NewDecoder(iotest.ErrReader(fakeError))
+	assert.False(t, d.Next())
+	assert.Equal(t, fakeError, d.Err())
+}
@@ -0,0 +1,107 @@
+package ldcontext
+
+import (
+	"strings"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+)
+
+// ParseFullyQualifiedKey reconstructs a Context from a string in the format produced by
+// [Context.FullyQualifiedKey].
+//
+// Since a fully-qualified key only contains kinds and keys, the result has no other attributes.
+// The format is interpreted as follows:
+//
+//   - If the string does not contain a ':', it is the key of a single Context whose Kind is
+//     [DefaultKind]. The key is used exactly as is, because FullyQualifiedKey does not escape the
+//     keys of such contexts.
+//   - Otherwise, the string consists of one or more "kind:key" pairs separated by ':'. In each key,
+//     "%3A" represents ':' and "%25" represents '%'; any other use of '%' is an error. If there is
+//     one pair, the result is a single Context; otherwise it is a multi-context.
+//
+// Because FullyQualifiedKey does not escape the key of a single Context of [DefaultKind], a key
+// such as "a:b" for such a Context cannot be distinguished from a Context of kind "a" whose key is
+// "b"; ParseFullyQualifiedKey always chooses the latter interpretation.
+//
+// If the string is not in a valid format, the error is [lderrors.ErrContextFullyQualifiedKeyInvalid].
+// If the format is valid but a kind or key is not allowed, the error is the same one that would be
+// reported by [Context.Err] for a Context built with those values. In either case, the returned
+// Context is invalid.
+func ParseFullyQualifiedKey(s string) (Context, error) {
+	if !strings.Contains(s, ":") {
+		c := New(s)
+		return c, c.Err()
+	}
+	var m MultiBuilder
+	count := 0
+	var single Context
+	pos := 0
+	for {
+		kindLength := strings.IndexByte(s[pos:], ':')
+		if kindLength < 0 {
+			return fullyQualifiedKeyError(len(s), `expected ":" after context kind`)
+		}
+		if kindLength == 0 {
+			return fullyQualifiedKeyError(pos, "context kind cannot be empty")
+		}
+		kind := Kind(s[pos : pos+kindLength])
+		pos += kindLength + 1
+		keyLength := strings.IndexByte(s[pos:], ':')
+		if keyLength < 0 {
+			keyLength = len(s) - pos
+		}
+		key, badEscape := unescapeFullyQualifiedKey(s[pos : pos+keyLength])
+		if badEscape >= 0 {
+			return fullyQualifiedKeyError(pos+badEscape, "invalid escape sequence")
+		}
+		single = NewWithKind(kind, key)
+		m.Add(single)
+		count++
+		pos += keyLength
+		if pos == len(s) {
+			break
+		}
+		pos++ // skip the ':' that ends the key
+	}
+	c := single
+	if count > 1 {
+		c = m.Build()
+	}
+	return c, c.Err()
+}
+
+func fullyQualifiedKeyError(pos int, message string) (Context, error) {
+	err := lderrors.ErrContextFullyQualifiedKeyInvalid{Position: pos, Message: message}
+	return Context{defined: true, err: err}, err
+}
+
+// unescapeFullyQualifiedKey reverses the escaping done by makeFullyQualifiedKeySingleKind. If
+// there is a '%' that is not part of a valid escape sequence, it returns its offset; otherwise the
+// second return value is -1.
+func unescapeFullyQualifiedKey(escaped string) (string, int) {
+	if !strings.Contains(escaped, "%") {
+		return escaped, -1
+	}
+	var sb strings.Builder
+	sb.Grow(len(escaped))
+	for i := 0; i < len(escaped); i++ {
+		ch := escaped[i]
+		if ch != '%' {
+			sb.WriteByte(ch)
+			continue
+		}
+		if i+3 > len(escaped) {
+			return "", i
+		}
+		switch escaped[i+1 : i+3] {
+		case "25":
+			sb.WriteByte('%')
+		case "3A", "3a":
+			sb.WriteByte(':')
+		default:
+			return "", i
+		}
+		i += 2
+	}
+	return sb.String(), -1
+}
@@ -0,0 +1,97 @@
+package ldcontext
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFullyQualifiedKey(t *testing.T) {
+	for _, p := range []struct {
+		input    string
+		expected Context
+	}{
+		{"abc", New("abc")},
+		{"a%3Ab%25", New("a%3Ab%25")},
+		{"org:abc", NewWithKind("org", "abc")},
+		{"user:abc", New("abc")},
+		{"org:my%3Akey%25x/y", NewWithKind("org", "my:key%x/y")},
+		{"org:my%3akey", NewWithKind("org", "my:key")},
+		{"org:key1:user:key2", NewMulti(NewWithKind("org", "key1"), New("key2"))},
+		{"user:key2:org:key1", NewMulti(NewWithKind("org", "key1"), New("key2"))},
+		{"a:x%3A:b:%25:c:z", NewMulti(NewWithKind("a", "x:"), NewWithKind("b", "%"), NewWithKind("c", "z"))},
+	} {
+		t.Run(p.input, func(t *testing.T) {
+			c, err := ParseFullyQualifiedKey(p.input)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, c)
+		})
+	}
+}
+
+func TestParseFullyQualifiedKeyRoundTrip(t *testing.T) {
+	for _, c := range []Context{
+		New("abc"),
+		NewWithKind("org", "my:key%x"),
+		NewWithKind("org", "%3A"),
+		NewMulti(NewWithKind("org", "a:b"), New("c:d"), NewWithKind("other", "%")),
+	} {
+		t.Run(c.FullyQualifiedKey(), func(t *testing.T) {
+			parsed, err := ParseFullyQualifiedKey(c.FullyQualifiedKey())
+			require.NoError(t, err)
+			assert.Equal(t, c, parsed)
+		})
+	}
+}
+
+func TestParseFullyQualifiedKeyErrors(t *testing.T) {
+	t.Run("syntax errors", func(t *testing.T) {
+		for _, p := range []struct {
+			input    string
+			position int
+			message  string
+		}{
+			{":abc", 0, "context kind cannot be empty"},
+			{"org:abc:", 8, `expected ":" after context kind`},
+			{"org:abc::key", 8, "context kind cannot be empty"},
+			{"org:abc:user", 12, `expected ":" after context kind`},
+			{"org:a%", 5, "invalid escape sequence"},
+			{"org:a%3", 5, "invalid escape sequence"},
+			{"org:ab%20c", 6, "invalid escape sequence"},
+			{"org:x:user:%25%2", 14, "invalid escape sequence"},
+		} {
+			t.Run(p.input, func(t *testing.T) {
+				c, err := ParseFullyQualifiedKey(p.input)
+				expected := lderrors.ErrContextFullyQualifiedKeyInvalid{Position: p.position, Message: p.message}
+				assert.Equal(t, expected, err)
+				assert.Equal(t, expected, c.Err())
+			})
+		}
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		for _, p := range []struct {
+			input string
+			err   error
+		}{
+			{"", lderrors.ErrContextKeyEmpty{}},
+			{"org:", lderrors.ErrContextKeyEmpty{}},
+			{"kind:abc", lderrors.ErrContextKindCannotBeKind{}},
+			{"multi:abc", lderrors.ErrContextKindMultiForSingleKind{}},
+			{"org:a:org:b", lderrors.ErrContextKindMultiDuplicates{}},
+			{"org:a:user:", lderrors.ErrContextPerKindErrors{
+				Errors: map[string]error{"user": lderrors.ErrContextKeyEmpty{}}}},
+			{"o~rg:a:user:b", lderrors.ErrContextPerKindErrors{
+				Errors: map[string]error{"o~rg": lderrors.ErrContextKindInvalidChars{}}}},
+		} {
+			t.Run(p.input, func(t *testing.T) {
+				c, err := ParseFullyQualifiedKey(p.input)
+				assert.Equal(t, p.err, err)
+				assert.Equal(t, p.err, c.Err())
+			})
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package ldcontext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// KeyPolicy describes optional normalization and validation rules for context keys.
+//
+// By default, context keys are used exactly as they are provided, and are compared byte-for-byte:
+// for instance, "José" with a precomposed "é" and "José" with "e" followed by a combining accent
+// are different keys, even though they look the same. Since the key determines the Context's
+// [Context.FullyQualifiedKey] and its percentage rollout bucketing, applications that obtain keys
+// from inconsistent sources may want to normalize them. Use [Builder.KeyPolicy],
+// [MultiBuilder.KeyPolicy], or [MultiBuilder.KeyPolicyForKind] to apply a policy when building a
+// Context.
+//
+// The normalization options are applied in the following order: TrimSpace, CaseFold, NFC. The
+// zero value KeyPolicy{} leaves keys unchanged.
+type KeyPolicy struct {
+	// TrimSpace removes any leading and trailing white space, as defined by Unicode.
+	TrimSpace bool
+
+	// CaseFold applies Unicode case folding, so that keys that differ only in case become identical.
+	// This is typically only desirable for specific kinds whose keys are known to be case-insensitive,
+	// such as email addresses.
+	CaseFold bool
+
+	// NFC converts the key to Unicode Normalization Form C, in which characters are precomposed
+	// wherever possible.
+	NFC bool
+
+	// Validate makes the Context invalid if the key is not a valid UTF-8 string, or if it contains
+	// any Unicode control characters after the normalization options have been applied. The errors
+	// are [lderrors.ErrContextKeyInvalidUTF8] and [lderrors.ErrContextKeyControlChars].
+	Validate bool
+}
+
+// Apply returns the key as transformed by the policy. If p.Validate is true and the key is not
+// valid, it returns an error instead.
+func (p KeyPolicy) Apply(key string) (string, error) {
+	if p.Validate && !utf8.ValidString(key) {
+		return "", lderrors.ErrContextKeyInvalidUTF8{}
+	}
+	if p.TrimSpace {
+		key = strings.TrimSpace(key)
+	}
+	if p.CaseFold {
+		key = cases.Fold().String(key)
+	}
+	if p.NFC {
+		key = norm.NFC.String(key)
+	}
+	if p.Validate && strings.IndexFunc(key, unicode.IsControl) >= 0 {
+		return "", lderrors.ErrContextKeyControlChars{}
+	}
+	return key, nil
+}
@@ -0,0 +1,146 @@
+package ldcontext
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	keyNFC = "Jos\u00e9"  // precomposed é
+	keyNFD = "Jose\u0301" // e followed by combining acute accent
+)
+
+func TestKeyPolicyApply(t *testing.T) {
+	for _, p := range []struct {
+		name     string
+		policy   KeyPolicy
+		input    string
+		expected string
+	}{
+		{"no options", KeyPolicy{}, " " + keyNFD + "\n", " " + keyNFD + "\n"},
+		{"NFC", KeyPolicy{NFC: true}, keyNFD, keyNFC},
+		{"NFC for already normalized key", KeyPolicy{NFC: true}, keyNFC, keyNFC},
+		{"TrimSpace", KeyPolicy{TrimSpace: true}, " \tkey \n", "key"},
+		{"CaseFold", KeyPolicy{CaseFold: true}, "MiXeD-Straße", "mixed-strasse"},
+		{"all options", KeyPolicy{TrimSpace: true, CaseFold: true, NFC: true, Validate: true}, " JOSÉ ", "josé"},
+		{"Validate for valid key", KeyPolicy{Validate: true}, keyNFD, keyNFD},
+		{"Validate after trimming", KeyPolicy{TrimSpace: true, Validate: true}, "key\n", "key"},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			key, err := p.policy.Apply(p.input)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, key)
+		})
+	}
+
+	t.Run("validation errors", func(t *testing.T) {
+		for _, p := range []struct {
+			input string
+			err   error
+		}{
+			{"a\xffb", lderrors.ErrContextKeyInvalidUTF8{}},
+			{"a\nb", lderrors.ErrContextKeyControlChars{}},
+			{"a\x00", lderrors.ErrContextKeyControlChars{}},
+			{"a\u0085b", lderrors.ErrContextKeyControlChars{}},
+		} {
+			_, err := KeyPolicy{Validate: true}.Apply(p.input)
+			assert.Equal(t, p.err, err, "input: %q", p.input)
+		}
+	})
+
+	t.Run("no validation", func(t *testing.T) {
+		key, err := KeyPolicy{}.Apply("a\xff\nb")
+		require.NoError(t, err)
+		assert.Equal(t, "a\xff\nb", key)
+	})
+}
+
+func TestBuilderKeyPolicy(t *testing.T) {
+	t.Run("normalized key", func(t *testing.T) {
+		c1 := NewBuilder(keyNFD).Kind("org").KeyPolicy(KeyPolicy{NFC: true}).Build()
+		c2 := NewBuilder(keyNFC).Kind("org").KeyPolicy(KeyPolicy{NFC: true}).Build()
+		require.NoError(t, c1.Err())
+		assert.Equal(t, keyNFC, c1.Key())
+		assert.Equal(t, "org:"+keyNFC, c1.FullyQualifiedKey())
+		assert.Equal(t, c2, c1)
+	})
+
+	t.Run("policy can be set before key", func(t *testing.T) {
+		c := NewBuilder("").KeyPolicy(KeyPolicy{CaseFold: true}).Key("ABC").Build()
+		assert.Equal(t, "abc", c.Key())
+	})
+
+	t.Run("builder key is unchanged", func(t *testing.T) {
+		b := NewBuilder("ABC").KeyPolicy(KeyPolicy{CaseFold: true})
+		assert.Equal(t, "abc", b.Build().Key())
+		assert.Equal(t, "ABC", b.KeyPolicy(KeyPolicy{}).Build().Key())
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		c := NewBuilder("a\nb").Kind("org").KeyPolicy(KeyPolicy{Validate: true}).Build()
+		assert.Equal(t, lderrors.ErrContextKeyControlChars{}, c.Err())
+		assert.Equal(t, Kind("org"), c.Kind())
+	})
+
+	t.Run("key is empty after trimming", func(t *testing.T) {
+		c := NewBuilder("  ").KeyPolicy(KeyPolicy{TrimSpace: true}).Build()
+		assert.Equal(t, lderrors.ErrContextKeyEmpty{}, c.Err())
+	})
+}
+
+func TestMultiBuilderKeyPolicy(t *testing.T) {
+	user := New(" User-Key ")
+	org := NewWithKind("org", " Org-Key ")
+	email := NewWithKind("email", " Someone@Example.com ")
+
+	t.Run("policy for all kinds", func(t *testing.T) {
+		c := NewMultiBuilder().Add(user).Add(org).KeyPolicy(KeyPolicy{TrimSpace: true}).Build()
+		require.NoError(t, c.Err())
+		assert.Equal(t, "User-Key", c.IndividualContextByKind("user").Key())
+		assert.Equal(t, "Org-Key", c.IndividualContextByKind("org").Key())
+		assert.Equal(t, "org:Org-Key:user:User-Key", c.FullyQualifiedKey())
+	})
+
+	t.Run("policy for specific kind", func(t *testing.T) {
+		c := NewMultiBuilder().Add(user).Add(org).Add(email).
+			KeyPolicy(KeyPolicy{TrimSpace: true}).
+			KeyPolicyForKind("email", KeyPolicy{TrimSpace: true, CaseFold: true}).
+			KeyPolicyForKind("org", KeyPolicy{}).
+			Build()
+		require.NoError(t, c.Err())
+		assert.Equal(t, "User-Key", c.IndividualContextByKind("user").Key())
+		assert.Equal(t, " Org-Key ", c.IndividualContextByKind("org").Key())
+		assert.Equal(t, "someone@example.com", c.IndividualContextByKind("email").Key())
+	})
+
+	t.Run("single context", func(t *testing.T) {
+		c := NewMultiBuilder().Add(user).KeyPolicy(KeyPolicy{TrimSpace: true}).Build()
+		assert.Equal(t, New("User-Key"), c)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		c := NewMultiBuilder().Add(user).Add(NewWithKind("org", "a\x01")).
+			KeyPolicy(KeyPolicy{Validate: true}).Build()
+		assert.Equal(t, lderrors.ErrContextPerKindErrors{
+			Errors: map[string]error{"org": lderrors.ErrContextKeyControlChars{}},
+		}, c.Err())
+	})
+
+	t.Run("validation error for single context", func(t *testing.T) {
+		c := NewMultiBuilder().Add(NewWithKind("org", "a\xff")).KeyPolicy(KeyPolicy{Validate: true}).Build()
+		assert.Equal(t, lderrors.ErrContextKeyInvalidUTF8{}, c.Err())
+		assert.Equal(t, Kind("org"), c.Kind())
+	})
+
+	t.Run("does not modify previously built context", func(t *testing.T) {
+		b := NewMultiBuilder().Add(user).Add(org)
+		c1 := b.Build()
+		c2 := b.KeyPolicy(KeyPolicy{TrimSpace: true}).Build()
+		assert.Equal(t, " User-Key ", c1.IndividualContextByKind("user").Key())
+		assert.Equal(t, "User-Key", c2.IndividualContextByKind("user").Key())
+	})
+}
@@ -0,0 +1,336 @@
+package ldcontext
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// ContextLimits describes size limits for a Context, to prevent excessively large contexts from
+// being sent to LaunchDarkly.
+//
+// Any limit that is zero or negative is not enforced, so the zero value ContextLimits{} has no
+// effect. Use [ContextLimits.Check] to find out whether a Context exceeds the limits,
+// [ContextLimits.Enforce] to truncate or drop attributes until it does not, or [Builder.Limits]
+// to do either of these automatically when building a Context.
+type ContextLimits struct {
+	// MaxAttributesPerKind is the maximum number of custom attributes in each individual context.
+	// Built-in attributes such as "key" and "name" are not counted.
+	MaxAttributesPerKind int
+
+	// MaxStringLength is the maximum length in bytes of any string value in the "name" attribute or
+	// a custom attribute, including strings within arrays and objects. It does not apply to the
+	// "kind" and "key" attributes, or to property names.
+	MaxStringLength int
+
+	// MaxNestingDepth is the maximum nesting depth of any custom attribute value. A string, number,
+	// boolean, or null value has a depth of zero; an array or object has a depth one greater than
+	// that of the deepest value it contains, or one if it is empty.
+	MaxNestingDepth int
+
+	// MaxJSONSize is the maximum size in bytes of the JSON representation of the Context, as
+	// computed by [Context.EstimatedJSONSize]. For a multi-context, this applies to the whole
+	// multi-context, not to each individual context.
+	MaxJSONSize int
+
+	// Truncate determines what [Builder.Limits] does if the Context exceeds the limits: if true, it
+	// calls [ContextLimits.Enforce] and discards the report, and if false, the Context is invalid
+	// with the error that would be returned by [ContextLimits.Check]. It has no effect on the
+	// Check and Enforce methods.
+	Truncate bool
+}
+
+// LimitAction describes a change that [ContextLimits.Enforce] made to a Context.
+type LimitAction struct {
+	// Kind is the kind of the individual context that was changed.
+	Kind Kind
+	// Attribute refers to the attribute or nested property that was changed.
+	Attribute ldattr.Ref
+	// Dropped is true if the attribute was removed, or false if a string value was truncated.
+	Dropped bool
+	// Reason is the limit violation that caused the change, such as an
+	// [lderrors.ErrContextLimitStringTooLong].
+	Reason error
+}
+
+// Check returns nil if the Context is within the limits, or an [lderrors.ErrContextLimitViolations]
+// describing every way in which it exceeds them. If the Context is invalid, it returns the same
+// error as [Context.Err].
+//
+// For each individual context, the errors are reported in the following order: too many attributes,
+// a string value of "name" that is too long, and then for each custom attribute in alphabetical
+// order, its nesting depth and then any string values that are too long. If the JSON size is too
+// large, that error is last.
+func (l ContextLimits) Check(c Context) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	var errs []error
+	for _, sc := range individualContexts(c) {
+		errs = l.checkSingleKind(sc, errs)
+	}
+	if l.MaxJSONSize > 0 {
+		if size := c.EstimatedJSONSize(); size > l.MaxJSONSize {
+			errs = append(errs, lderrors.ErrContextLimitTooLarge{Size: size, Limit: l.MaxJSONSize})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return lderrors.ErrContextLimitViolations{Errors: errs}
+}
+
+// Enforce returns a copy of the Context modified to be within the limits, along with a list of the
+// changes that were made. If the Context was already within the limits, it is returned unchanged
+// and the list is empty.
+//
+// The changes are made as follows, for each individual context:
+//
+//   - If there are more than MaxAttributesPerKind custom attributes, only that many are kept, in
+//     alphabetical order by name, and the rest are dropped.
+//   - Any custom attribute whose value is nested more deeply than MaxNestingDepth is dropped.
+//   - Any string value longer than MaxStringLength is truncated to that length, or slightly less if
+//     necessary to avoid splitting a multi-byte UTF-8 character.
+//
+// Then, while the JSON size is greater than MaxJSONSize, the custom attribute that contributes the
+// most to the size is dropped. If the Context is still too large after all of its custom attributes
+// have been dropped, Enforce returns an [lderrors.ErrContextLimitTooLarge] error along with the
+// reduced Context.
+//
+// Dropping an attribute does not affect any private attribute references to it.
+//
+// If the Context is invalid, it is returned unchanged along with the same error as [Context.Err].
+func (l ContextLimits) Enforce(c Context) (Context, []LimitAction, error) {
+	if err := c.Err(); err != nil {
+		return c, nil, err
+	}
+	contexts := individualContexts(c)
+	builders := make([]*Builder, len(contexts))
+	var actions []LimitAction
+	for i, sc := range contexts {
+		builders[i], actions = l.enforceSingleKind(sc, actions)
+	}
+	buildResult := func() Context {
+		if !c.Multiple() {
+			return builders[0].Build()
+		}
+		mb := NewMultiBuilder()
+		for _, b := range builders {
+			mb.Add(b.Build())
+		}
+		return mb.Build()
+	}
+	if len(actions) != 0 {
+		c = buildResult()
+	}
+	if l.MaxJSONSize <= 0 {
+		return c, actions, nil
+	}
+	size := c.EstimatedJSONSize()
+	if size <= l.MaxJSONSize {
+		return c, actions, nil
+	}
+	for size > l.MaxJSONSize {
+		// Find the custom attribute that contributes the most to the size. Each builder's attributes
+		// are still the same as those in the corresponding individual context.
+		largestIndex, largestName, largestSize := -1, "", 0
+		for i, b := range builders {
+			for _, name := range sortedAttributeNames(b.attributes.Build()) {
+				if attrSize := estimatedAttributeJSONSize(name, b.attributes.Get(name)); attrSize > largestSize {
+					largestIndex, largestName, largestSize = i, name, attrSize
+				}
+			}
+		}
+		if largestIndex < 0 {
+			return buildResult(), actions, lderrors.ErrContextLimitTooLarge{Size: size, Limit: l.MaxJSONSize}
+		}
+		b := builders[largestIndex]
+		actions = append(actions, LimitAction{
+			Kind:      b.kind,
+			Attribute: ldattr.NewLiteralRef(largestName),
+			Dropped:   true,
+			Reason:    lderrors.ErrContextLimitTooLarge{Size: size, Limit: l.MaxJSONSize},
+		})
+		b.attributes.Remove(largestName)
+		size -= largestSize
+	}
+	return buildResult(), actions, nil
+}
+
+func (l ContextLimits) applyToBuild(c Context) Context {
+	var err error
+	if l.Truncate {
+		c, _, err = l.Enforce(c)
+	} else {
+		err = l.Check(c)
+	}
+	if err != nil {
+		return Context{defined: true, err: err, kind: c.kind}
+	}
+	return c
+}
+
+func (l ContextLimits) checkSingleKind(c Context, errs []error) []error {
+	names := sortedAttributeNames(c.attributes)
+	if l.MaxAttributesPerKind > 0 && len(names) > l.MaxAttributesPerKind {
+		errs = append(errs, lderrors.ErrContextLimitTooManyAttributes{Kind: string(c.kind), Count: len(names),
+			Limit: l.MaxAttributesPerKind})
+	}
+	if l.MaxStringLength > 0 && len(c.name.StringValue()) > l.MaxStringLength {
+		errs = append(errs, l.stringTooLong(c.kind, ldattr.NameAttr, c.name.StringValue()))
+	}
+	for _, name := range names {
+		errs = l.checkAttribute(c.kind, name, c.attributes.Get(name), errs)
+	}
+	return errs
+}
+
+func (l ContextLimits) checkAttribute(kind Kind, name string, value ldvalue.Value, errs []error) []error {
+	if l.MaxNestingDepth > 0 {
+		if depth := valueNestingDepth(value); depth > l.MaxNestingDepth {
+			errs = append(errs, lderrors.ErrContextLimitTooDeep{Kind: string(kind),
+				Attribute: ldattr.NewLiteralRef(name).String(), Depth: depth, Limit: l.MaxNestingDepth})
+		}
+	}
+	if l.MaxStringLength > 0 {
+		errs = l.findLongStrings(kind, []string{name}, value, errs)
+	}
+	return errs
+}
+
+func (l ContextLimits) findLongStrings(kind Kind, path []string, value ldvalue.Value, errs []error) []error {
+	value = parseIfRaw(value)
+	switch value.Type() {
+	case ldvalue.StringType:
+		if len(value.StringValue()) > l.MaxStringLength {
+			errs = append(errs, l.stringTooLong(kind, ldattr.NewRefFromComponents(path).String(), value.StringValue()))
+		}
+	case ldvalue.ArrayType:
+		for i := 0; i < value.Count(); i++ {
+			errs = l.findLongStrings(kind, path, value.GetByIndex(i), errs)
+		}
+	case ldvalue.ObjectType:
+		m := value.AsValueMap()
+		for _, k := range sortedAttributeNames(m) {
+			if k != "" {
+				errs = l.findLongStrings(kind, append(path[:len(path):len(path)], k), m.Get(k), errs)
+			}
+		}
+	}
+	return errs
+}
+
+func (l ContextLimits) stringTooLong(kind Kind, attribute, s string) error {
+	return lderrors.ErrContextLimitStringTooLong{Kind: string(kind), Attribute: attribute, Length: len(s),
+		Limit: l.MaxStringLength}
+}
+
+func (l ContextLimits) enforceSingleKind(c Context, actions []LimitAction) (*Builder, []LimitAction) {
+	b := NewBuilderFromContext(c)
+	names := sortedAttributeNames(c.attributes)
+	if l.MaxAttributesPerKind > 0 && len(names) > l.MaxAttributesPerKind {
+		reason := lderrors.ErrContextLimitTooManyAttributes{Kind: string(c.kind), Count: len(names),
+			Limit: l.MaxAttributesPerKind}
+		for _, name := range names[l.MaxAttributesPerKind:] {
+			b.attributes.Remove(name)
+			actions = append(actions, LimitAction{Kind: c.kind, Attribute: ldattr.NewLiteralRef(name),
+				Dropped: true, Reason: reason})
+		}
+		names = names[:l.MaxAttributesPerKind]
+	}
+	if l.MaxStringLength > 0 && len(c.name.StringValue()) > l.MaxStringLength {
+		b.Name(truncateUTF8(c.name.StringValue(), l.MaxStringLength))
+		actions = append(actions, LimitAction{Kind: c.kind, Attribute: ldattr.NewLiteralRef(ldattr.NameAttr),
+			Reason: l.stringTooLong(c.kind, ldattr.NameAttr, c.name.StringValue())})
+	}
+	for _, name := range names {
+		value := c.attributes.Get(name)
+		errs := l.checkAttribute(c.kind, name, value, nil)
+		if len(errs) == 0 {
+			continue
+		}
+		if tooDeep, ok := errs[0].(lderrors.ErrContextLimitTooDeep); ok {
+			b.attributes.Remove(name)
+			actions = append(actions, LimitAction{Kind: c.kind, Attribute: ldattr.NewLiteralRef(name),
+				Dropped: true, Reason: tooDeep})
+			continue
+		}
+		b.attributes.Set(name, truncateStrings(value, l.MaxStringLength))
+		for _, err := range errs {
+			actions = append(actions, LimitAction{Kind: c.kind,
+				Attribute: ldattr.NewRef(err.(lderrors.ErrContextLimitStringTooLong).Attribute), Reason: err})
+		}
+	}
+	return b, actions
+}
+
+func individualContexts(c Context) []Context {
+	if c.multiContexts != nil {
+		return c.multiContexts
+	}
+	return []Context{c}
+}
+
+func sortedAttributeNames(m ldvalue.ValueMap) []string {
+	names := m.Keys(nil)
+	sort.Strings(names)
+	return names
+}
+
+func parseIfRaw(value ldvalue.Value) ldvalue.Value {
+	if value.Type() == ldvalue.RawType {
+		return ldvalue.Parse(value.AsRaw())
+	}
+	return value
+}
+
+func valueNestingDepth(value ldvalue.Value) int {
+	value = parseIfRaw(value)
+	maxChildDepth := 0
+	switch value.Type() {
+	case ldvalue.ArrayType:
+		for i := 0; i < value.Count(); i++ {
+			if d := valueNestingDepth(value.GetByIndex(i)); d > maxChildDepth {
+				maxChildDepth = d
+			}
+		}
+	case ldvalue.ObjectType:
+		value.AsValueMap().Range(func(_ string, v ldvalue.Value) bool {
+			if d := valueNestingDepth(v); d > maxChildDepth {
+				maxChildDepth = d
+			}
+			return true
+		})
+	default:
+		return 0
+	}
+	return maxChildDepth + 1
+}
+
+func truncateStrings(value ldvalue.Value, maxLength int) ldvalue.Value {
+	value = parseIfRaw(value)
+	switch value.Type() {
+	case ldvalue.StringType:
+		return ldvalue.String(truncateUTF8(value.StringValue(), maxLength))
+	case ldvalue.ArrayType, ldvalue.ObjectType:
+		return value.Transform(func(_ int, _ string, v ldvalue.Value) (ldvalue.Value, bool) {
+			return truncateStrings(v, maxLength), true
+		})
+	default:
+		return value
+	}
+}
+
+func truncateUTF8(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
@@ -0,0 +1,195 @@
+package ldcontext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-test-helpers/v3/jsonhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextLimitsCheck(t *testing.T) {
+	c := NewBuilder("key").Name("long name").
+		SetString("a", "short").
+		SetValue("b", ldvalue.Parse([]byte(`{"x":{"y":["long string"]},"z":"long string"}`))).
+		SetString("c", "long string").
+		Build()
+
+	t.Run("no limits", func(t *testing.T) {
+		assert.NoError(t, ContextLimits{}.Check(c))
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		limits := ContextLimits{MaxAttributesPerKind: 3, MaxStringLength: 11, MaxNestingDepth: 3,
+			MaxJSONSize: c.EstimatedJSONSize()}
+		assert.NoError(t, limits.Check(c))
+	})
+
+	t.Run("too many attributes", func(t *testing.T) {
+		assert.Equal(t, lderrors.ErrContextLimitViolations{Errors: []error{
+			lderrors.ErrContextLimitTooManyAttributes{Kind: "user", Count: 3, Limit: 2},
+		}}, ContextLimits{MaxAttributesPerKind: 2}.Check(c))
+	})
+
+	t.Run("string too long", func(t *testing.T) {
+		assert.Equal(t, lderrors.ErrContextLimitViolations{Errors: []error{
+			lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "name", Length: 9, Limit: 5},
+			lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "/b/x/y", Length: 11, Limit: 5},
+			lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "/b/z", Length: 11, Limit: 5},
+			lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "c", Length: 11, Limit: 5},
+		}}, ContextLimits{MaxStringLength: 5}.Check(c))
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		assert.Equal(t, lderrors.ErrContextLimitViolations{Errors: []error{
+			lderrors.ErrContextLimitTooDeep{Kind: "user", Attribute: "b", Depth: 3, Limit: 2},
+		}}, ContextLimits{MaxNestingDepth: 2}.Check(c))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		size := c.EstimatedJSONSize()
+		assert.Equal(t, lderrors.ErrContextLimitViolations{Errors: []error{
+			lderrors.ErrContextLimitTooLarge{Size: size, Limit: size - 1},
+		}}, ContextLimits{MaxJSONSize: size - 1}.Check(c))
+	})
+
+	t.Run("multi-context", func(t *testing.T) {
+		mc := NewMulti(NewBuilder("org-key").Kind("org").SetString("a", "long string").Build(), c)
+		err := ContextLimits{MaxAttributesPerKind: 2, MaxJSONSize: 10}.Check(mc)
+		assert.Equal(t, lderrors.ErrContextLimitViolations{Errors: []error{
+			lderrors.ErrContextLimitTooManyAttributes{Kind: "user", Count: 3, Limit: 2},
+			lderrors.ErrContextLimitTooLarge{Size: mc.EstimatedJSONSize(), Limit: 10},
+		}}, err)
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		assert.Equal(t, lderrors.ErrContextKeyEmpty{}, ContextLimits{MaxJSONSize: 10}.Check(New("")))
+	})
+}
+
+func TestContextLimitsEnforce(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		c := NewBuilder("key").SetString("a", "b").Build()
+		result, actions, err := ContextLimits{MaxStringLength: 10, MaxJSONSize: 1000}.Enforce(c)
+		require.NoError(t, err)
+		assert.Equal(t, c, result)
+		assert.Len(t, actions, 0)
+	})
+
+	t.Run("too many attributes", func(t *testing.T) {
+		c := NewBuilder("key").SetInt("c", 3).SetInt("a", 1).SetInt("b", 2).Build()
+		result, actions, err := ContextLimits{MaxAttributesPerKind: 2}.Enforce(c)
+		require.NoError(t, err)
+		assert.Equal(t, NewBuilder("key").SetInt("a", 1).SetInt("b", 2).Build(), result)
+		assert.Equal(t, []LimitAction{
+			{Kind: "user", Attribute: ldattr.NewRef("c"), Dropped: true,
+				Reason: lderrors.ErrContextLimitTooManyAttributes{Kind: "user", Count: 3, Limit: 2}},
+		}, actions)
+	})
+
+	t.Run("strings truncated", func(t *testing.T) {
+		c := NewBuilder("key").Name("long name").
+			SetValue("b", ldvalue.Raw([]byte(`{"x":["long string", 1],"y":"ok"}`))).
+			SetString("c", "mañana").
+			Build()
+		result, actions, err := ContextLimits{MaxStringLength: 4}.Enforce(c)
+		require.NoError(t, err)
+		assert.Equal(t, "long", result.Name().StringValue())
+		jsonhelpers.AssertEqual(t, `{"x":["long", 1],"y":"ok"}`, result.GetValue("b"))
+		assert.Equal(t, ldvalue.String("mañ"), result.GetValue("c"))
+		assert.Equal(t, []LimitAction{
+			{Kind: "user", Attribute: ldattr.NewRef("name"),
+				Reason: lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "name", Length: 9, Limit: 4}},
+			{Kind: "user", Attribute: ldattr.NewRef("/b/x"),
+				Reason: lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "/b/x", Length: 11, Limit: 4}},
+			{Kind: "user", Attribute: ldattr.NewRef("c"),
+				Reason: lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "c", Length: 7, Limit: 4}},
+		}, actions)
+	})
+
+	t.Run("truncation does not split multi-byte characters", func(t *testing.T) {
+		c := NewBuilder("key").SetString("a", "mañana").Build()
+		result, _, err := ContextLimits{MaxStringLength: 3}.Enforce(c)
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.String("ma"), result.GetValue("a"))
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		c := NewBuilder("key").SetValue("a", ldvalue.Parse([]byte(`[[1]]`))).SetString("b", "x").Build()
+		result, actions, err := ContextLimits{MaxNestingDepth: 1}.Enforce(c)
+		require.NoError(t, err)
+		assert.Equal(t, NewBuilder("key").SetString("b", "x").Build(), result)
+		assert.Equal(t, []LimitAction{
+			{Kind: "user", Attribute: ldattr.NewRef("a"), Dropped: true,
+				Reason: lderrors.ErrContextLimitTooDeep{Kind: "user", Attribute: "a", Depth: 2, Limit: 1}},
+		}, actions)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		org := NewBuilder("org-key").Kind("org").SetString("small", "x").Build()
+		user := NewBuilder("user-key").SetString("large", strings.Repeat("x", 100)).SetString("small", "x").Build()
+		c := NewMulti(org, user)
+		size := c.EstimatedJSONSize()
+		result, actions, err := ContextLimits{MaxJSONSize: size - 50}.Enforce(c)
+		require.NoError(t, err)
+		assert.Equal(t, NewMulti(org, NewBuilder("user-key").SetString("small", "x").Build()), result)
+		assert.LessOrEqual(t, result.EstimatedJSONSize(), size-50)
+		assert.Equal(t, []LimitAction{
+			{Kind: "user", Attribute: ldattr.NewRef("large"), Dropped: true,
+				Reason: lderrors.ErrContextLimitTooLarge{Size: size, Limit: size - 50}},
+		}, actions)
+	})
+
+	t.Run("cannot be made small enough", func(t *testing.T) {
+		c := NewBuilder(strings.Repeat("x", 100)).SetString("a", "b").Build()
+		result, actions, err := ContextLimits{MaxJSONSize: 50}.Enforce(c)
+		assert.Equal(t, lderrors.ErrContextLimitTooLarge{Size: result.EstimatedJSONSize(), Limit: 50}, err)
+		assert.Equal(t, New(strings.Repeat("x", 100)), result)
+		assert.Len(t, actions, 1)
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		c := New("")
+		result, actions, err := ContextLimits{MaxJSONSize: 10}.Enforce(c)
+		assert.Equal(t, lderrors.ErrContextKeyEmpty{}, err)
+		assert.Equal(t, c, result)
+		assert.Nil(t, actions)
+	})
+}
+
+func TestBuilderLimits(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		c := NewBuilder("key").Limits(ContextLimits{MaxStringLength: 3}).SetString("a", "long").Build()
+		assert.Equal(t, lderrors.ErrContextLimitViolations{Errors: []error{
+			lderrors.ErrContextLimitStringTooLong{Kind: "user", Attribute: "a", Length: 4, Limit: 3},
+		}}, c.Err())
+		assert.Equal(t, Kind("user"), c.Kind())
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		c := NewBuilder("key").Limits(ContextLimits{MaxStringLength: 3, Truncate: true}).SetString("a", "long").Build()
+		assert.NoError(t, c.Err())
+		assert.Equal(t, ldvalue.String("lon"), c.GetValue("a"))
+	})
+
+	t.Run("truncate cannot be made small enough", func(t *testing.T) {
+		c := NewBuilder("key").Limits(ContextLimits{MaxJSONSize: 5, Truncate: true}).Build()
+		assert.Equal(t, lderrors.ErrContextLimitTooLarge{Size: New("key").EstimatedJSONSize(), Limit: 5}, c.Err())
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		c := NewBuilder("key").Limits(ContextLimits{MaxStringLength: 3}).SetString("a", "abc").Build()
+		assert.Equal(t, NewBuilder("key").SetString("a", "abc").Build(), c)
+	})
+
+	t.Run("removing limits", func(t *testing.T) {
+		c := NewBuilder("key").Limits(ContextLimits{MaxStringLength: 3}).Limits(ContextLimits{}).
+			SetString("a", "long").Build()
+		assert.NoError(t, c.Err())
+	})
+}
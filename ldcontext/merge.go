@@ -0,0 +1,145 @@
+package ldcontext
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// MergePolicy determines how [Merge] resolves an attribute that is present in both Contexts.
+//
+// The policies can be combined with the | operator. For instance, MergeDeepObjects|MergeConcatArrays
+// merges objects recursively and also concatenates arrays, and MergeBaseWins|MergeDeepObjects merges
+// objects recursively but keeps the base value for any other conflict within them.
+type MergePolicy uint
+
+const (
+	// MergeOverlayWins means that when an attribute is present in both Contexts, the value from the
+	// overlay Context is used. This is the default if no other policy is specified.
+	MergeOverlayWins MergePolicy = 0
+
+	// MergeBaseWins means that when an attribute is present in both Contexts, the value from the base
+	// Context is used.
+	MergeBaseWins MergePolicy = 1 << iota
+
+	// MergeDeepObjects means that when an attribute has a JSON object value in both Contexts, the two
+	// objects are merged recursively, applying the same policy to each property that is present in
+	// both. Conflicts between values that are not both objects are resolved by MergeOverlayWins or
+	// MergeBaseWins.
+	MergeDeepObjects
+
+	// MergeConcatArrays means that when an attribute has a JSON array value in both Contexts, the
+	// result is the base array followed by the overlay array. Conflicts between values that are not
+	// both arrays are resolved by MergeOverlayWins or MergeBaseWins.
+	MergeConcatArrays
+)
+
+// Merge combines the attributes of two Contexts.
+//
+// For each kind that is present in both Contexts, the individual contexts of that kind are merged
+// attribute by attribute: an attribute that is present in only one of them is copied as-is, and an
+// attribute that is present in both is resolved according to the [MergePolicy]. The built-in "key",
+// "name", and "anonymous" attributes are resolved in the same way as custom attributes, except that
+// they are never merged as objects or arrays. The private attributes of the result are the union of
+// the private attributes of both contexts (see [Builder.Private]).
+//
+// A kind that is present in only one of the Contexts is copied to the result unchanged. Therefore,
+// merging two single contexts of different kinds, or merging multi-contexts with different sets of
+// kinds, produces a multi-context containing all of the kinds.
+//
+//	serverUser := ldcontext.NewBuilder("user-key").SetString("plan", "pro").Build()
+//	clientUser := ldcontext.NewBuilder("user-key").SetString("device", "mobile").Build()
+//	merged, err := ldcontext.Merge(serverUser, clientUser, ldcontext.MergeBaseWins)
+//
+// If either Context is invalid, Merge returns that Context's error (see [Context.Err]).
+func Merge(base, overlay Context, policy MergePolicy) (Context, error) {
+	if err := base.Err(); err != nil {
+		return Context{}, err
+	}
+	if err := overlay.Err(); err != nil {
+		return Context{}, err
+	}
+	if !base.Multiple() && !overlay.Multiple() && base.kind == overlay.kind {
+		return mergeSingleKind(base, overlay, policy).TryBuild()
+	}
+	mb := NewMultiBuilder()
+	for i := 0; i < base.IndividualContextCount(); i++ {
+		bc := base.IndividualContextByIndex(i)
+		if oc := overlay.IndividualContextByKind(bc.kind); oc.IsDefined() {
+			mb.Add(mergeSingleKind(bc, oc, policy).Build())
+		} else {
+			mb.Add(bc)
+		}
+	}
+	for i := 0; i < overlay.IndividualContextCount(); i++ {
+		oc := overlay.IndividualContextByIndex(i)
+		if !base.IndividualContextByKind(oc.kind).IsDefined() {
+			mb.Add(oc)
+		}
+	}
+	return mb.TryBuild()
+}
+
+func mergeSingleKind(base, overlay Context, policy MergePolicy) *Builder {
+	b := NewBuilderFromContext(base)
+	baseWins := policy&MergeBaseWins != 0
+	if !baseWins {
+		b.Key(overlay.key)
+		b.Anonymous(overlay.anonymous)
+		if overlay.name.IsDefined() {
+			b.OptName(overlay.name)
+		}
+	} else if !base.name.IsDefined() {
+		b.OptName(overlay.name)
+	}
+	for _, name := range overlay.attributes.Keys(nil) {
+		overlayValue := overlay.attributes.Get(name)
+		if baseValue, ok := base.attributes.TryGet(name); ok {
+			b.SetValue(name, mergeValues(baseValue, overlayValue, policy))
+		} else {
+			b.SetValue(name, overlayValue)
+		}
+	}
+	for _, ref := range overlay.privateAttrs {
+		found := false
+		for _, existing := range base.privateAttrs {
+			if existing.String() == ref.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b.PrivateRef(ref)
+		}
+	}
+	return b
+}
+
+func mergeValues(base, overlay ldvalue.Value, policy MergePolicy) ldvalue.Value {
+	if base.Type() == ldvalue.RawType {
+		base = ldvalue.Parse(base.AsRaw())
+	}
+	if overlay.Type() == ldvalue.RawType {
+		overlay = ldvalue.Parse(overlay.AsRaw())
+	}
+	switch {
+	case policy&MergeDeepObjects != 0 && base.Type() == ldvalue.ObjectType && overlay.Type() == ldvalue.ObjectType:
+		baseMap := base.AsValueMap()
+		mb := ldvalue.ValueMapBuildFromMap(baseMap)
+		for _, key := range overlay.Keys(nil) {
+			overlayItem := overlay.GetByKey(key)
+			if baseItem, ok := baseMap.TryGet(key); ok {
+				mb.Set(key, mergeValues(baseItem, overlayItem, policy))
+			} else {
+				mb.Set(key, overlayItem)
+			}
+		}
+		return mb.Build().AsValue()
+	case policy&MergeConcatArrays != 0 && base.Type() == ldvalue.ArrayType && overlay.Type() == ldvalue.ArrayType:
+		return ldvalue.ValueArrayBuildFromArray(base.AsValueArray()).
+			AddAllFromValueArray(overlay.AsValueArray()).
+			Build().AsValue()
+	case policy&MergeBaseWins != 0:
+		return base
+	default:
+		return overlay
+	}
+}
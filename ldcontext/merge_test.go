@@ -0,0 +1,118 @@
+package ldcontext
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertContextsEqual(t *testing.T, expected, actual Context) {
+	t.Helper()
+	assert.True(t, expected.Equal(actual), "expected %s, got %s", expected, actual)
+}
+
+func TestMergeSingleContexts(t *testing.T) {
+	base := NewBuilder("base-key").Name("base-name").
+		SetString("plan", "pro").
+		SetValue("address", ldvalue.Parse([]byte(`{"city":"a","zip":"1"}`))).
+		SetValue("tags", ldvalue.ArrayOf(ldvalue.String("x"))).
+		Private("plan").
+		Build()
+	overlay := NewBuilder("overlay-key").Anonymous(true).
+		SetString("plan", "free").
+		SetString("device", "mobile").
+		SetValue("address", ldvalue.Parse([]byte(`{"city":"b","street":"s"}`))).
+		SetValue("tags", ldvalue.ArrayOf(ldvalue.String("y"))).
+		Private("device", "plan").
+		Build()
+
+	t.Run("overlay wins", func(t *testing.T) {
+		c, err := Merge(base, overlay, MergeOverlayWins)
+		require.NoError(t, err)
+		assertContextsEqual(t, NewBuilder("overlay-key").Name("base-name").Anonymous(true).
+			SetString("plan", "free").
+			SetString("device", "mobile").
+			SetValue("address", ldvalue.Parse([]byte(`{"city":"b","street":"s"}`))).
+			SetValue("tags", ldvalue.ArrayOf(ldvalue.String("y"))).
+			Private("plan", "device").
+			Build(), c)
+	})
+
+	t.Run("base wins", func(t *testing.T) {
+		c, err := Merge(base, overlay, MergeBaseWins)
+		require.NoError(t, err)
+		assertContextsEqual(t, NewBuilder("base-key").Name("base-name").
+			SetString("plan", "pro").
+			SetString("device", "mobile").
+			SetValue("address", ldvalue.Parse([]byte(`{"city":"a","zip":"1"}`))).
+			SetValue("tags", ldvalue.ArrayOf(ldvalue.String("x"))).
+			Private("plan", "device").
+			Build(), c)
+	})
+
+	t.Run("deep merge objects", func(t *testing.T) {
+		c, err := Merge(base, overlay, MergeDeepObjects)
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.Parse([]byte(`{"city":"b","zip":"1","street":"s"}`)), c.GetValue("address"))
+		assert.Equal(t, ldvalue.ArrayOf(ldvalue.String("y")), c.GetValue("tags"))
+
+		c, err = Merge(base, overlay, MergeDeepObjects|MergeBaseWins)
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.Parse([]byte(`{"city":"a","zip":"1","street":"s"}`)), c.GetValue("address"))
+	})
+
+	t.Run("concatenate arrays", func(t *testing.T) {
+		c, err := Merge(base, overlay, MergeConcatArrays)
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.ArrayOf(ldvalue.String("x"), ldvalue.String("y")), c.GetValue("tags"))
+		assert.Equal(t, ldvalue.Parse([]byte(`{"city":"b","street":"s"}`)), c.GetValue("address"))
+		assert.Equal(t, ldvalue.String("free"), c.GetValue("plan"))
+	})
+
+	t.Run("name from overlay when base has none", func(t *testing.T) {
+		c, err := Merge(New("a"), NewBuilder("a").Name("n").Build(), MergeBaseWins)
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.NewOptionalString("n"), c.Name())
+	})
+}
+
+func TestMergeDifferentKinds(t *testing.T) {
+	user := NewBuilder("u").SetString("a", "1").Build()
+	org := NewWithKind("org", "o")
+	c, err := Merge(user, org, MergeOverlayWins)
+	require.NoError(t, err)
+	assertContextsEqual(t, NewMulti(user, org), c)
+}
+
+func TestMergeMultiContexts(t *testing.T) {
+	base := NewMulti(
+		NewBuilder("u").SetString("a", "1").Build(),
+		NewWithKind("org", "o"),
+	)
+	overlay := NewMulti(
+		NewBuilder("u").SetString("b", "2").Build(),
+		NewWithKind("device", "d"),
+	)
+	c, err := Merge(base, overlay, MergeOverlayWins)
+	require.NoError(t, err)
+	assertContextsEqual(t, NewMulti(
+		NewBuilder("u").SetString("a", "1").SetString("b", "2").Build(),
+		NewWithKind("org", "o"),
+		NewWithKind("device", "d"),
+	), c)
+
+	c, err = Merge(base, NewBuilder("u").SetString("a", "3").Build(), MergeOverlayWins)
+	require.NoError(t, err)
+	assertContextsEqual(t, NewMulti(NewBuilder("u").SetString("a", "3").Build(), NewWithKind("org", "o")), c)
+}
+
+func TestMergeInvalidContexts(t *testing.T) {
+	_, err := Merge(Context{}, New("a"), MergeOverlayWins)
+	assert.Equal(t, lderrors.ErrContextUninitialized{}, err)
+	_, err = Merge(New("a"), New(""), MergeOverlayWins)
+	assert.Equal(t, lderrors.ErrContextKeyEmpty{}, err)
+}
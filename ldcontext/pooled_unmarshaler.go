@@ -0,0 +1,156 @@
+package ldcontext
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/internal/valuereuse"
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+)
+
+// maxPooledStrings is the maximum number of distinct attribute names, and of distinct private attribute
+// references, that a PooledUnmarshaler will remember. This keeps its memory usage bounded if the input
+// contains arbitrary names; any names beyond that limit are still unmarshaled correctly, just not reused.
+const maxPooledStrings = 1000
+
+// PooledUnmarshaler is an optional alternative to [Context.UnmarshalJSON] and
+// [ContextSerializationMethods.UnmarshalFromJSONReader], for applications that unmarshal a large
+// number of Contexts one at a time and only need each of them briefly-- for instance, to evaluate
+// flags for it and then discard it. It produces the same results, but reuses memory across calls
+// to reduce allocations:
+//
+//   - The storage for a Context's attributes, and for its list of private attribute references,
+//     is reused for the Context that is unmarshaled by the next call.
+//   - Attribute names, and parsed private attribute references, are remembered and reused when the
+//     same ones are seen again.
+//
+// Because of the first of these, a Context that was unmarshaled by a PooledUnmarshaler is only valid
+// until the next call to one of the PooledUnmarshaler's unmarshaling methods; after that, its
+// attributes may be cleared or may be replaced by those of a different Context. Do not retain the
+// Context beyond that point, or pass it to anything that might retain it, such as an SDK method that
+// queues analytics events. If you need to keep a Context, unmarshal it with the regular methods
+// instead.
+//
+// The same applies to anything that still shares the Context's attribute storage: a Context that
+// was copied from it, a [Builder] created from it with [NewBuilderFromContext] before any attribute
+// is set or removed on that Builder, and any Context built by such a Builder. Values and strings that
+// were obtained from the Context, such as the result of [Context.GetValue], do not share that storage
+// and remain valid, as does a Context returned by [Context.Transform] that is not the same Context.
+//
+// The zero value of PooledUnmarshaler is ready to use. A PooledUnmarshaler is not safe for concurrent
+// use by multiple goroutines; to unmarshal Contexts concurrently, give each goroutine its own.
+type PooledUnmarshaler struct {
+	builders     []*Builder
+	buildersUsed int
+	multi        MultiBuilder
+	names        map[string]string
+	refs         map[string]ldattr.Ref
+}
+
+// Unmarshal unmarshals a Context from JSON data, in the same way as [Context.UnmarshalJSON].
+//
+// The Context is only valid until the next call to an unmarshaling method of the same
+// PooledUnmarshaler; see [PooledUnmarshaler] for details.
+func (u *PooledUnmarshaler) Unmarshal(data []byte, c *Context) error {
+	r := jreader.NewReader(data)
+	return u.unmarshal(&r, c, &exactNumberSource{data: data})
+}
+
+// UnmarshalFromJSONReader unmarshals a Context with the jsonstream Reader API, in the same way as
+// [ContextSerializationMethods.UnmarshalFromJSONReader].
+//
+// The Context is only valid until the next call to an unmarshaling method of the same
+// PooledUnmarshaler; see [PooledUnmarshaler] for details. In case of failure, the error is both
+// returned from the method and stored as a failure state in the Reader.
+//
+// Like [ContextSerializationMethods.UnmarshalFromJSONReader], this reads integers that cannot be exactly
+// represented as a float64 with a loss of precision; [PooledUnmarshaler.Unmarshal] reads them exactly.
+func (u *PooledUnmarshaler) UnmarshalFromJSONReader(r *jreader.Reader, c *Context) error {
+	return u.unmarshal(r, c, nil)
+}
+
+func (u *PooledUnmarshaler) unmarshal(r *jreader.Reader, c *Context, s *exactNumberSource) error {
+	if u != nil {
+		u.buildersUsed = 0
+	}
+	unmarshalFromJSONReader(r, c, false, u, s)
+	return r.Error()
+}
+
+// builder returns a Builder to use for one individual context. If u is nil, this is the Builder
+// that was passed in; otherwise it is a pooled Builder that was reset for reuse.
+func (u *PooledUnmarshaler) builder(local *Builder) *Builder {
+	if u == nil {
+		return local
+	}
+	if u.buildersUsed == len(u.builders) {
+		u.builders = append(u.builders, &Builder{})
+	}
+	b := u.builders[u.buildersUsed]
+	u.buildersUsed++
+	b.resetForReuse()
+	return b
+}
+
+// multiBuilder is the equivalent of builder for a MultiBuilder.
+func (u *PooledUnmarshaler) multiBuilder(local *MultiBuilder) *MultiBuilder {
+	if u == nil {
+		return local
+	}
+	u.multi.contexts = u.multi.contexts[:0]
+	u.multi.contextsCopyOnWrite = false
+	return &u.multi
+}
+
+// attributeName converts a property name to a string, reusing a previously seen string if possible.
+// See internAttributeNameIfPossible.
+func (u *PooledUnmarshaler) attributeName(nameBytes []byte) string {
+	if u == nil {
+		return internAttributeNameIfPossible(nameBytes)
+	}
+	if name, ok := u.names[string(nameBytes)]; ok {
+		return name
+	}
+	name := internAttributeNameIfPossible(nameBytes)
+	if len(u.names) < maxPooledStrings {
+		if u.names == nil {
+			u.names = make(map[string]string)
+		}
+		u.names[name] = name
+	}
+	return name
+}
+
+// ref converts a private attribute string to an ldattr.Ref, reusing a previously parsed Ref if possible.
+// Literal references are not remembered, because creating one does not involve any parsing.
+func (u *PooledUnmarshaler) ref(s string, asLiteral bool) ldattr.Ref {
+	if u == nil || asLiteral {
+		return refOrLiteralRef(s, asLiteral)
+	}
+	if ref, ok := u.refs[s]; ok {
+		return ref
+	}
+	ref := ldattr.NewRef(s)
+	if len(u.refs) < maxPooledStrings {
+		if u.refs == nil {
+			u.refs = make(map[string]ldattr.Ref)
+		}
+		u.refs[s] = ref
+	}
+	return ref
+}
+
+// resetForReuse returns the Builder to the same state as a new Builder{}, except that it keeps the
+// storage that was allocated for attributes and private attribute references. Any Context that was
+// previously built from this Builder shares that storage, so it must not be used after this.
+//
+// The storage for private attribute references is kept in spareStorage rather than privateAttrs,
+// so that a Context that has no private attributes still has a nil privateAttrs slice, just as it
+// would if it had been built by a new Builder.
+func (b *Builder) resetForReuse() {
+	attributes, spareStorage := b.attributes, b.privateAttrs
+	if spareStorage == nil {
+		spareStorage = b.spareStorage
+	}
+	*b = Builder{attributes: attributes, spareStorage: spareStorage[:0]}
+	b.attributes.ResetForReuse(valuereuse.Permission{})
+}
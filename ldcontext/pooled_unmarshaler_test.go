@@ -0,0 +1,95 @@
+package ldcontext
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pooledUnmarshalTestFn(u *PooledUnmarshaler) func(*Context, []byte) error {
+	return func(c *Context, data []byte) error { return u.Unmarshal(data, c) }
+}
+
+func TestPooledUnmarshaler(t *testing.T) {
+	// The same PooledUnmarshaler is used for every test case, so that each one is unmarshaled into
+	// storage left over from previous ones. There's no array test, because the results of unmarshaling
+	// the earlier array elements would no longer be valid.
+	contextUnmarshalingTests(t, pooledUnmarshalTestFn(&PooledUnmarshaler{}), nil)
+}
+
+func TestPooledUnmarshalerWithNilReceiverIsSameAsRegularUnmarshaling(t *testing.T) {
+	contextUnmarshalingTests(t, pooledUnmarshalTestFn(nil), nil)
+}
+
+func TestPooledUnmarshalerReusesStorage(t *testing.T) {
+	var u PooledUnmarshaler
+	var c Context
+	json1 := []byte(`{"kind": "user", "key": "a", "email": "a@example.com", "_meta": {"privateAttributes": ["/x/y"]}}`)
+	json2 := []byte(`{"kind": "user", "key": "b", "email": "b@example.com", "_meta": {"privateAttributes": ["/x/y"]}}`)
+
+	require.NoError(t, u.Unmarshal(json1, &c))
+	email := c.GetValue("email")
+	require.NoError(t, u.Unmarshal(json2, &c))
+	assert.Equal(t, ldvalue.String("a@example.com"), email) // values obtained from the old Context are unaffected
+	assert.Equal(t, NewBuilder("b").SetString("email", "b@example.com").Private("/x/y").Build(), c)
+
+	pooledAllocs := testing.AllocsPerRun(100, func() { _ = u.Unmarshal(json1, &c) })
+	regularAllocs := testing.AllocsPerRun(100, func() { _ = c.UnmarshalJSON(json1) })
+	assert.Less(t, pooledAllocs, regularAllocs)
+}
+
+func TestPooledUnmarshalerReusesStorageForPrivateAttributes(t *testing.T) {
+	var u PooledUnmarshaler
+	var c Context
+	withPrivate := []byte(`{"kind": "user", "key": "a", "_meta": {"privateAttributes": ["/x/y"]}}`)
+	withoutPrivate := []byte(`{"kind": "user", "key": "b"}`)
+	withEmptyPrivate := []byte(`{"kind": "user", "key": "c", "_meta": {"privateAttributes": []}}`)
+
+	require.NoError(t, u.Unmarshal(withPrivate, &c))
+	require.NoError(t, u.Unmarshal(withoutPrivate, &c))
+	assert.Equal(t, NewBuilder("b").Build(), c)
+	assert.Nil(t, c.privateAttrs)
+
+	require.NoError(t, u.Unmarshal(withEmptyPrivate, &c))
+	var expected Context
+	require.NoError(t, expected.UnmarshalJSON(withEmptyPrivate))
+	assert.Equal(t, expected, c)
+
+	require.NoError(t, u.Unmarshal(withPrivate, &c))
+	assert.Equal(t, NewBuilder("a").Private("/x/y").Build(), c)
+}
+
+func TestPooledUnmarshalerReusesStorageForMultiContext(t *testing.T) {
+	var u PooledUnmarshaler
+	var c Context
+	json1 := []byte(`{"kind": "multi", "org": {"key": "a", "x": 1}, "user": {"key": "b", "y": 2}}`)
+	json2 := []byte(`{"kind": "multi", "org": {"key": "c", "x": 3}, "user": {"key": "d", "y": 4}}`)
+
+	require.NoError(t, u.Unmarshal(json1, &c))
+	require.NoError(t, u.Unmarshal(json2, &c))
+	assert.Equal(t, NewMulti(
+		NewBuilder("c").Kind("org").SetInt("x", 3).Build(),
+		NewBuilder("d").SetInt("y", 4).Build(),
+	), c)
+
+	pooledAllocs := testing.AllocsPerRun(100, func() { _ = u.Unmarshal(json1, &c) })
+	regularAllocs := testing.AllocsPerRun(100, func() { _ = c.UnmarshalJSON(json1) })
+	assert.Less(t, pooledAllocs, regularAllocs)
+}
+
+func TestPooledUnmarshalerLimitsRememberedNames(t *testing.T) {
+	var u PooledUnmarshaler
+	var c Context
+	for i := 0; i < maxPooledStrings+10; i++ {
+		data := fmt.Sprintf(`{"kind": "user", "key": "a", "attr%d": true, "_meta": {"privateAttributes": ["/p%d/q"]}}`,
+			i, i)
+		require.NoError(t, u.Unmarshal([]byte(data), &c))
+		assert.Equal(t, NewBuilder("a").SetBool(fmt.Sprintf("attr%d", i), true).Private(fmt.Sprintf("/p%d/q", i)).Build(), c)
+	}
+	assert.Len(t, u.names, maxPooledStrings)
+	assert.Len(t, u.refs, maxPooledStrings)
+}
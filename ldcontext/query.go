@@ -0,0 +1,484 @@
+package ldcontext
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// Query is a compiled expression for selecting Contexts based on their attributes. It is created
+// by [ParseQuery]. A Query is immutable and can be safely used by multiple goroutines.
+//
+// A query consists of comparisons, combined with the logical operators "&&", "||", and "!", and
+// grouped with parentheses if necessary. "!" has the highest precedence and "||" the lowest:
+//
+//	kind == "org" && /address/country in ["US", "CA"] && anonymous == false
+//	!(plan == "free" || /seats < 10)
+//
+// Each side of a comparison is either an attribute or a literal value:
+//
+//   - An attribute that starts with a slash is an attribute reference in the same syntax as
+//     [ldattr.NewRef], such as "/address/country". The reference ends at the first whitespace
+//     character or at any of the characters ( ) [ ] , & | ! = < > ". An attribute that does not
+//     start with a slash is a simple attribute name that begins with a letter or "_" and contains
+//     only letters, digits, "_", "-", and ".", such as "kind" or "email". An attribute that does
+//     not exist has a null value.
+//   - A literal value is a JSON string, number, boolean, or null, or an array of these in square
+//     brackets (arrays can be nested).
+//
+// The comparison operators are:
+//
+//   - "==" and "!=" compare any two values for deep equality, as defined by [ldvalue.Value.Equal].
+//   - "<", "<=", ">", and ">=" compare two numbers, or two strings (by their UTF-8 bytes). They are
+//     false if the values are not both numbers or both strings. Numbers are compared as in
+//     [ldvalue.Compare], so integers within the range of int64 or uint64 are compared exactly.
+//   - "in" is true if the right-hand side is an array that contains a value equal to the left-hand
+//     side, and false otherwise.
+//
+// When a Query is applied to a multi-context, it matches if any of the individual contexts matches
+// the whole expression. For instance, kind == "org" && /address/country == "US" matches a
+// multi-context containing an "org" context whose country is "US", regardless of the other kinds.
+type Query struct {
+	source string
+	root   queryNode
+}
+
+// ParseQuery compiles a query expression. See [Query] for the syntax.
+//
+// If the expression is invalid, it returns an error of type [lderrors.ErrContextQuerySyntax]
+// indicating where the problem was found.
+func ParseQuery(s string) (Query, error) {
+	tokens, err := lexQuery(s)
+	if err != nil {
+		return Query{}, err
+	}
+	p := queryParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return Query{}, err
+	}
+	if t := p.peek(); t.tokenType != queryTokenEOF {
+		return Query{}, t.syntaxError("unexpected %s", t.describe())
+	}
+	return Query{source: s, root: root}, nil
+}
+
+// Match returns true if the Context matches the query.
+//
+// It always returns false for an invalid Context, or for an uninitialized Query{}.
+func (q Query) Match(c Context) bool {
+	if q.root == nil || c.Err() != nil {
+		return false
+	}
+	if c.Multiple() {
+		for _, mc := range c.multiContexts {
+			if q.root.matches(mc) {
+				return true
+			}
+		}
+		return false
+	}
+	return q.root.matches(c)
+}
+
+// Filter returns a new slice containing only the Contexts that match the query, in their original
+// order. If none of them match, it returns nil.
+func (q Query) Filter(contexts []Context) []Context {
+	var ret []Context
+	for _, c := range contexts {
+		if q.Match(c) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// String returns the original expression that the query was parsed from.
+func (q Query) String() string {
+	return q.source
+}
+
+type queryNode interface {
+	matches(c Context) bool
+}
+
+type queryAnd struct{ left, right queryNode }
+
+type queryOr struct{ left, right queryNode }
+
+type queryNot struct{ operand queryNode }
+
+type queryComparison struct {
+	left, right queryOperand
+	operator    string
+}
+
+type queryOperand struct {
+	ref   ldattr.Ref // defined only if this is an attribute
+	value ldvalue.Value
+}
+
+func (n queryAnd) matches(c Context) bool { return n.left.matches(c) && n.right.matches(c) }
+
+func (n queryOr) matches(c Context) bool { return n.left.matches(c) || n.right.matches(c) }
+
+func (n queryNot) matches(c Context) bool { return !n.operand.matches(c) }
+
+func (n queryComparison) matches(c Context) bool {
+	left, right := n.left.resolve(c), n.right.resolve(c)
+	switch n.operator {
+	case "==":
+		return left.Equal(right)
+	case "!=":
+		return !left.Equal(right)
+	case "in":
+		if right.Type() == ldvalue.RawType {
+			right = ldvalue.Parse(right.AsRaw())
+		}
+		if right.Type() != ldvalue.ArrayType {
+			return false
+		}
+		for i := 0; i < right.Count(); i++ {
+			if left.Equal(right.GetByIndex(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	var result int
+	switch {
+	case left.IsNumber() && right.IsNumber():
+		result = ldvalue.Compare(left, right)
+	case left.IsString() && right.IsString():
+		result = strings.Compare(left.StringValue(), right.StringValue())
+	default:
+		return false
+	}
+	switch n.operator {
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	default: // ">="
+		return result >= 0
+	}
+}
+
+func (o queryOperand) resolve(c Context) ldvalue.Value {
+	if o.ref.IsDefined() {
+		return c.GetValueForRef(o.ref)
+	}
+	return o.value
+}
+
+type queryParser struct {
+	tokens []queryToken
+	index  int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.index]
+}
+
+func (p *queryParser) next() queryToken {
+	t := p.tokens[p.index]
+	if t.tokenType != queryTokenEOF {
+		p.index++
+	}
+	return t
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().tokenType == queryTokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = queryOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().tokenType == queryTokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = queryAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	switch p.peek().tokenType {
+	case queryTokenNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{operand}, nil
+	case queryTokenLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.tokenType != queryTokenRParen {
+			return nil, t.syntaxError(`expected ")", found %s`, t.describe())
+		}
+		return expr, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *queryParser) parseComparison() (queryNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.next()
+	if t.tokenType != queryTokenOperator && t.tokenType != queryTokenIn {
+		return nil, t.syntaxError("expected a comparison operator, found %s", t.describe())
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return queryComparison{left: left, right: right, operator: t.text}, nil
+}
+
+func (p *queryParser) parseOperand() (queryOperand, error) {
+	if t := p.peek(); t.tokenType == queryTokenAttribute {
+		p.next()
+		return queryOperand{ref: t.ref}, nil
+	}
+	value, err := p.parseLiteral("an attribute or a value")
+	if err != nil {
+		return queryOperand{}, err
+	}
+	return queryOperand{value: value}, nil
+}
+
+func (p *queryParser) parseLiteral(expected string) (ldvalue.Value, error) {
+	t := p.next()
+	switch t.tokenType {
+	case queryTokenLiteral:
+		return t.value, nil
+	case queryTokenLBracket:
+		items := ldvalue.ArrayBuild()
+		if p.peek().tokenType == queryTokenRBracket {
+			p.next()
+			return items.Build(), nil
+		}
+		for {
+			item, err := p.parseLiteral("a value")
+			if err != nil {
+				return ldvalue.Null(), err
+			}
+			items.Add(item)
+			t := p.next()
+			if t.tokenType == queryTokenRBracket {
+				return items.Build(), nil
+			}
+			if t.tokenType != queryTokenComma {
+				return ldvalue.Null(), t.syntaxError(`expected "," or "]", found %s`, t.describe())
+			}
+		}
+	default:
+		return ldvalue.Null(), t.syntaxError("expected %s, found %s", expected, t.describe())
+	}
+}
+
+type queryTokenType int
+
+const (
+	queryTokenEOF queryTokenType = iota
+	queryTokenLParen
+	queryTokenRParen
+	queryTokenLBracket
+	queryTokenRBracket
+	queryTokenComma
+	queryTokenAnd
+	queryTokenOr
+	queryTokenNot
+	queryTokenOperator
+	queryTokenIn
+	queryTokenAttribute
+	queryTokenLiteral
+)
+
+type queryToken struct {
+	tokenType queryTokenType
+	pos       int
+	text      string
+	ref       ldattr.Ref
+	value     ldvalue.Value
+}
+
+func (t queryToken) describe() string {
+	if t.tokenType == queryTokenEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+func (t queryToken) syntaxError(format string, args ...any) error {
+	return lderrors.ErrContextQuerySyntax{Position: t.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func lexQuery(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	add := func(tokenType queryTokenType, length int) {
+		tokens = append(tokens, queryToken{tokenType: tokenType, pos: i, text: s[i : i+length]})
+		i += length
+	}
+	for i < len(s) {
+		ch := s[i]
+		var next byte
+		if i+1 < len(s) {
+			next = s[i+1]
+		}
+		switch {
+		case isQueryWhitespace(ch):
+			i++
+		case ch == '(':
+			add(queryTokenLParen, 1)
+		case ch == ')':
+			add(queryTokenRParen, 1)
+		case ch == '[':
+			add(queryTokenLBracket, 1)
+		case ch == ']':
+			add(queryTokenRBracket, 1)
+		case ch == ',':
+			add(queryTokenComma, 1)
+		case ch == '&' && next == '&':
+			add(queryTokenAnd, 2)
+		case ch == '|' && next == '|':
+			add(queryTokenOr, 2)
+		case ch == '=' && next == '=', ch == '!' && next == '=', (ch == '<' || ch == '>') && next == '=':
+			add(queryTokenOperator, 2)
+		case ch == '<', ch == '>':
+			add(queryTokenOperator, 1)
+		case ch == '!':
+			add(queryTokenNot, 1)
+		case ch == '"', ch == '/', ch == '-', ch >= '0' && ch <= '9', isQueryNameStart(ch):
+			t, err := lexQueryOperand(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i += len(t.text)
+		default:
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return nil, lderrors.ErrContextQuerySyntax{Position: i,
+				Message: fmt.Sprintf("unexpected character %q", string(r))}
+		}
+	}
+	tokens = append(tokens, queryToken{tokenType: queryTokenEOF, pos: len(s)})
+	return tokens, nil
+}
+
+func lexQueryOperand(s string, start int) (queryToken, error) {
+	ch := s[start]
+	switch {
+	case ch == '"':
+		return lexQueryString(s, start)
+	case ch == '/':
+		end := start + 1
+		for end < len(s) && !isQueryDelimiter(s[end]) {
+			end++
+		}
+		ref := ldattr.NewRef(s[start:end])
+		if err := ref.Err(); err != nil {
+			return queryToken{}, lderrors.ErrContextQuerySyntax{Position: start,
+				Message: fmt.Sprintf("invalid attribute reference %q: %s", s[start:end], err)}
+		}
+		return queryToken{tokenType: queryTokenAttribute, pos: start, text: s[start:end], ref: ref}, nil
+	case isQueryNameStart(ch):
+		end := start + 1
+		for end < len(s) && isQueryNameChar(s[end]) {
+			end++
+		}
+		t := queryToken{tokenType: queryTokenLiteral, pos: start, text: s[start:end]}
+		switch t.text {
+		case "true":
+			t.value = ldvalue.Bool(true)
+		case "false":
+			t.value = ldvalue.Bool(false)
+		case "null":
+		case "in":
+			t.tokenType = queryTokenIn
+		default:
+			t.tokenType = queryTokenAttribute
+			t.ref = ldattr.NewLiteralRef(t.text)
+		}
+		return t, nil
+	default:
+		end := start + 1
+		for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+			end++
+		}
+		n := ldvalue.Number(json.Number(s[start:end]))
+		if !n.IsNumber() {
+			return queryToken{}, lderrors.ErrContextQuerySyntax{Position: start,
+				Message: fmt.Sprintf("invalid number %q", s[start:end])}
+		}
+		return queryToken{tokenType: queryTokenLiteral, pos: start, text: s[start:end], value: n}, nil
+	}
+}
+
+func lexQueryString(s string, start int) (queryToken, error) {
+	for end := start + 1; end < len(s); end++ {
+		switch s[end] {
+		case '\\':
+			end++
+		case '"':
+			text := s[start : end+1]
+			var str string
+			if err := json.Unmarshal([]byte(text), &str); err != nil {
+				return queryToken{}, lderrors.ErrContextQuerySyntax{Position: start,
+					Message: fmt.Sprintf("invalid string %s", text)}
+			}
+			return queryToken{tokenType: queryTokenLiteral, pos: start, text: text, value: ldvalue.String(str)}, nil
+		}
+	}
+	return queryToken{}, lderrors.ErrContextQuerySyntax{Position: start, Message: "unterminated string"}
+}
+
+func isQueryWhitespace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isQueryDelimiter(ch byte) bool {
+	return isQueryWhitespace(ch) || strings.IndexByte(`()[],&|!=<>"`, ch) >= 0
+}
+
+func isQueryNameStart(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+}
+
+func isQueryNameChar(ch byte) bool {
+	return isQueryNameStart(ch) || (ch >= '0' && ch <= '9') || ch == '-' || ch == '.'
+}
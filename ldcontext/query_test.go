@@ -0,0 +1,165 @@
+package ldcontext
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeQueryTestContext() Context {
+	return NewBuilder("org-key").Kind("org").Name("Org").
+		SetValue("address", ldvalue.Parse([]byte(`{"country":"US","zip":"94612"}`))).
+		SetInt("seats", 25).
+		SetValue("tags", ldvalue.ArrayOf(ldvalue.String("a"), ldvalue.String("b"))).
+		SetValue("raw", ldvalue.Raw([]byte(`{"n":3,"list":[1,2]}`))).
+		SetValue("id", ldvalue.Int64(9007199254740993)).
+		Build()
+}
+
+func TestQueryMatch(t *testing.T) {
+	c := makeQueryTestContext()
+	for _, p := range []struct {
+		query    string
+		expected bool
+	}{
+		{`kind == "org"`, true},
+		{`kind == "user"`, false},
+		{`kind != "user"`, true},
+		{`key == "org-key"`, true},
+		{`name == "Org"`, true},
+		{`anonymous == false`, true},
+		{`/address/country == "US"`, true},
+		{`/address/country in ["US", "CA"]`, true},
+		{`/address/country in ["GB", "CA"]`, false},
+		{`/address/country in []`, false},
+		{`/address/country in "US"`, false},
+		{`"a" in tags`, true},
+		{`"c" in tags`, false},
+		{`tags == ["a", "b"]`, true},
+		{`seats == 25`, true},
+		{`seats > 20`, true},
+		{`seats >= 25`, true},
+		{`seats < 25`, false},
+		{`seats <= 25.0`, true},
+		{`seats > -1e3`, true},
+		{`seats > "20"`, false},
+		{`/address/zip >= "9"`, true},
+		{`/address/zip < "9"`, false},
+		{`missing == null`, true},
+		{`missing != null`, false},
+		{`/address/missing == null`, true},
+		{`missing < 1`, false},
+		{`/raw/n == 3`, true},
+		{`2 in /raw/list`, true},
+		{`/raw/n > 2`, true},
+		{`id == 9007199254740993`, true},
+		{`id == 9007199254740992`, false},
+		{`id > 9007199254740992`, true},
+		{`id < 9007199254740994`, true},
+		{`id >= 9.007199254740993e15`, true},
+		{`id < 1e400`, true},
+		{`kind == "org" && /address/country in ["US","CA"] && anonymous == false`, true},
+		{`kind == "user" || seats == 25`, true},
+		{`kind == "user" || seats == 1 && name == "Org"`, false},
+		{`(kind == "user" || seats == 25) && name == "Org"`, true},
+		{`!(kind == "user")`, true},
+		{`!kind == "org"`, false},
+		{`!!(kind == "org")`, true},
+		{`"org" == kind`, true},
+		{`true == true`, true},
+	} {
+		t.Run(p.query, func(t *testing.T) {
+			q, err := ParseQuery(p.query)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, q.Match(c))
+			assert.Equal(t, p.query, q.String())
+		})
+	}
+}
+
+func TestQueryEscapedAttributeNames(t *testing.T) {
+	c := NewBuilder("key").SetString("a/b", "x").SetValue("c~d", ldvalue.Parse([]byte(`{"e":1}`))).Build()
+	for _, s := range []string{`/a~1b == "x"`, `/c~0d/e == 1`} {
+		q, err := ParseQuery(s)
+		require.NoError(t, err)
+		assert.True(t, q.Match(c), s)
+	}
+}
+
+func TestQueryMultiContext(t *testing.T) {
+	c := NewMulti(
+		NewBuilder("user-key").SetString("country", "GB").Build(),
+		NewBuilder("org-key").Kind("org").SetString("country", "US").Build(),
+	)
+	for _, p := range []struct {
+		query    string
+		expected bool
+	}{
+		{`kind == "org" && country == "US"`, true},
+		{`kind == "user" && country == "US"`, false},
+		{`country == "GB"`, true},
+		{`kind == "multi"`, false},
+	} {
+		t.Run(p.query, func(t *testing.T) {
+			q, err := ParseQuery(p.query)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, q.Match(c))
+		})
+	}
+}
+
+func TestQueryDoesNotMatchInvalidContextOrUninitializedQuery(t *testing.T) {
+	q, err := ParseQuery(`key == ""`)
+	require.NoError(t, err)
+	assert.False(t, q.Match(New("")))
+	assert.False(t, q.Match(Context{}))
+
+	assert.False(t, Query{}.Match(New("key")))
+}
+
+func TestQueryFilter(t *testing.T) {
+	c1 := NewBuilder("a").SetInt("n", 1).Build()
+	c2 := NewBuilder("b").SetInt("n", 2).Build()
+	c3 := NewBuilder("c").SetInt("n", 3).Build()
+	q, err := ParseQuery(`n != 2`)
+	require.NoError(t, err)
+	assert.Equal(t, []Context{c1, c3}, q.Filter([]Context{c1, c2, c3}))
+	assert.Nil(t, q.Filter([]Context{c2}))
+}
+
+func TestQuerySyntaxErrors(t *testing.T) {
+	for _, p := range []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{``, 0, `expected an attribute or a value, found end of query`},
+		{`kind`, 4, `expected a comparison operator, found end of query`},
+		{`kind = "org"`, 5, `unexpected character "="`},
+		{`kind == `, 8, `expected an attribute or a value, found end of query`},
+		{`kind == "org" &`, 14, `unexpected character "&"`},
+		{`kind == "org" kind`, 14, `unexpected "kind"`},
+		{`(kind == "org"`, 14, `expected ")", found end of query`},
+		{`kind == "org")`, 13, `unexpected ")"`},
+		{`kind == "org`, 8, `unterminated string`},
+		{`kind == "a\qb"`, 8, `invalid string "a\qb"`},
+		{`x == 1.2.3`, 5, `invalid number "1.2.3"`},
+		{`x == 01`, 5, `invalid number "01"`},
+		{`/a//b == 1`, 0, `invalid attribute reference "/a//b": attribute reference contained a double slash or a trailing slash`},
+		{`x in [1, 2`, 10, `expected "," or "]", found end of query`},
+		{`x in [1 2]`, 8, `expected "," or "]", found "2"`},
+		{`x in [y]`, 6, `expected a value, found "y"`},
+		{`x == 1 && || y == 2`, 10, `expected an attribute or a value, found "||"`},
+		{`x == @`, 5, `unexpected character "@"`},
+		{`x == é`, 5, `unexpected character "é"`},
+	} {
+		t.Run(p.query, func(t *testing.T) {
+			_, err := ParseQuery(p.query)
+			assert.Equal(t, lderrors.ErrContextQuerySyntax{Position: p.position, Message: p.message}, err)
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package ldcontext
+
+import (
+	"sort"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// SchemaRegistry is an optional set of declarations about what attributes each [Kind] of Context
+// is expected to have. It can be used to detect mistakes such as a misspelled attribute name, or an
+// attribute value of the wrong type, before a Context is used in the SDK.
+//
+// The SDK itself never consults a SchemaRegistry: a Context that does not conform to the schema is
+// still a valid Context. Validation only happens when you call [SchemaRegistry.Validate].
+//
+//	registry := ldcontext.NewSchemaRegistry().
+//		Register(ldcontext.NewKindSchemaBuilder("user").
+//			RequiredAttribute("plan", ldvalue.StringType).
+//			Attribute("email", ldvalue.StringType).
+//			Private("email").
+//			Build())
+//	if err := registry.Validate(context); err != nil {
+//		// err is an lderrors.ErrContextSchemaViolations
+//	}
+//
+// A SchemaRegistry should be fully configured before it is used. Once it is no longer being
+// modified, it is safe to call Validate from multiple goroutines.
+type SchemaRegistry struct {
+	kinds              map[Kind]KindSchema
+	rejectUnknownKinds bool
+}
+
+// KindSchema describes the expected attributes of a Context of one [Kind]. Use
+// [NewKindSchemaBuilder] to create one.
+type KindSchema struct {
+	kind                string
+	attributes          []schemaAttribute
+	topLevelNames       map[string]struct{}
+	allowUndeclared     bool
+	defaultPrivateAttrs []ldattr.Ref
+	err                 error
+}
+
+type schemaAttribute struct {
+	ref       ldattr.Ref
+	types     []ldvalue.ValueType
+	required  bool
+	isPrivate bool
+}
+
+// KindSchemaBuilder is a mutable object that uses the builder pattern to specify a [KindSchema].
+//
+// A KindSchemaBuilder should not be accessed by multiple goroutines at once.
+type KindSchemaBuilder struct {
+	kind            Kind
+	attributes      []schemaAttribute
+	allowUndeclared bool
+	err             error
+}
+
+// NewSchemaRegistry creates an empty SchemaRegistry. The zero value SchemaRegistry{} is also an
+// empty registry.
+//
+// By default, a Context whose kind has not been registered is not checked at all. To treat such
+// kinds as an error, use [SchemaRegistry.RejectUnknownKinds].
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{}
+}
+
+// Register adds a KindSchema to the registry, replacing any previous schema for the same kind.
+func (r *SchemaRegistry) Register(schema KindSchema) *SchemaRegistry {
+	if r.kinds == nil {
+		r.kinds = make(map[Kind]KindSchema)
+	}
+	r.kinds[Kind(schema.kind)] = schema
+	return r
+}
+
+// RejectUnknownKinds sets whether a Context with a kind that has not been registered should be
+// reported as a schema violation. The default is false.
+func (r *SchemaRegistry) RejectUnknownKinds(value bool) *SchemaRegistry {
+	r.rejectUnknownKinds = value
+	return r
+}
+
+// Schema returns the KindSchema that was registered for the specified kind, if any.
+func (r *SchemaRegistry) Schema(kind Kind) (KindSchema, bool) {
+	s, ok := r.kinds[kind]
+	return s, ok
+}
+
+// Validate checks whether a Context conforms to the registry. For a multi-context, each individual
+// context is checked against the schema for its kind.
+//
+// If the Context itself is invalid (see [Context.Err]), Validate returns that error; likewise, if
+// the KindSchema for any of its kinds is invalid (see [KindSchema.Err]), it returns that error. If
+// there are any problems, it returns an [lderrors.ErrContextSchemaViolations] containing one error for
+// each problem, such as [lderrors.ErrContextSchemaUnknownAttribute]. Otherwise it returns nil.
+func (r *SchemaRegistry) Validate(c Context) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	var errs []error
+	for i := 0; i < c.IndividualContextCount(); i++ {
+		mc := c.IndividualContextByIndex(i)
+		schema, ok := r.kinds[mc.Kind()]
+		if !ok {
+			if r.rejectUnknownKinds {
+				errs = append(errs, lderrors.ErrContextSchemaUnknownKind{Kind: string(mc.Kind())})
+			}
+			continue
+		}
+		if schema.err != nil {
+			return schema.err
+		}
+		errs = schema.validate(mc, errs)
+	}
+	if len(errs) != 0 {
+		return lderrors.ErrContextSchemaViolations{Errors: errs}
+	}
+	return nil
+}
+
+// ApplyPrivateAttributes returns a copy of the Context in which every attribute that is declared as
+// private in the registry (see [KindSchemaBuilder.Private]) is also marked as private in the Context,
+// as if by [Builder.PrivateRef]. Private attributes that were already set on the Context are kept.
+//
+// If no changes are needed, or if the Context is invalid, the same Context is returned.
+func (r *SchemaRegistry) ApplyPrivateAttributes(c Context) Context {
+	if c.Err() != nil {
+		return c
+	}
+	if !c.Multiple() {
+		return r.applyPrivateAttributesSingleKind(c)
+	}
+	mb := NewMultiBuilder()
+	for _, mc := range c.multiContexts {
+		mb.Add(r.applyPrivateAttributesSingleKind(mc))
+	}
+	return mb.Build()
+}
+
+func (r *SchemaRegistry) applyPrivateAttributesSingleKind(c Context) Context {
+	schema, ok := r.kinds[c.Kind()]
+	if !ok || len(schema.defaultPrivateAttrs) == 0 {
+		return c
+	}
+	var missing []ldattr.Ref
+	for _, ref := range schema.defaultPrivateAttrs {
+		found := false
+		for _, existing := range c.privateAttrs {
+			if existing.Equal(ref) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, ref)
+		}
+	}
+	if len(missing) == 0 {
+		return c
+	}
+	return NewBuilderFromContext(c).PrivateRef(missing...).Build()
+}
+
+// NewKindSchemaBuilder creates a KindSchemaBuilder for the specified kind. If the kind is an empty
+// string, [DefaultKind] is used instead.
+func NewKindSchemaBuilder(kind Kind) *KindSchemaBuilder {
+	if kind == "" {
+		kind = DefaultKind
+	}
+	return &KindSchemaBuilder{kind: kind}
+}
+
+// Attribute declares an optional attribute. The attribute reference uses the same syntax as
+// [ldattr.NewRef], so it can be either a top-level attribute name like "plan", or a path to a
+// property within an object like "/address/city".
+//
+// If any value types are specified, the attribute's value must be one of those types when it is
+// present; otherwise it can have any type. Declaring the same attribute again replaces the previous
+// declaration.
+//
+// The built-in attributes "kind", "key", "name", and "anonymous" do not need to be declared, but
+// declaring "name" allows it to be made required.
+func (b *KindSchemaBuilder) Attribute(attrRef string, types ...ldvalue.ValueType) *KindSchemaBuilder {
+	return b.addAttribute(attrRef, types, false)
+}
+
+// RequiredAttribute declares an attribute that must be present. It is otherwise the same as
+// [KindSchemaBuilder.Attribute].
+func (b *KindSchemaBuilder) RequiredAttribute(attrRef string, types ...ldvalue.ValueType) *KindSchemaBuilder {
+	return b.addAttribute(attrRef, types, true)
+}
+
+// Private marks previously declared attributes as private by default. This has no effect on
+// validation; it is used by [SchemaRegistry.ApplyPrivateAttributes]. If an attribute reference has
+// not been declared, it is declared as an optional attribute of any type.
+func (b *KindSchemaBuilder) Private(attrRefs ...string) *KindSchemaBuilder {
+	for _, s := range attrRefs {
+		ref := b.newRef(s)
+		if i := b.indexOf(ref); i >= 0 {
+			b.attributes[i].isPrivate = true
+		} else {
+			b.attributes = append(b.attributes, schemaAttribute{ref: ref, isPrivate: true})
+		}
+	}
+	return b
+}
+
+// AllowUndeclaredAttributes sets whether the Context may have top-level attributes that were not
+// declared. The default is false, meaning that any undeclared attribute is reported as an
+// [lderrors.ErrContextSchemaUnknownAttribute].
+func (b *KindSchemaBuilder) AllowUndeclaredAttributes(value bool) *KindSchemaBuilder {
+	b.allowUndeclared = value
+	return b
+}
+
+// Build creates a KindSchema from the current properties of the builder.
+//
+// If any attribute reference that was declared is not a valid [ldattr.Ref], the KindSchema is
+// invalid: [KindSchema.Err] returns an [lderrors.ErrContextSchemaInvalidAttribute] for the first such
+// reference, and [SchemaRegistry.Validate] returns that error for any Context of this kind. To check
+// for this when building the schema, use [KindSchemaBuilder.TryBuild].
+func (b *KindSchemaBuilder) Build() KindSchema {
+	ret := KindSchema{
+		kind:            string(b.kind),
+		attributes:      append([]schemaAttribute(nil), b.attributes...),
+		topLevelNames:   make(map[string]struct{}, len(b.attributes)),
+		allowUndeclared: b.allowUndeclared,
+		err:             b.err,
+	}
+	for _, a := range ret.attributes {
+		if a.ref.Err() == nil {
+			ret.topLevelNames[a.ref.Component(0)] = struct{}{}
+		}
+		if a.isPrivate {
+			ret.defaultPrivateAttrs = append(ret.defaultPrivateAttrs, a.ref)
+		}
+	}
+	return ret
+}
+
+// TryBuild is the same as [KindSchemaBuilder.Build], except that it also returns the error from
+// [KindSchema.Err], if any.
+func (b *KindSchemaBuilder) TryBuild() (KindSchema, error) {
+	ret := b.Build()
+	return ret, ret.err
+}
+
+func (b *KindSchemaBuilder) addAttribute(attrRef string, types []ldvalue.ValueType, required bool) *KindSchemaBuilder {
+	attr := schemaAttribute{ref: b.newRef(attrRef), types: append([]ldvalue.ValueType(nil), types...),
+		required: required}
+	if i := b.indexOf(attr.ref); i >= 0 {
+		attr.isPrivate = b.attributes[i].isPrivate
+		b.attributes[i] = attr
+	} else {
+		b.attributes = append(b.attributes, attr)
+	}
+	return b
+}
+
+// newRef parses an attribute reference, recording the error if it is the first invalid one.
+func (b *KindSchemaBuilder) newRef(attrRef string) ldattr.Ref {
+	ref := ldattr.NewRef(attrRef)
+	if ref.Err() != nil && b.err == nil {
+		b.err = lderrors.ErrContextSchemaInvalidAttribute{Kind: string(b.kind), Attribute: attrRef, Err: ref.Err()}
+	}
+	return ref
+}
+
+func (b *KindSchemaBuilder) indexOf(ref ldattr.Ref) int {
+	for i, a := range b.attributes {
+		if a.ref.Equal(ref) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Kind returns the context kind that this schema applies to.
+func (s KindSchema) Kind() Kind {
+	return Kind(s.kind)
+}
+
+// Err returns nil if the KindSchema is valid, or an [lderrors.ErrContextSchemaInvalidAttribute] if it
+// declared an invalid attribute reference. See [KindSchemaBuilder.Build].
+func (s KindSchema) Err() error {
+	return s.err
+}
+
+func (s KindSchema) validate(c Context, errs []error) []error {
+	for _, a := range s.attributes {
+		value := c.GetValueForRef(a.ref)
+		if value.Type() == ldvalue.RawType {
+			value = ldvalue.Parse(value.AsRaw())
+		}
+		if value.IsNull() {
+			if a.required {
+				errs = append(errs, lderrors.ErrContextSchemaMissingAttribute{Kind: s.kind, Attribute: a.ref.String()})
+			}
+			continue
+		}
+		if len(a.types) != 0 && !schemaTypeAllowed(value.Type(), a.types) {
+			expected := make([]string, 0, len(a.types))
+			for _, t := range a.types {
+				expected = append(expected, t.String())
+			}
+			errs = append(errs, lderrors.ErrContextSchemaWrongType{Kind: s.kind, Attribute: a.ref.String(),
+				ExpectedTypes: expected, ActualType: value.Type().String()})
+		}
+	}
+	if !s.allowUndeclared {
+		names := c.attributes.Keys(nil)
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := s.topLevelNames[name]; !ok {
+				errs = append(errs, lderrors.ErrContextSchemaUnknownAttribute{Kind: s.kind,
+					Attribute: ldattr.NewLiteralRef(name).String()})
+			}
+		}
+	}
+	return errs
+}
+
+func schemaTypeAllowed(t ldvalue.ValueType, allowed []ldvalue.ValueType) bool {
+	for _, a := range allowed {
+		if a == t {
+			return true
+		}
+	}
+	return false
+}
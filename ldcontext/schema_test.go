@@ -0,0 +1,179 @@
+package ldcontext
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestSchemaRegistry() *SchemaRegistry {
+	return NewSchemaRegistry().
+		Register(NewKindSchemaBuilder("user").
+			RequiredAttribute("plan", ldvalue.StringType).
+			Attribute("seats", ldvalue.NumberType).
+			Attribute("email", ldvalue.StringType).
+			Attribute("/address/city", ldvalue.StringType).
+			Private("email", "/address/city").
+			Build()).
+		Register(NewKindSchemaBuilder("org").
+			RequiredAttribute("name").
+			AllowUndeclaredAttributes(true).
+			Build())
+}
+
+func TestSchemaValidateValidContexts(t *testing.T) {
+	registry := makeTestSchemaRegistry()
+
+	for _, c := range []Context{
+		NewBuilder("a").SetString("plan", "pro").Build(),
+		NewBuilder("a").SetString("plan", "pro").SetInt("seats", 3).SetString("email", "x").Build(),
+		NewBuilder("a").SetString("plan", "pro").
+			SetValue("address", ldvalue.ObjectBuild().SetString("city", "Oakland").Build()).Build(),
+		NewBuilder("a").SetString("plan", "pro").SetValue("address", ldvalue.Parse([]byte(`{"city":"x"}`))).Build(),
+		NewBuilder("a").Kind("org").Name("n").SetBool("anything", true).Build(),
+		NewBuilder("a").Kind("other").SetBool("anything", true).Build(),
+		NewMulti(NewBuilder("a").SetString("plan", "pro").Build(), NewBuilder("b").Kind("org").Name("n").Build()),
+	} {
+		t.Run(c.String(), func(t *testing.T) {
+			assert.NoError(t, registry.Validate(c))
+		})
+	}
+}
+
+func TestSchemaValidateViolations(t *testing.T) {
+	registry := makeTestSchemaRegistry()
+
+	t.Run("missing required attribute", func(t *testing.T) {
+		c := NewBuilder("a").Build()
+		assert.Equal(t, lderrors.ErrContextSchemaViolations{Errors: []error{
+			lderrors.ErrContextSchemaMissingAttribute{Kind: "user", Attribute: "plan"},
+		}}, registry.Validate(c))
+	})
+
+	t.Run("wrong types", func(t *testing.T) {
+		c := NewBuilder("a").SetInt("plan", 1).SetString("seats", "3").
+			SetValue("address", ldvalue.ObjectBuild().SetInt("city", 2).Build()).Build()
+		assert.Equal(t, lderrors.ErrContextSchemaViolations{Errors: []error{
+			lderrors.ErrContextSchemaWrongType{Kind: "user", Attribute: "plan",
+				ExpectedTypes: []string{"string"}, ActualType: "number"},
+			lderrors.ErrContextSchemaWrongType{Kind: "user", Attribute: "seats",
+				ExpectedTypes: []string{"number"}, ActualType: "string"},
+			lderrors.ErrContextSchemaWrongType{Kind: "user", Attribute: "/address/city",
+				ExpectedTypes: []string{"string"}, ActualType: "number"},
+		}}, registry.Validate(c))
+	})
+
+	t.Run("unknown attributes", func(t *testing.T) {
+		c := NewBuilder("a").SetString("plan", "x").SetString("plann", "x").SetBool("/odd", true).Build()
+		assert.Equal(t, lderrors.ErrContextSchemaViolations{Errors: []error{
+			lderrors.ErrContextSchemaUnknownAttribute{Kind: "user", Attribute: "/~1odd"},
+			lderrors.ErrContextSchemaUnknownAttribute{Kind: "user", Attribute: "plann"},
+		}}, registry.Validate(c))
+	})
+
+	t.Run("multi-context", func(t *testing.T) {
+		c := NewMulti(NewBuilder("a").SetString("plan", "x").Build(), NewWithKind("org", "b"))
+		assert.Equal(t, lderrors.ErrContextSchemaViolations{Errors: []error{
+			lderrors.ErrContextSchemaMissingAttribute{Kind: "org", Attribute: "name"},
+		}}, registry.Validate(c))
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		c := NewWithKind("other", "a")
+		assert.NoError(t, registry.Validate(c))
+		registry.RejectUnknownKinds(true)
+		assert.Equal(t, lderrors.ErrContextSchemaViolations{Errors: []error{
+			lderrors.ErrContextSchemaUnknownKind{Kind: "other"},
+		}}, registry.Validate(c))
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		assert.Equal(t, lderrors.ErrContextUninitialized{}, registry.Validate(Context{}))
+		assert.Equal(t, lderrors.ErrContextKeyEmpty{}, registry.Validate(New("")))
+	})
+}
+
+func TestSchemaApplyPrivateAttributes(t *testing.T) {
+	registry := makeTestSchemaRegistry()
+
+	t.Run("single context", func(t *testing.T) {
+		c := NewBuilder("a").SetString("email", "x").Private("email", "other").Build()
+		c1 := registry.ApplyPrivateAttributes(c)
+		assert.Equal(t, NewBuilder("a").SetString("email", "x").Private("email", "other", "/address/city").Build(), c1)
+	})
+
+	t.Run("no changes needed", func(t *testing.T) {
+		c := NewBuilder("a").Private("email", "/address/city").Build()
+		assert.Equal(t, c, registry.ApplyPrivateAttributes(c))
+		c2 := NewWithKind("org", "b")
+		assert.Equal(t, c2, registry.ApplyPrivateAttributes(c2))
+	})
+
+	t.Run("multi-context", func(t *testing.T) {
+		c := NewMulti(New("a"), NewWithKind("org", "b"))
+		c1 := registry.ApplyPrivateAttributes(c)
+		assert.Equal(t, NewMulti(NewBuilder("a").Private("email", "/address/city").Build(), NewWithKind("org", "b")), c1)
+	})
+}
+
+func TestKindSchemaBuilder(t *testing.T) {
+	s := NewKindSchemaBuilder("").
+		Attribute("a", ldvalue.StringType).
+		Private("a").
+		RequiredAttribute("a", ldvalue.NumberType).
+		Build()
+	assert.Equal(t, DefaultKind, s.Kind())
+	assert.Len(t, s.attributes, 1)
+	assert.True(t, s.attributes[0].required)
+	assert.True(t, s.attributes[0].isPrivate)
+	assert.Equal(t, []ldvalue.ValueType{ldvalue.NumberType}, s.attributes[0].types)
+	assert.Equal(t, []ldattr.Ref{ldattr.NewRef("a")}, s.defaultPrivateAttrs)
+
+	registry := NewSchemaRegistry().Register(s)
+	s1, ok := registry.Schema(DefaultKind)
+	assert.True(t, ok)
+	assert.Equal(t, s, s1)
+	_, ok = registry.Schema("org")
+	assert.False(t, ok)
+}
+
+func TestKindSchemaBuilderInvalidRef(t *testing.T) {
+	expectedErr := lderrors.ErrContextSchemaInvalidAttribute{Kind: "user", Attribute: "/a//b",
+		Err: lderrors.ErrAttributeExtraSlash{}}
+
+	for name, b := range map[string]*KindSchemaBuilder{
+		"attribute":          NewKindSchemaBuilder("user").Attribute("/a//b").Attribute("/c/"),
+		"required attribute": NewKindSchemaBuilder("user").RequiredAttribute("/a//b", ldvalue.StringType),
+		"private":            NewKindSchemaBuilder("user").Attribute("x").Private("/a//b"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, err := b.TryBuild()
+			assert.Equal(t, expectedErr, err)
+			assert.Equal(t, expectedErr, s.Err())
+			assert.Equal(t, expectedErr, b.Build().Err())
+
+			registry := NewSchemaRegistry().Register(s)
+			assert.Equal(t, expectedErr, registry.Validate(NewBuilder("a").SetString("x", "y").Build()))
+			assert.Equal(t, expectedErr, registry.Validate(NewMulti(NewWithKind("org", "b"), New("a"))))
+			assert.NoError(t, registry.Validate(NewWithKind("org", "b")))
+		})
+	}
+
+	t.Run("valid schema", func(t *testing.T) {
+		s, err := NewKindSchemaBuilder("user").Attribute("/a/b").TryBuild()
+		assert.NoError(t, err)
+		assert.NoError(t, s.Err())
+	})
+}
+
+func TestSchemaRegistryZeroValue(t *testing.T) {
+	var registry SchemaRegistry
+	assert.NoError(t, registry.Validate(New("a")))
+	registry.Register(NewKindSchemaBuilder("user").RequiredAttribute("plan").Build())
+	assert.Equal(t, lderrors.ErrContextSchemaViolations{Errors: []error{
+		lderrors.ErrContextSchemaMissingAttribute{Kind: "user", Attribute: "plan"}}}, registry.Validate(New("a")))
+}
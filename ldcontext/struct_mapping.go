@@ -0,0 +1,536 @@
+package ldcontext
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// structTagName is the struct tag key used by FromStruct and ToStruct.
+const structTagName = "ld"
+
+// FromStruct creates a single Context of the specified kind from the fields of a struct, based on
+// struct tags with the key "ld".
+//
+// Each tagged field becomes an attribute of the Context. The tag value is the attribute name,
+// optionally followed by ",private" to mark the attribute as private (see [Builder.Private]). The
+// attribute names "key", "name", and "anonymous" set the corresponding built-in attributes, and have
+// the same type restrictions as in [Builder.SetValue]. A tag of "-" means the field is ignored, and
+// fields with no "ld" tag are also ignored, so that data is never sent to LaunchDarkly unless it was
+// explicitly mapped.
+//
+//	type User struct {
+//		ID      string              `ld:"key"`
+//		Name    string              `ld:"name"`
+//		Email   string              `ld:"email,private"`
+//		Plan    string              `ld:"plan"`
+//		Address Address             `ld:"address"`
+//		Groups  []string            `ld:"groups"`
+//		Nick    *string             `ld:"nickname"`
+//		Age     ldvalue.OptionalInt `ld:"age"`
+//		Secret  string              `ld:"-"`
+//	}
+//
+//	context, err := ldcontext.FromStruct("user", user)
+//
+// Field values are converted as follows:
+//   - Booleans, numbers, and strings become the corresponding JSON primitive types.
+//   - Slices and arrays become JSON arrays; maps with string keys become JSON objects.
+//   - A nested struct that has any "ld" tags becomes a JSON object containing its tagged fields. Its
+//     tags can also use ",private", which marks that property (for instance, "/address/street") as
+//     private. A nested struct with no "ld" tags is converted as if by [ldvalue.FromJSONMarshal].
+//   - A nil pointer, nil slice, nil map, or an undefined optional type such as
+//     [ldvalue.OptionalString] is treated as an absent attribute.
+//   - [ldvalue.Value] and the other ldvalue types are used as-is.
+//   - Any other type, including any type that implements [encoding/json.Marshaler], is converted
+//     as if by [ldvalue.FromJSONMarshal].
+//
+// Embedded structs with no "ld" tag on the embedding field have their fields promoted, as in
+// [encoding/json].
+//
+// The value parameter must be a struct or a non-nil pointer to a struct. An error is returned if it
+// is not, if a field value cannot be used for its attribute (for instance, a non-string "key"), or if
+// the resulting Context is invalid as described in [Builder.Build].
+func FromStruct(kind Kind, value any) (Context, error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return Context{}, fmt.Errorf("ldcontext.FromStruct requires a struct, not %T", value)
+	}
+	fields, err := getStructMapping(rv.Type())
+	if err != nil {
+		return Context{}, err
+	}
+	b := NewBuilder("").Kind(kind)
+	for _, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			continue
+		}
+		attrValue, privateRefs, err := goValueToValue(fv, []string{f.name}, nil)
+		if err != nil {
+			return Context{}, err
+		}
+		if attrValue.IsNull() {
+			continue
+		}
+		if !b.TrySetValue(f.name, attrValue) {
+			return Context{}, fmt.Errorf("field %s of type %s cannot be used for the context attribute %q",
+				f.goName, fv.Type(), f.name)
+		}
+		if f.private {
+			b.PrivateRef(ldattr.NewLiteralRef(f.name))
+		}
+		b.PrivateRef(privateRefs...)
+	}
+	return b.TryBuild()
+}
+
+// ToStruct is the reverse of [FromStruct]: it sets the tagged fields of a struct from the attributes
+// of a single Context.
+//
+// The target parameter must be a non-nil pointer to a struct. Each field with an "ld" tag is set from
+// the attribute of the same name, using the same conversion rules as FromStruct in reverse; fields
+// whose attribute does not exist are set to their zero value. Fields of types that FromStruct would
+// convert with [ldvalue.FromJSONMarshal] are set with [encoding/json.Unmarshal]. Private attribute
+// metadata is not used.
+//
+// An error is returned if the Context is invalid or is a multi-context (use
+// [Context.IndividualContextByKind] to get a single context first), or if an attribute value cannot
+// be stored in the corresponding field, such as a string value for an int field.
+func ToStruct(c Context, target any) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	if c.Multiple() {
+		return fmt.Errorf("ldcontext.ToStruct cannot be used with a multi-context")
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ldcontext.ToStruct requires a non-nil pointer to a struct, not %T", target)
+	}
+	return setStructFromValues(rv.Elem(), c.GetValue)
+}
+
+type structFieldMapping struct {
+	index   []int
+	goName  string
+	name    string
+	private bool
+}
+
+var structMappingCache sync.Map //nolint:gochecknoglobals // cache of reflection metadata
+
+func getStructMapping(t reflect.Type) ([]structFieldMapping, error) {
+	if cached, ok := structMappingCache.Load(t); ok {
+		return cached.([]structFieldMapping), nil
+	}
+	fields, err := buildStructMapping(t, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]string, len(fields))
+	for _, f := range fields {
+		if other, ok := seen[f.name]; ok {
+			return nil, fmt.Errorf("fields %s and %s of %s have the same attribute name %q", other, f.goName, t, f.name)
+		}
+		seen[f.name] = f.goName
+	}
+	structMappingCache.Store(t, fields)
+	return fields, nil
+}
+
+// buildStructMapping returns the mappings for the fields of t, including any promoted fields of embedded
+// structs. The enclosing types parameter lists the structs that t is embedded in, if any.
+func buildStructMapping(t reflect.Type, parentIndex []int, enclosing []reflect.Type) ([]structFieldMapping, error) {
+	for _, et := range enclosing {
+		if et == t {
+			return nil, fmt.Errorf("struct %s cannot be used because it embeds itself", t)
+		}
+	}
+	var ret []structFieldMapping
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		index := append(append([]int(nil), parentIndex...), i)
+		tag, hasTag := sf.Tag.Lookup(structTagName)
+		if !hasTag {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if sf.Anonymous && embedded.Kind() == reflect.Struct {
+				promoted, err := buildStructMapping(embedded, index, append(enclosing[:len(enclosing):len(enclosing)], t))
+				if err != nil {
+					return nil, err
+				}
+				ret = append(ret, promoted...)
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		f := structFieldMapping{index: index, goName: sf.Name, name: name}
+		for _, opt := range strings.Split(options, ",") {
+			switch opt {
+			case "":
+			case "private":
+				f.private = true
+			default:
+				return nil, fmt.Errorf("field %s of %s has unrecognized %q tag option %q", sf.Name, t, structTagName, opt)
+			}
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, except that it returns false instead of
+// panicking if it encounters a nil embedded pointer.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+type valueConvertible interface {
+	AsValue() ldvalue.Value
+}
+
+var ( //nolint:gochecknoglobals // reflection type constants
+	valueType          = reflect.TypeOf(ldvalue.Value{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	valueConvertibleTp = reflect.TypeOf((*valueConvertible)(nil)).Elem()
+)
+
+// visitKey identifies a pointer, map, or slice that is being converted by goValueToValue, so that
+// cyclic data structures can be detected. The type is included because a pointer to a struct and a
+// pointer to its first field have the same address.
+type visitKey struct {
+	ptr    uintptr
+	t      reflect.Type
+	length int
+}
+
+// goValueToValue converts a field value for FromStruct. If path is non-nil, it is the path of the
+// value within the context attributes, and the returned Refs are the paths of any properties within
+// the value that should be private. The visiting map contains all of the pointers, maps, and slices
+// that are in the process of being converted; it is created when first needed.
+func goValueToValue(
+	rv reflect.Value,
+	path []string,
+	visiting map[visitKey]struct{},
+) (ldvalue.Value, []ldattr.Ref, error) {
+	t := rv.Type()
+	if rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Interface {
+		switch {
+		case t == valueType:
+			return rv.Interface().(ldvalue.Value), nil, nil
+		case t.Implements(valueConvertibleTp):
+			return rv.Interface().(valueConvertible).AsValue(), nil, nil
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return ldvalue.Null(), nil, nil
+		}
+		key := visitKey{ptr: rv.Pointer(), t: t}
+		if rv.Kind() == reflect.Slice {
+			key.length = rv.Len()
+		}
+		if _, ok := visiting[key]; ok {
+			return ldvalue.Null(), nil, fmt.Errorf("cannot convert value of type %s because it contains a cycle", t)
+		}
+		if visiting == nil {
+			visiting = make(map[visitKey]struct{})
+		}
+		visiting[key] = struct{}{}
+		defer delete(visiting, key)
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return ldvalue.Null(), nil, nil
+		}
+		return goValueToValue(rv.Elem(), path, visiting)
+	case reflect.Bool:
+		return ldvalue.Bool(rv.Bool()), nil, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ldvalue.Int64(rv.Int()), nil, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return ldvalue.Uint64(rv.Uint()), nil, nil
+	case reflect.Float32, reflect.Float64:
+		return ldvalue.Float64(rv.Float()), nil, nil
+	case reflect.String:
+		if t.Implements(jsonMarshalerType) {
+			break
+		}
+		return ldvalue.String(rv.String()), nil, nil
+	case reflect.Slice, reflect.Array:
+		if t.Implements(jsonMarshalerType) {
+			break
+		}
+		ab := ldvalue.ArrayBuildWithCapacity(rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, _, err := goValueToValue(rv.Index(i), nil, visiting)
+			if err != nil {
+				return ldvalue.Null(), nil, err
+			}
+			ab.Add(item)
+		}
+		return ab.Build(), nil, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || t.Implements(jsonMarshalerType) {
+			break
+		}
+		ob := ldvalue.ObjectBuildWithCapacity(rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			item, _, err := goValueToValue(iter.Value(), nil, visiting)
+			if err != nil {
+				return ldvalue.Null(), nil, err
+			}
+			ob.Set(iter.Key().String(), item)
+		}
+		return ob.Build(), nil, nil
+	case reflect.Struct:
+		if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			break
+		}
+		fields, err := getStructMapping(t)
+		if err != nil {
+			return ldvalue.Null(), nil, err
+		}
+		if len(fields) == 0 {
+			break
+		}
+		return structToValue(rv, fields, path, visiting)
+	}
+	data, err := json.Marshal(rv.Interface())
+	if err != nil {
+		return ldvalue.Null(), nil, err
+	}
+	return ldvalue.Parse(data), nil, nil
+}
+
+func structToValue(
+	rv reflect.Value,
+	fields []structFieldMapping,
+	path []string,
+	visiting map[visitKey]struct{},
+) (ldvalue.Value, []ldattr.Ref, error) {
+	var privateRefs []ldattr.Ref
+	ob := ldvalue.ObjectBuildWithCapacity(len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			continue
+		}
+		var childPath []string
+		if path != nil {
+			childPath = append(append([]string(nil), path...), f.name)
+		}
+		item, childPrivateRefs, err := goValueToValue(fv, childPath, visiting)
+		if err != nil {
+			return ldvalue.Null(), nil, err
+		}
+		if item.IsNull() {
+			continue
+		}
+		ob.Set(f.name, item)
+		if f.private && childPath != nil {
+			privateRefs = append(privateRefs, ldattr.NewRefFromComponents(childPath))
+		}
+		privateRefs = append(privateRefs, childPrivateRefs...)
+	}
+	return ob.Build(), privateRefs, nil
+}
+
+func setStructFromValues(rv reflect.Value, getValue func(string) ldvalue.Value) error {
+	fields, err := getStructMapping(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		fv, err := allocFieldByIndex(rv, f.index)
+		if err != nil {
+			return err
+		}
+		if err := valueToGoValue(getValue(f.name), fv); err != nil {
+			return fmt.Errorf("cannot set field %s from attribute %q: %w", f.goName, f.name, err)
+		}
+	}
+	return nil
+}
+
+// allocFieldByIndex is like reflect.Value.FieldByIndex, except that it allocates any nil embedded
+// pointers along the way.
+func allocFieldByIndex(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s",
+						rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
+
+func valueToGoValue(v ldvalue.Value, rv reflect.Value) error { //nolint:gocyclo // switch on all reflect kinds
+	t := rv.Type()
+	if t == valueType {
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if v.Type() == ldvalue.RawType {
+		v = ldvalue.Parse(v.AsRaw())
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return unmarshalValueInto(v, rv)
+	}
+	if v.IsNull() {
+		rv.Set(reflect.Zero(t))
+		return nil
+	}
+	wrongType := func() error {
+		return fmt.Errorf("value of type %s cannot be stored in %s", v.Type(), t)
+	}
+	switch rv.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		if err := valueToGoValue(v, elem.Elem()); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return wrongType()
+		}
+		if arbitrary := v.AsArbitraryValueWithExactNumbers(); arbitrary != nil {
+			rv.Set(reflect.ValueOf(arbitrary))
+		}
+		return nil
+	case reflect.Bool:
+		if !v.IsBool() {
+			return wrongType()
+		}
+		rv.SetBool(v.BoolValue())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := v.Int64Value()
+		if !v.IsInt() || !ok || rv.OverflowInt(n) {
+			return wrongType()
+		}
+		rv.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := v.Uint64Value()
+		if !v.IsInt() || !ok || rv.OverflowUint(n) {
+			return wrongType()
+		}
+		rv.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if !v.IsNumber() {
+			return wrongType()
+		}
+		rv.SetFloat(v.Float64Value())
+		return nil
+	case reflect.String:
+		if !v.IsString() {
+			return wrongType()
+		}
+		rv.SetString(v.StringValue())
+		return nil
+	case reflect.Slice:
+		if v.Type() != ldvalue.ArrayType {
+			return wrongType()
+		}
+		slice := reflect.MakeSlice(t, v.Count(), v.Count())
+		for i := 0; i < v.Count(); i++ {
+			if err := valueToGoValue(v.GetByIndex(i), slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Array:
+		if v.Type() != ldvalue.ArrayType {
+			return wrongType()
+		}
+		rv.Set(reflect.Zero(t))
+		for i := 0; i < v.Count() && i < rv.Len(); i++ {
+			if err := valueToGoValue(v.GetByIndex(i), rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			break
+		}
+		if v.Type() != ldvalue.ObjectType {
+			return wrongType()
+		}
+		m := reflect.MakeMapWithSize(t, v.Count())
+		for _, key := range v.Keys(nil) {
+			item := reflect.New(t.Elem()).Elem()
+			if err := valueToGoValue(v.GetByKey(key), item); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), item)
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Struct:
+		fields, err := getStructMapping(t)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			break
+		}
+		if v.Type() != ldvalue.ObjectType {
+			return wrongType()
+		}
+		rv.Set(reflect.Zero(t))
+		return setStructFromValues(rv, v.GetByKey)
+	}
+	return unmarshalValueInto(v, rv)
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem() //nolint:gochecknoglobals
+
+func unmarshalValueInto(v ldvalue.Value, rv reflect.Value) error {
+	target := reflect.New(rv.Type())
+	if err := json.Unmarshal([]byte(v.JSONString()), target.Interface()); err != nil {
+		return err
+	}
+	rv.Set(target.Elem())
+	return nil
+}
@@ -0,0 +1,280 @@
+package ldcontext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type structMappingAddress struct {
+	Street string `ld:"street,private"`
+	City   string `ld:"city"`
+}
+
+type structMappingBase struct {
+	Tenant string `ld:"tenant"`
+}
+
+type structMappingUser struct {
+	structMappingBase
+	ID        string               `ld:"key"`
+	Name      string               `ld:"name"`
+	Anonymous bool                 `ld:"anonymous"`
+	Email     string               `ld:"email,private"`
+	Seats     int                  `ld:"seats"`
+	Ratio     float64              `ld:"ratio"`
+	Address   structMappingAddress `ld:"address"`
+	Groups    []string             `ld:"groups"`
+	Limits    map[string]int       `ld:"limits"`
+	Nickname  *string              `ld:"nickname"`
+	Age       ldvalue.OptionalInt  `ld:"age"`
+	Extra     ldvalue.Value        `ld:"extra"`
+	Created   time.Time            `ld:"created"`
+	Plain     struct{ X int }      `ld:"plain"`
+	Any       any                  `ld:"any"`
+	Ignored   string               `ld:"-"`
+	Untagged  string
+	Ptr       *structMappingAddress `ld:"ptr"`
+}
+
+func makeStructMappingUser() structMappingUser {
+	nickname := "bob"
+	return structMappingUser{
+		structMappingBase: structMappingBase{Tenant: "t1"},
+		ID:                "user-key",
+		Name:              "Robert",
+		Anonymous:         true,
+		Email:             "bob@example.com",
+		Seats:             3,
+		Ratio:             0.5,
+		Address:           structMappingAddress{Street: "1 Main", City: "Oakland"},
+		Groups:            []string{"a", "b"},
+		Limits:            map[string]int{"x": 1},
+		Nickname:          &nickname,
+		Age:               ldvalue.NewOptionalInt(40),
+		Extra:             ldvalue.ArrayOf(ldvalue.Bool(true)),
+		Created:           time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Plain:             struct{ X int }{X: 7},
+		Any:               "anything",
+		Ignored:           "ignored",
+		Untagged:          "untagged",
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	c, err := FromStruct("user", makeStructMappingUser())
+	require.NoError(t, err)
+
+	expected := NewBuilder("user-key").
+		Name("Robert").
+		Anonymous(true).
+		SetString("tenant", "t1").
+		SetString("email", "bob@example.com").
+		SetInt("seats", 3).
+		SetFloat64("ratio", 0.5).
+		SetValue("address", ldvalue.ObjectBuild().SetString("street", "1 Main").SetString("city", "Oakland").Build()).
+		SetValue("groups", ldvalue.ArrayOf(ldvalue.String("a"), ldvalue.String("b"))).
+		SetValue("limits", ldvalue.ObjectBuild().SetInt("x", 1).Build()).
+		SetString("nickname", "bob").
+		SetInt("age", 40).
+		SetValue("extra", ldvalue.ArrayOf(ldvalue.Bool(true))).
+		SetString("created", "2024-01-02T03:04:05Z").
+		SetValue("plain", ldvalue.ObjectBuild().SetInt("X", 7).Build()).
+		SetString("any", "anything").
+		Private("email", "/address/street").
+		Build()
+	assert.True(t, expected.Equal(c), "expected %s, got %s", expected, c)
+
+	c1, err := FromStruct("org", &structMappingUser{ID: "org-key"})
+	require.NoError(t, err)
+	assert.Equal(t, Kind("org"), c1.Kind())
+	assert.Equal(t, "org-key", c1.Key())
+	assert.Equal(t, ldvalue.ObjectBuild().SetString("street", "").SetString("city", "").Build(),
+		c1.GetValue("address"))
+}
+
+func TestFromStructAbsentValues(t *testing.T) {
+	c, err := FromStruct("user", structMappingUser{ID: "k"})
+	require.NoError(t, err)
+	for _, name := range []string{"nickname", "age", "extra", "groups", "limits", "any", "ptr"} {
+		assert.False(t, c.GetValue(name).IsDefined(), name)
+	}
+	assert.Equal(t, ldvalue.String(""), c.GetValue("email"))
+}
+
+func TestFromStructErrors(t *testing.T) {
+	_, err := FromStruct("user", "not a struct")
+	assert.Error(t, err)
+
+	_, err = FromStruct("user", (*structMappingUser)(nil))
+	assert.Error(t, err)
+
+	_, err = FromStruct("user", struct {
+		Key int `ld:"key"`
+	}{Key: 1})
+	assert.Error(t, err)
+
+	_, err = FromStruct("user", struct {
+		A string `ld:"a"`
+		B string `ld:"a"`
+	}{})
+	assert.Error(t, err)
+
+	_, err = FromStruct("user", struct {
+		A string `ld:"a,unknown"`
+	}{})
+	assert.Error(t, err)
+
+	_, err = FromStruct("user", struct {
+		Key string `ld:"key"`
+	}{})
+	assert.Equal(t, lderrors.ErrContextKeyEmpty{}, err)
+
+	_, err = FromStruct("kind", struct {
+		Key string `ld:"key"`
+	}{Key: "x"})
+	assert.Equal(t, lderrors.ErrContextKindCannotBeKind{}, err)
+}
+
+type structMappingRecursiveEmbedding struct {
+	*structMappingRecursiveEmbedding
+	X int `ld:"x"`
+}
+
+type structMappingNode struct {
+	Key  string             `ld:"key"`
+	Next *structMappingNode `ld:"next"`
+	List []any              `ld:"list"`
+}
+
+func TestFromStructCycles(t *testing.T) {
+	t.Run("struct that embeds itself", func(t *testing.T) {
+		_, err := FromStruct("user", structMappingRecursiveEmbedding{X: 1})
+		assert.Error(t, err)
+		assert.Error(t, ToStruct(NewBuilder("a").SetInt("x", 1).Build(), &structMappingRecursiveEmbedding{}))
+	})
+
+	t.Run("recursive type without a cyclic value", func(t *testing.T) {
+		c, err := FromStruct("user", structMappingNode{Key: "a", Next: &structMappingNode{Key: "b"}})
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.ObjectBuild().SetString("key", "b").Build(), c.GetValue("next"))
+	})
+
+	t.Run("same pointer used twice without a cycle", func(t *testing.T) {
+		shared := &structMappingNode{Key: "b"}
+		c, err := FromStruct("user", structMappingNode{Key: "a", List: []any{shared, shared}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, c.GetValue("list").Count())
+	})
+
+	t.Run("cyclic pointer", func(t *testing.T) {
+		node := &structMappingNode{Key: "a"}
+		node.Next = node
+		_, err := FromStruct("user", node)
+		assert.Error(t, err)
+	})
+
+	t.Run("cyclic slice", func(t *testing.T) {
+		node := structMappingNode{Key: "a", List: make([]any, 1)}
+		node.List[0] = node.List
+		_, err := FromStruct("user", node)
+		assert.Error(t, err)
+	})
+
+	t.Run("cyclic map", func(t *testing.T) {
+		m := map[string]any{}
+		m["m"] = m
+		_, err := FromStruct("user", struct {
+			Key string         `ld:"key"`
+			M   map[string]any `ld:"m"`
+		}{Key: "a", M: m})
+		assert.Error(t, err)
+	})
+}
+
+func TestFromStructEscapesPrivatePaths(t *testing.T) {
+	type inner struct {
+		A string `ld:"a/b~c,private"`
+	}
+	c, err := FromStruct("user", struct {
+		Key   string `ld:"key"`
+		Inner inner  `ld:"x~/y"`
+	}{Key: "k", Inner: inner{A: "v"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, c.PrivateAttributeCount())
+	ref, _ := c.PrivateAttributeByIndex(0)
+	assert.Equal(t, "/x~0~1y/a~1b~0c", ref.String())
+}
+
+func TestToStruct(t *testing.T) {
+	original := makeStructMappingUser()
+	original.Any = nil
+	c, err := FromStruct("user", original)
+	require.NoError(t, err)
+
+	var u structMappingUser
+	u.Ignored = "unchanged"
+	require.NoError(t, ToStruct(c, &u))
+
+	expected := original
+	expected.Ignored = "unchanged"
+	expected.Untagged = ""
+	assert.Equal(t, expected, u)
+}
+
+func TestToStructArbitraryValue(t *testing.T) {
+	c := NewBuilder("k").SetValue("any", ldvalue.ArrayOf(ldvalue.Int(1))).Build()
+	var u structMappingUser
+	require.NoError(t, ToStruct(c, &u))
+	assert.Equal(t, []any{float64(1)}, u.Any)
+}
+
+func TestStructMappingLargeIntegers(t *testing.T) {
+	type ids struct {
+		Key      string `ld:"key"`
+		Signed   int64  `ld:"signed"`
+		Unsigned uint64 `ld:"unsigned"`
+	}
+	original := ids{Key: "k", Signed: -9007199254740993, Unsigned: 18446744073709551615}
+	c, err := FromStruct("user", original)
+	require.NoError(t, err)
+	assert.Equal(t, ldvalue.Int64(original.Signed), c.GetValue("signed"))
+	assert.Equal(t, ldvalue.Uint64(original.Unsigned), c.GetValue("unsigned"))
+
+	var result ids
+	require.NoError(t, ToStruct(c, &result))
+	assert.Equal(t, original, result)
+
+	var overflow struct {
+		Signed int64 `ld:"unsigned"`
+	}
+	assert.Error(t, ToStruct(c, &overflow))
+}
+
+func TestToStructErrors(t *testing.T) {
+	var u structMappingUser
+	assert.Error(t, ToStruct(Context{}, &u))
+	assert.Error(t, ToStruct(NewMulti(New("a"), NewWithKind("org", "b")), &u))
+	assert.Error(t, ToStruct(New("a"), u))
+	assert.Error(t, ToStruct(New("a"), (*structMappingUser)(nil)))
+
+	for _, c := range []Context{
+		NewBuilder("a").SetString("seats", "x").Build(),
+		NewBuilder("a").SetFloat64("seats", 1.5).Build(),
+		NewBuilder("a").SetInt("email", 1).Build(),
+		NewBuilder("a").SetString("groups", "x").Build(),
+		NewBuilder("a").SetValue("address", ldvalue.ArrayOf()).Build(),
+		NewBuilder("a").SetString("age", "x").Build(),
+		NewBuilder("a").SetString("limits", "x").Build(),
+	} {
+		t.Run(c.String(), func(t *testing.T) {
+			var u1 structMappingUser
+			assert.Error(t, ToStruct(c, &u1))
+		})
+	}
+}
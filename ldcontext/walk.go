@@ -0,0 +1,67 @@
+package ldcontext
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// Walk calls fn for every attribute of the Context, stopping early if fn returns false.
+//
+// For each individual context, the built-in attributes are visited first, in the order "kind",
+// "key", "name" (only if it has been set), and "anonymous"; then the custom attributes are visited
+// in an undefined order.
+//
+// For a multi-context, every individual context is visited in the same order as
+// [Context.IndividualContextByIndex], and the kind parameter indicates which one the attribute
+// belongs to. The multi-context itself has no attributes of its own, so the "multi" kind is
+// never visited.
+//
+// Walk does not descend into attributes whose value is a JSON object. Each property within such an
+// object would need its own Ref containing the full path to it, such as "/address/street", and that
+// cannot be created without allocating memory; Walk is meant to be used where allocations matter. To
+// visit those properties, use [ldvalue.Value.AsValueMap] and [ldvalue.ValueMap.Range] on the value
+// that was passed to fn, building the Ref with [ldattr.NewRefFromComponents] only if it is needed.
+// Likewise, a value created with [ldvalue.Raw] is passed to fn as it is, without being parsed.
+//
+// Private attribute settings have no effect on Walk; the Context's private attribute references
+// can be inspected with [Context.PrivateAttributeByIndex].
+//
+// Walk does not allocate any memory, unless an attribute name begins with a slash (which requires
+// escaping in the Ref).
+//
+// If the Context is uninitialized (Context{}), fn is never called.
+func (c Context) Walk(fn func(kind Kind, ref ldattr.Ref, value ldvalue.Value) bool) {
+	if fn == nil {
+		return
+	}
+	if c.multiContexts != nil {
+		for _, mc := range c.multiContexts {
+			if !mc.walkSingleKind(fn) {
+				return
+			}
+		}
+		return
+	}
+	if c.defined {
+		c.walkSingleKind(fn)
+	}
+}
+
+func (c Context) walkSingleKind(fn func(kind Kind, ref ldattr.Ref, value ldvalue.Value) bool) bool {
+	if !fn(c.kind, ldattr.NewLiteralRef(ldattr.KindAttr), ldvalue.String(string(c.kind))) ||
+		!fn(c.kind, ldattr.NewLiteralRef(ldattr.KeyAttr), ldvalue.String(c.key)) {
+		return false
+	}
+	if c.name.IsDefined() && !fn(c.kind, ldattr.NewLiteralRef(ldattr.NameAttr), c.name.AsValue()) {
+		return false
+	}
+	if !fn(c.kind, ldattr.NewLiteralRef(ldattr.AnonymousAttr), ldvalue.Bool(c.anonymous)) {
+		return false
+	}
+	continueWalk := true
+	c.attributes.Range(func(name string, value ldvalue.Value) bool {
+		continueWalk = fn(c.kind, ldattr.NewLiteralRef(name), value)
+		return continueWalk
+	})
+	return continueWalk
+}
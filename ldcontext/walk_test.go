@@ -0,0 +1,118 @@
+package ldcontext
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type walkedAttribute struct {
+	kind  Kind
+	ref   string
+	value ldvalue.Value
+}
+
+func walkAll(c Context) []walkedAttribute {
+	var ret []walkedAttribute
+	c.Walk(func(kind Kind, ref ldattr.Ref, value ldvalue.Value) bool {
+		ret = append(ret, walkedAttribute{kind, ref.String(), value})
+		return true
+	})
+	return ret
+}
+
+func TestWalkBuiltInAttributes(t *testing.T) {
+	t.Run("without name", func(t *testing.T) {
+		c := NewBuilder("my-key").Kind("org").Build()
+		assert.Equal(t, []walkedAttribute{
+			{"org", "kind", ldvalue.String("org")},
+			{"org", "key", ldvalue.String("my-key")},
+			{"org", "anonymous", ldvalue.Bool(false)},
+		}, walkAll(c))
+	})
+
+	t.Run("with name", func(t *testing.T) {
+		c := NewBuilder("my-key").Name("x").Anonymous(true).Build()
+		assert.Equal(t, []walkedAttribute{
+			{"user", "kind", ldvalue.String("user")},
+			{"user", "key", ldvalue.String("my-key")},
+			{"user", "name", ldvalue.String("x")},
+			{"user", "anonymous", ldvalue.Bool(true)},
+		}, walkAll(c))
+	})
+}
+
+func TestWalkCustomAttributes(t *testing.T) {
+	address := ldvalue.Parse([]byte(`{"street":{"line1":"x"}}`))
+	c := NewBuilder("my-key").SetInt("n", 1).SetValue("address", address).SetString("/a", "y").Build()
+	walked := walkAll(c)
+
+	assert.Len(t, walked, 6)
+	assert.Equal(t, walkedAttribute{"user", "anonymous", ldvalue.Bool(false)}, walked[2])
+
+	custom := make(map[string]ldvalue.Value)
+	for _, w := range walked[3:] {
+		assert.Equal(t, Kind("user"), w.kind)
+		custom[w.ref] = w.value
+	}
+	assert.Equal(t, map[string]ldvalue.Value{
+		"n":       ldvalue.Int(1),
+		"address": address,
+		"/~1a":    ldvalue.String("y"),
+	}, custom)
+
+	for _, w := range walked {
+		assert.Equal(t, w.value, c.GetValueForRef(ldattr.NewRef(w.ref)), "ref: %s", w.ref)
+	}
+}
+
+func TestWalkRawValueIsNotParsed(t *testing.T) {
+	raw := ldvalue.Raw([]byte(`{"b":true}`))
+	c := NewBuilder("my-key").SetValue("a", raw).Build()
+	assert.Equal(t, []walkedAttribute{
+		{"user", "a", raw},
+	}, walkAll(c)[3:])
+}
+
+func TestWalkMultiContext(t *testing.T) {
+	c := NewMulti(
+		NewBuilder("org-key").Kind("org").SetString("x", "1").Build(),
+		NewBuilder("user-key").Build(),
+	)
+	assert.Equal(t, []walkedAttribute{
+		{"org", "kind", ldvalue.String("org")},
+		{"org", "key", ldvalue.String("org-key")},
+		{"org", "anonymous", ldvalue.Bool(false)},
+		{"org", "x", ldvalue.String("1")},
+		{"user", "kind", ldvalue.String("user")},
+		{"user", "key", ldvalue.String("user-key")},
+		{"user", "anonymous", ldvalue.Bool(false)},
+	}, walkAll(c))
+}
+
+func TestWalkStopsEarly(t *testing.T) {
+	c := NewMulti(
+		NewBuilder("org-key").Kind("org").SetString("a", "b").Build(),
+		NewBuilder("user-key").Build(),
+	)
+	for _, stopAt := range []string{"kind", "key", "anonymous", "a"} {
+		t.Run(stopAt, func(t *testing.T) {
+			var refs []string
+			c.Walk(func(kind Kind, ref ldattr.Ref, value ldvalue.Value) bool {
+				refs = append(refs, ref.String())
+				return ref.String() != stopAt
+			})
+			assert.Equal(t, stopAt, refs[len(refs)-1])
+		})
+	}
+}
+
+func TestWalkUninitializedContext(t *testing.T) {
+	Context{}.Walk(func(Kind, ldattr.Ref, ldvalue.Value) bool {
+		assert.Fail(t, "should not have called function")
+		return true
+	})
+}
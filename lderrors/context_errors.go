@@ -13,6 +13,8 @@ const (
 	msgContextKeyEmpty               = "context key must not be empty"
 	msgContextKeyNull                = "context key must not be null"
 	msgContextKeyMissing             = `"key" property not found in JSON context object`
+	msgContextKeyInvalidUTF8         = "context key is not valid UTF-8"
+	msgContextKeyControlChars        = "context key contains control characters"
 	msgContextKindEmpty              = "context kind cannot be empty"
 	msgContextKindCannotBeKind       = `"kind" is not a valid context kind`
 	msgContextKindMultiForSingleKind = `single context cannot have the kind "multi"`
@@ -36,6 +38,15 @@ type ErrContextKeyNull struct{}
 // property.
 type ErrContextKeyMissing struct{}
 
+// ErrContextKeyInvalidUTF8 means that the ldcontext.Context Key field was not a valid UTF-8 string.
+// This is only checked if key validation was enabled with ldcontext.KeyPolicy.
+type ErrContextKeyInvalidUTF8 struct{}
+
+// ErrContextKeyControlChars means that the ldcontext.Context Key field contained Unicode control
+// characters, such as a newline or a NUL character. This is only checked if key validation was
+// enabled with ldcontext.KeyPolicy.
+type ErrContextKeyControlChars struct{}
+
 // ErrContextKindEmpty means that the "kind" property in the JSON representation of an
 // ldcontext.Context had an empty string value. This is specific to JSON unmarshaling, since if you
 // are creating a Context programmatically, an empty string is automatically changed to
@@ -71,10 +82,30 @@ type ErrContextPerKindErrors struct {
 	Errors map[string]error
 }
 
+// ErrContextFullyQualifiedKeyInvalid means that a string passed to
+// ldcontext.ParseFullyQualifiedKey was not in the format produced by
+// ldcontext.Context.FullyQualifiedKey.
+type ErrContextFullyQualifiedKeyInvalid struct {
+	// Position is the zero-based byte offset within the string where the problem was found.
+	Position int
+	// Message describes the problem.
+	Message string
+}
+
+// ErrContextAttributeInvalidValue means that you tried to set a Context attribute to a value that is not
+// allowed for it, such as a non-string value for "key", or tried to set an attribute whose name is
+// reserved, such as "_meta".
+type ErrContextAttributeInvalidValue struct {
+	// Attribute is the attribute name.
+	Attribute string
+}
+
 func (e ErrContextUninitialized) Error() string          { return msgContextUninitialized }
 func (e ErrContextKeyEmpty) Error() string               { return msgContextKeyEmpty }
 func (e ErrContextKeyNull) Error() string                { return msgContextKeyNull }
 func (e ErrContextKeyMissing) Error() string             { return msgContextKeyMissing }
+func (e ErrContextKeyInvalidUTF8) Error() string         { return msgContextKeyInvalidUTF8 }
+func (e ErrContextKeyControlChars) Error() string        { return msgContextKeyControlChars }
 func (e ErrContextKindEmpty) Error() string              { return msgContextKindEmpty }
 func (e ErrContextKindCannotBeKind) Error() string       { return msgContextKindCannotBeKind }
 func (e ErrContextKindMultiForSingleKind) Error() string { return msgContextKindMultiForSingleKind }
@@ -91,3 +122,11 @@ func (e ErrContextPerKindErrors) Error() string {
 	}
 	return strings.Join(messages, ", ")
 }
+
+func (e ErrContextFullyQualifiedKeyInvalid) Error() string {
+	return fmt.Sprintf("invalid fully-qualified context key at position %d: %s", e.Position, e.Message)
+}
+
+func (e ErrContextAttributeInvalidValue) Error() string {
+	return fmt.Sprintf("value is not allowed for the context attribute %q", e.Attribute)
+}
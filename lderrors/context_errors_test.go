@@ -16,6 +16,8 @@ func TestContextErrorMessages(t *testing.T) {
 		{ErrContextKeyEmpty{}, msgContextKeyEmpty},
 		{ErrContextKeyNull{}, msgContextKeyNull},
 		{ErrContextKeyMissing{}, msgContextKeyMissing},
+		{ErrContextKeyInvalidUTF8{}, msgContextKeyInvalidUTF8},
+		{ErrContextKeyControlChars{}, msgContextKeyControlChars},
 		{ErrContextKindEmpty{}, msgContextKindEmpty},
 		{ErrContextKindCannotBeKind{}, msgContextKindCannotBeKind},
 		{ErrContextKindMultiForSingleKind{}, msgContextKindMultiForSingleKind},
@@ -45,4 +47,14 @@ func TestContextErrorMessages(t *testing.T) {
 		}
 		assert.Equal(t, "(kind1) "+ErrContextKeyEmpty{}.Error()+", (kind2) "+ErrContextKeyNull{}.Error(), e2.Error())
 	})
+
+	t.Run("ErrContextFullyQualifiedKeyInvalid", func(t *testing.T) {
+		assert.Equal(t, "invalid fully-qualified context key at position 4: invalid escape sequence",
+			ErrContextFullyQualifiedKeyInvalid{Position: 4, Message: "invalid escape sequence"}.Error())
+	})
+
+	t.Run("ErrContextAttributeInvalidValue", func(t *testing.T) {
+		assert.Equal(t, `value is not allowed for the context attribute "key"`,
+			ErrContextAttributeInvalidValue{Attribute: "key"}.Error())
+	})
 }
@@ -0,0 +1,86 @@
+package lderrors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrContextLimitTooManyAttributes means that a Context was checked against an ldcontext.ContextLimits,
+// and one of its individual contexts had more custom attributes than ContextLimits.MaxAttributesPerKind.
+type ErrContextLimitTooManyAttributes struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Count is the number of custom attributes.
+	Count int
+	// Limit is the maximum number of custom attributes.
+	Limit int
+}
+
+// ErrContextLimitStringTooLong means that a Context was checked against an ldcontext.ContextLimits, and
+// it had a string value longer than ContextLimits.MaxStringLength.
+type ErrContextLimitStringTooLong struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Attribute is a reference to the attribute or nested property containing the string (in the same
+	// format as ldattr.Ref.String()). If the string is an element of an array, this refers to the array.
+	Attribute string
+	// Length is the length of the string in bytes.
+	Length int
+	// Limit is the maximum length in bytes.
+	Limit int
+}
+
+// ErrContextLimitTooDeep means that a Context was checked against an ldcontext.ContextLimits, and it had
+// an attribute whose value had more levels of nested arrays or objects than ContextLimits.MaxNestingDepth.
+type ErrContextLimitTooDeep struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Attribute is the attribute name (in the same format as ldattr.Ref.String()).
+	Attribute string
+	// Depth is the nesting depth of the attribute value.
+	Depth int
+	// Limit is the maximum nesting depth.
+	Limit int
+}
+
+// ErrContextLimitTooLarge means that a Context was checked against an ldcontext.ContextLimits, and its
+// estimated JSON size was greater than ContextLimits.MaxJSONSize.
+type ErrContextLimitTooLarge struct {
+	// Size is the estimated JSON size in bytes.
+	Size int
+	// Limit is the maximum size in bytes.
+	Limit int
+}
+
+// ErrContextLimitViolations means that a Context was checked against an ldcontext.ContextLimits and
+// exceeded one or more of the limits. There is a separate error for each problem that was found.
+type ErrContextLimitViolations struct {
+	// Errors contains the individual errors, such as ErrContextLimitStringTooLong.
+	Errors []error
+}
+
+func (e ErrContextLimitTooManyAttributes) Error() string {
+	return fmt.Sprintf("(%s) context has %d custom attributes, limit is %d", e.Kind, e.Count, e.Limit)
+}
+
+func (e ErrContextLimitStringTooLong) Error() string {
+	return fmt.Sprintf("(%s) attribute %q has a string of length %d, limit is %d", e.Kind, e.Attribute,
+		e.Length, e.Limit)
+}
+
+func (e ErrContextLimitTooDeep) Error() string {
+	return fmt.Sprintf("(%s) attribute %q has nesting depth %d, limit is %d", e.Kind, e.Attribute,
+		e.Depth, e.Limit)
+}
+
+func (e ErrContextLimitTooLarge) Error() string {
+	return fmt.Sprintf("context has estimated JSON size %d, limit is %d", e.Size, e.Limit)
+}
+
+func (e ErrContextLimitViolations) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, ", ")
+}
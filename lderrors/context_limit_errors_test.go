@@ -0,0 +1,25 @@
+package lderrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextLimitErrorMessages(t *testing.T) {
+	assert.Equal(t, `(user) context has 12 custom attributes, limit is 10`,
+		ErrContextLimitTooManyAttributes{Kind: "user", Count: 12, Limit: 10}.Error())
+	assert.Equal(t, `(user) attribute "/address/street" has a string of length 300, limit is 256`,
+		ErrContextLimitStringTooLong{Kind: "user", Attribute: "/address/street", Length: 300, Limit: 256}.Error())
+	assert.Equal(t, `(org) attribute "tree" has nesting depth 5, limit is 3`,
+		ErrContextLimitTooDeep{Kind: "org", Attribute: "tree", Depth: 5, Limit: 3}.Error())
+	assert.Equal(t, `context has estimated JSON size 40000, limit is 32768`,
+		ErrContextLimitTooLarge{Size: 40000, Limit: 32768}.Error())
+
+	e := ErrContextLimitViolations{Errors: []error{
+		ErrContextLimitTooManyAttributes{Kind: "user", Count: 12, Limit: 10},
+		ErrContextLimitTooLarge{Size: 40000, Limit: 32768},
+	}}
+	assert.Equal(t, ErrContextLimitTooManyAttributes{Kind: "user", Count: 12, Limit: 10}.Error()+", "+
+		ErrContextLimitTooLarge{Size: 40000, Limit: 32768}.Error(), e.Error())
+}
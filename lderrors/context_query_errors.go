@@ -0,0 +1,17 @@
+package lderrors
+
+import "fmt"
+
+// ErrContextQuerySyntax means that a context query expression passed to ldcontext.ParseQuery could
+// not be parsed.
+type ErrContextQuerySyntax struct {
+	// Position is the zero-based byte offset within the query string where the problem was found.
+	// If the query ended unexpectedly, this is the length of the string.
+	Position int
+	// Message describes the problem.
+	Message string
+}
+
+func (e ErrContextQuerySyntax) Error() string {
+	return fmt.Sprintf("invalid context query at position %d: %s", e.Position, e.Message)
+}
@@ -0,0 +1,12 @@
+package lderrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextQueryErrorMessages(t *testing.T) {
+	assert.Equal(t, `invalid context query at position 7: expected a value`,
+		ErrContextQuerySyntax{Position: 7, Message: "expected a value"}.Error())
+}
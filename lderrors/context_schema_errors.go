@@ -0,0 +1,97 @@
+package lderrors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrContextSchemaUnknownKind means that a Context was validated against an ldcontext.SchemaRegistry
+// that does not allow unregistered kinds, and the Context had a kind that was not registered.
+type ErrContextSchemaUnknownKind struct {
+	// Kind is the context kind (as a string).
+	Kind string
+}
+
+// ErrContextSchemaUnknownAttribute means that a Context was validated against an ldcontext.SchemaRegistry,
+// and it had an attribute that was not declared in the schema for its kind.
+type ErrContextSchemaUnknownAttribute struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Attribute is the attribute reference (in the same format as ldattr.Ref.String()).
+	Attribute string
+}
+
+// ErrContextSchemaMissingAttribute means that a Context was validated against an ldcontext.SchemaRegistry,
+// and it did not have an attribute that is required by the schema for its kind.
+type ErrContextSchemaMissingAttribute struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Attribute is the attribute reference (in the same format as ldattr.Ref.String()).
+	Attribute string
+}
+
+// ErrContextSchemaWrongType means that a Context was validated against an ldcontext.SchemaRegistry,
+// and it had an attribute whose value was not one of the types allowed by the schema for its kind.
+type ErrContextSchemaWrongType struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Attribute is the attribute reference (in the same format as ldattr.Ref.String()).
+	Attribute string
+	// ExpectedTypes are the names of the allowed value types (in the same format as
+	// ldvalue.ValueType.String()).
+	ExpectedTypes []string
+	// ActualType is the name of the value type that was found.
+	ActualType string
+}
+
+// ErrContextSchemaInvalidAttribute means that an ldcontext.KindSchema declared an attribute reference
+// that is not a valid ldattr.Ref, so the schema cannot be used.
+type ErrContextSchemaInvalidAttribute struct {
+	// Kind is the context kind (as a string).
+	Kind string
+	// Attribute is the attribute reference string that was declared.
+	Attribute string
+	// Err is the error from ldattr.Ref.Err(), such as ErrAttributeExtraSlash.
+	Err error
+}
+
+// ErrContextSchemaViolations means that a Context was validated against an ldcontext.SchemaRegistry and
+// did not conform to it. There is a separate error for each problem that was found.
+type ErrContextSchemaViolations struct {
+	// Errors contains the individual validation errors, such as ErrContextSchemaUnknownAttribute.
+	Errors []error
+}
+
+func (e ErrContextSchemaUnknownKind) Error() string {
+	return fmt.Sprintf("context kind %q is not defined in the schema", e.Kind)
+}
+
+func (e ErrContextSchemaUnknownAttribute) Error() string {
+	return fmt.Sprintf("(%s) attribute %q is not defined in the schema", e.Kind, e.Attribute)
+}
+
+func (e ErrContextSchemaMissingAttribute) Error() string {
+	return fmt.Sprintf("(%s) required attribute %q is missing", e.Kind, e.Attribute)
+}
+
+func (e ErrContextSchemaWrongType) Error() string {
+	return fmt.Sprintf("(%s) attribute %q has type %s, expected %s", e.Kind, e.Attribute, e.ActualType,
+		strings.Join(e.ExpectedTypes, " or "))
+}
+
+func (e ErrContextSchemaInvalidAttribute) Error() string {
+	return fmt.Sprintf("(%s) attribute reference %q in the schema is invalid: %s", e.Kind, e.Attribute, e.Err)
+}
+
+// Unwrap returns the error from ldattr.Ref.Err().
+func (e ErrContextSchemaInvalidAttribute) Unwrap() error {
+	return e.Err
+}
+
+func (e ErrContextSchemaViolations) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, ", ")
+}
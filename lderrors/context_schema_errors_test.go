@@ -0,0 +1,30 @@
+package lderrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextSchemaErrorMessages(t *testing.T) {
+	assert.Equal(t, `context kind "org" is not defined in the schema`,
+		ErrContextSchemaUnknownKind{Kind: "org"}.Error())
+	assert.Equal(t, `(user) attribute "plann" is not defined in the schema`,
+		ErrContextSchemaUnknownAttribute{Kind: "user", Attribute: "plann"}.Error())
+	assert.Equal(t, `(user) required attribute "/address/city" is missing`,
+		ErrContextSchemaMissingAttribute{Kind: "user", Attribute: "/address/city"}.Error())
+	assert.Equal(t, `(user) attribute "plan" has type string, expected number or bool`,
+		ErrContextSchemaWrongType{Kind: "user", Attribute: "plan", ExpectedTypes: []string{"number", "bool"},
+			ActualType: "string"}.Error())
+	assert.Equal(t, `(user) attribute reference "/a//b" in the schema is invalid: `+ErrAttributeExtraSlash{}.Error(),
+		ErrContextSchemaInvalidAttribute{Kind: "user", Attribute: "/a//b", Err: ErrAttributeExtraSlash{}}.Error())
+	assert.Equal(t, ErrAttributeExtraSlash{},
+		ErrContextSchemaInvalidAttribute{Kind: "user", Attribute: "/a//b", Err: ErrAttributeExtraSlash{}}.Unwrap())
+
+	e := ErrContextSchemaViolations{Errors: []error{
+		ErrContextSchemaUnknownKind{Kind: "org"},
+		ErrContextSchemaMissingAttribute{Kind: "user", Attribute: "plan"},
+	}}
+	assert.Equal(t, ErrContextSchemaUnknownKind{Kind: "org"}.Error()+", "+
+		ErrContextSchemaMissingAttribute{Kind: "user", Attribute: "plan"}.Error(), e.Error())
+}
@@ -1,6 +1,10 @@
 // Package lderrors provides identifiers for particular kinds of errors that can be returned by
-// code in [github.com/launchdarkly/go-sdk-common/v3/ldcontext] or
-// [github.com/launchdarkly/go-sdk-common/v3/ldattr].
+// code in [github.com/launchdarkly/go-sdk-common/v3/ldcontext],
+// [github.com/launchdarkly/go-sdk-common/v3/ldattr],
+// [github.com/launchdarkly/go-sdk-common/v3/ldvalue],
+// [github.com/launchdarkly/go-sdk-common/v3/ldtoml] (such as [ErrTOMLConversion] and
+// [ErrTOMLUnrepresentable]), or [github.com/launchdarkly/go-sdk-common/v3/ldyaml] (such as
+// [ErrYAMLConversion]).
 //
 // Errors are only defined here if they are specifically generated by those packages.
 // The LaunchDarkly Go SDK ([github.com/launchdarkly/go-server-sdk/v6]) may define its own error
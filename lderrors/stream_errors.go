@@ -0,0 +1,24 @@
+package lderrors
+
+import "fmt"
+
+// ErrStreamElementInvalid means that an element read by ldcontext.Decoder or ldvalue.Decoder could
+// not be unmarshaled. The stream itself was well-formed up to that point, so the Index and Offset
+// identify which element had the problem.
+type ErrStreamElementInvalid struct {
+	// Index is the zero-based index of the element within the stream.
+	Index int
+	// Offset is the zero-based byte offset within the stream where the element begins.
+	Offset int
+	// Err is the error that occurred when unmarshaling the element.
+	Err error
+}
+
+func (e ErrStreamElementInvalid) Error() string {
+	return fmt.Sprintf("element %d at position %d in JSON stream is invalid: %s", e.Index, e.Offset, e.Err)
+}
+
+// Unwrap returns the error that occurred when unmarshaling the element.
+func (e ErrStreamElementInvalid) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,18 @@
+package lderrors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamErrorMessages(t *testing.T) {
+	assert.Equal(t, "element 2 at position 30 in JSON stream is invalid: context key must not be empty",
+		ErrStreamElementInvalid{Index: 2, Offset: 30, Err: ErrContextKeyEmpty{}}.Error())
+}
+
+func TestStreamErrorUnwrap(t *testing.T) {
+	var target ErrContextKeyEmpty
+	assert.True(t, errors.As(ErrStreamElementInvalid{Err: ErrContextKeyEmpty{}}, &target))
+}
@@ -0,0 +1,36 @@
+package lderrors
+
+import "fmt"
+
+// ErrTOMLConversion means that a TOML document passed to ldtoml.Parse was not valid TOML, or could
+// not be converted to an ldvalue.Value because it contained a value that has no equivalent in JSON,
+// such as nan or inf.
+type ErrTOMLConversion struct {
+	// Line is the one-based line number where the problem was found.
+	Line int
+	// Column is the one-based column number, in bytes, where the problem was found.
+	Column int
+	// Message describes the problem.
+	Message string
+}
+
+// ErrTOMLUnrepresentable means that an ldvalue.Value passed to ldtoml.Marshal cannot be represented
+// in TOML, for instance because it contains a null value or because it is not a JSON object.
+type ErrTOMLUnrepresentable struct {
+	// Path is the location of the value within the original value, such as "a.b[1]", or "" if the
+	// problem is with the top-level value.
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+func (e ErrTOMLConversion) Error() string {
+	return fmt.Sprintf("cannot convert TOML at line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+func (e ErrTOMLUnrepresentable) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("cannot represent value in TOML: %s", e.Message)
+	}
+	return fmt.Sprintf("cannot represent value at %q in TOML: %s", e.Path, e.Message)
+}
@@ -0,0 +1,16 @@
+package lderrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOMLErrorMessages(t *testing.T) {
+	assert.Equal(t, `cannot convert TOML at line 4, column 1: key "a" is defined more than once`,
+		ErrTOMLConversion{Line: 4, Column: 1, Message: `key "a" is defined more than once`}.Error())
+	assert.Equal(t, `cannot represent value in TOML: the top-level value must be an object`,
+		ErrTOMLUnrepresentable{Message: "the top-level value must be an object"}.Error())
+	assert.Equal(t, `cannot represent value at "a.b[1]" in TOML: TOML has no null value`,
+		ErrTOMLUnrepresentable{Path: "a.b[1]", Message: "TOML has no null value"}.Error())
+}
@@ -0,0 +1,40 @@
+package lderrors
+
+import "fmt"
+
+// ErrValueNumberNotFinite means that you tried to produce canonical JSON for an ldvalue.Value that
+// contained a number that was NaN or infinite. Such numbers cannot be represented in JSON.
+type ErrValueNumberNotFinite struct{}
+
+// ErrValueRawJSONInvalid means that an operation needed to parse an ldvalue.Value that was created
+// from unparsed JSON data, but the data was not valid JSON.
+type ErrValueRawJSONInvalid struct{}
+
+// ErrValuePathEmpty means that you tried to set a value at a nested path within a JSON object, such
+// as with ldvalue.ObjectBuilder.TrySetPath, but the path had no components.
+//
+// This is distinct from ErrAttributeEmpty, which is about the syntax of attribute references; a
+// path of property names is not an attribute reference, and can never be invalid in any other way.
+type ErrValuePathEmpty struct{}
+
+// ErrValuePathNotObject means that you tried to set a value at a nested path, such as "/address/city", but
+// one of the path components other than the last one referred to an existing value that was not a JSON
+// object, so the path could not be followed.
+type ErrValuePathNotObject struct {
+	// Property is the name of the property whose value was not a JSON object.
+	Property string
+}
+
+const (
+	msgValueNumberNotFinite = "a number that is NaN or infinite cannot be represented in JSON"
+	msgValueRawJSONInvalid  = "unparsed JSON value was not valid JSON"
+	msgValuePathEmpty       = "path of JSON object properties cannot be empty"
+)
+
+func (e ErrValueNumberNotFinite) Error() string { return msgValueNumberNotFinite }
+func (e ErrValueRawJSONInvalid) Error() string  { return msgValueRawJSONInvalid }
+func (e ErrValuePathEmpty) Error() string       { return msgValuePathEmpty }
+
+func (e ErrValuePathNotObject) Error() string {
+	return fmt.Sprintf("cannot set a nested property within %q because its value is not a JSON object", e.Property)
+}
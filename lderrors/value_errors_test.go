@@ -0,0 +1,29 @@
+package lderrors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueErrorMessages(t *testing.T) {
+	params := []struct {
+		err     error
+		message string
+	}{
+		{ErrValueNumberNotFinite{}, msgValueNumberNotFinite},
+		{ErrValueRawJSONInvalid{}, msgValueRawJSONInvalid},
+		{ErrValuePathEmpty{}, msgValuePathEmpty},
+	}
+	for _, p := range params {
+		t.Run(fmt.Sprintf("%T", p.err), func(t *testing.T) {
+			assert.Equal(t, p.message, p.err.Error())
+		})
+	}
+}
+
+func TestValuePathNotObjectErrorMessage(t *testing.T) {
+	assert.Equal(t, `cannot set a nested property within "address" because its value is not a JSON object`,
+		ErrValuePathNotObject{Property: "address"}.Error())
+}
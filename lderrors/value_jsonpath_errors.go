@@ -0,0 +1,17 @@
+package lderrors
+
+import "fmt"
+
+// ErrValueJSONPathSyntax means that a JSONPath expression passed to ldvalue.ParseJSONPath could not
+// be parsed, or was not well-typed as defined in RFC 9535.
+type ErrValueJSONPathSyntax struct {
+	// Position is the zero-based byte offset within the expression where the problem was found.
+	// If the expression ended unexpectedly, this is the length of the string.
+	Position int
+	// Message describes the problem.
+	Message string
+}
+
+func (e ErrValueJSONPathSyntax) Error() string {
+	return fmt.Sprintf("invalid JSONPath at position %d: %s", e.Position, e.Message)
+}
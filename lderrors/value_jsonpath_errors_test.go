@@ -0,0 +1,12 @@
+package lderrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueJSONPathErrorMessages(t *testing.T) {
+	assert.Equal(t, `invalid JSONPath at position 3: expected "]"`,
+		ErrValueJSONPathSyntax{Position: 3, Message: `expected "]"`}.Error())
+}
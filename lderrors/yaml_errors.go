@@ -0,0 +1,19 @@
+package lderrors
+
+import "fmt"
+
+// ErrYAMLConversion means that a YAML document passed to ldyaml.Parse could not be converted to an
+// ldvalue.Value, because it used a feature that has no equivalent in JSON, such as a mapping key
+// that is not a string.
+type ErrYAMLConversion struct {
+	// Line is the one-based line number where the problem was found.
+	Line int
+	// Column is the one-based column number where the problem was found.
+	Column int
+	// Message describes the problem.
+	Message string
+}
+
+func (e ErrYAMLConversion) Error() string {
+	return fmt.Sprintf("cannot convert YAML at line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
@@ -0,0 +1,12 @@
+package lderrors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLErrorMessages(t *testing.T) {
+	assert.Equal(t, `cannot convert YAML at line 2, column 3: mapping key must be a string`,
+		ErrYAMLConversion{Line: 2, Column: 3, Message: "mapping key must be a string"}.Error())
+}
@@ -2,6 +2,9 @@ package ldreason
 
 import (
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
 )
 
 // EvaluationDetail is an object returned by the SDK's "detail" evaluation methods, such as
@@ -44,3 +47,53 @@ func NewEvaluationDetail(
 func NewEvaluationDetailForError(errorKind EvalErrorKind, defaultValue ldvalue.Value) EvaluationDetail {
 	return EvaluationDetail{Value: defaultValue, Reason: NewEvalReasonError(errorKind)}
 }
+
+// MarshalJSON implements custom JSON serialization for EvaluationDetail.
+//
+// The JSON representation is an object with the properties "value", "variationIndex" (omitted if
+// undefined), and "reason" (omitted if undefined).
+func (d EvaluationDetail) MarshalJSON() ([]byte, error) {
+	return jwriter.MarshalJSONWithWriter(d)
+}
+
+// UnmarshalJSON implements custom JSON deserialization for EvaluationDetail.
+func (d *EvaluationDetail) UnmarshalJSON(data []byte) error {
+	return jreader.UnmarshalJSONWithReader(data, d)
+}
+
+// ReadFromJSONReader provides JSON deserialization for use with the jsonstream API.
+//
+// This implementation is used by the SDK in cases where it is more efficient than [encoding/json.Unmarshal].
+// See [github.com/launchdarkly/go-jsonstream/v3] for more details.
+func (d *EvaluationDetail) ReadFromJSONReader(reader *jreader.Reader) {
+	var ret EvaluationDetail
+	for obj := reader.ObjectOrNull(); obj.Next(); {
+		switch string(obj.Name()) {
+		case "value":
+			ret.Value.ReadFromJSONReader(reader)
+		case "variationIndex":
+			ret.VariationIndex.ReadFromJSONReader(reader)
+		case "reason":
+			ret.Reason.ReadFromJSONReader(reader)
+		}
+	}
+	if reader.Error() == nil {
+		*d = ret
+	}
+}
+
+// WriteToJSONWriter provides JSON serialization for use with the jsonstream API.
+//
+// This implementation is used by the SDK in cases where it is more efficient than [encoding/json.Marshal].
+// See [github.com/launchdarkly/go-jsonstream/v3] for more details.
+func (d EvaluationDetail) WriteToJSONWriter(w *jwriter.Writer) {
+	obj := w.Object()
+	d.Value.WriteToJSONWriter(obj.Name("value"))
+	if d.VariationIndex.IsDefined() {
+		obj.Name("variationIndex").Int(d.VariationIndex.IntValue())
+	}
+	if d.Reason.IsDefined() {
+		d.Reason.WriteToJSONWriter(obj.Name("reason"))
+	}
+	obj.End()
+}
@@ -0,0 +1,36 @@
+//go:build launchdarkly_easyjson
+
+package ldreason
+
+import (
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
+
+	"github.com/mailru/easyjson/jlexer"
+	ej_jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// This conditionally-compiled file provides custom marshal/unmarshal functions for the EvaluationDetail
+// and TypedEvaluationDetail types in EasyJSON. See reason_serialization_easyjson.go for the rationale.
+
+func (d EvaluationDetail) MarshalEasyJSON(writer *ej_jwriter.Writer) {
+	wrappedWriter := jwriter.NewWriterFromEasyJSONWriter(writer)
+	d.WriteToJSONWriter(&wrappedWriter)
+}
+
+func (d *EvaluationDetail) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
+	wrappedReader := jreader.NewReaderFromEasyJSONLexer(lexer)
+	d.ReadFromJSONReader(&wrappedReader)
+	lexer.AddError(wrappedReader.Error())
+}
+
+func (d TypedEvaluationDetail[T]) MarshalEasyJSON(writer *ej_jwriter.Writer) {
+	wrappedWriter := jwriter.NewWriterFromEasyJSONWriter(writer)
+	d.WriteToJSONWriter(&wrappedWriter)
+}
+
+func (d *TypedEvaluationDetail[T]) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
+	wrappedReader := jreader.NewReaderFromEasyJSONLexer(lexer)
+	d.ReadFromJSONReader(&wrappedReader)
+	lexer.AddError(wrappedReader.Error())
+}
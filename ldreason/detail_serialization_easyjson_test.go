@@ -0,0 +1,27 @@
+//go:build launchdarkly_easyjson
+
+package ldreason
+
+import (
+	"testing"
+
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedDetailEasyJSON(t *testing.T) {
+	detail := NewTypedEvaluationDetail(3, 1, NewEvalReasonRuleMatch(0, "id"))
+	expectedJSON := `{"value":3,"variationIndex":1,"reason":{"kind":"RULE_MATCH","ruleIndex":0,"ruleId":"id"}}`
+
+	data, err := easyjson.Marshal(detail)
+	require.NoError(t, err)
+	assert.Equal(t, expectedJSON, string(data))
+
+	var detail1 TypedEvaluationDetail[int]
+	require.NoError(t, easyjson.Unmarshal(data, &detail1))
+	assert.Equal(t, detail, detail1)
+
+	var wrongType TypedEvaluationDetail[string]
+	assert.Error(t, easyjson.Unmarshal(data, &wrongType))
+}
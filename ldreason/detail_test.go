@@ -1,9 +1,14 @@
 package ldreason
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
 )
@@ -23,3 +28,67 @@ func TestDetailErrorConstructor(t *testing.T) {
 	assert.Equal(t, NewEvalReasonError(EvalErrorFlagNotFound), detail.Reason)
 	assert.True(t, detail.IsDefaultValue())
 }
+
+func TestDetailSerializationAndDeserialization(t *testing.T) {
+	params := []struct {
+		detail       EvaluationDetail
+		expectedJSON string
+	}{
+		{
+			NewEvaluationDetail(ldvalue.Bool(true), 1, NewEvalReasonFallthrough()),
+			`{"value":true,"variationIndex":1,"reason":{"kind":"FALLTHROUGH"}}`,
+		},
+		{
+			NewEvaluationDetail(ldvalue.ObjectBuild().SetString("a", "b").Build(), 0, NewEvalReasonRuleMatch(0, "id")),
+			`{"value":{"a":"b"},"variationIndex":0,"reason":{"kind":"RULE_MATCH","ruleIndex":0,"ruleId":"id"}}`,
+		},
+		{
+			NewEvaluationDetailForError(EvalErrorFlagNotFound, ldvalue.String("default")),
+			`{"value":"default","reason":{"kind":"ERROR","errorKind":"FLAG_NOT_FOUND"}}`,
+		},
+		{
+			EvaluationDetail{},
+			`{"value":null}`,
+		},
+	}
+	for _, p := range params {
+		t.Run(p.expectedJSON, func(t *testing.T) {
+			actual, err := json.Marshal(p.detail)
+			require.NoError(t, err)
+			assert.JSONEq(t, p.expectedJSON, string(actual))
+
+			w := jwriter.NewWriter()
+			p.detail.WriteToJSONWriter(&w)
+			require.NoError(t, w.Error())
+			assert.JSONEq(t, p.expectedJSON, string(w.Bytes()))
+
+			var d1 EvaluationDetail
+			require.NoError(t, json.Unmarshal(actual, &d1))
+			assert.Equal(t, p.detail, d1)
+
+			var d2 EvaluationDetail
+			reader := jreader.NewReader(actual)
+			d2.ReadFromJSONReader(&reader)
+			require.NoError(t, reader.Error())
+			assert.Equal(t, p.detail, d2)
+		})
+	}
+
+	t.Run("null variationIndex", func(t *testing.T) {
+		var d EvaluationDetail
+		require.NoError(t, json.Unmarshal([]byte(`{"value":1,"variationIndex":null,"extra":true}`), &d))
+		assert.Equal(t, EvaluationDetail{Value: ldvalue.Int(1)}, d)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var d EvaluationDetail
+		require.NoError(t, json.Unmarshal([]byte(`null`), &d))
+		assert.Equal(t, EvaluationDetail{}, d)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		var d EvaluationDetail
+		assert.Error(t, json.Unmarshal([]byte(`{"variationIndex":"x"}`), &d))
+		assert.Error(t, json.Unmarshal([]byte(`[]`), &d))
+	})
+}
@@ -0,0 +1,172 @@
+package ldreason
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
+)
+
+// TypedEvaluationDetail is equivalent to [EvaluationDetail], except that the result value has a
+// specific Go type rather than being an [ldvalue.Value]. It is meant for wrappers around
+// typed evaluation methods such as [github.com/launchdarkly/go-server-sdk/v6.LDClient.BoolVariationDetail].
+//
+// A TypedEvaluationDetail can be converted to and from an EvaluationDetail with
+// [TypedEvaluationDetail.ToEvaluationDetail] and [NewTypedEvaluationDetailFromDetail]. Its JSON
+// representation is the same as that of the equivalent EvaluationDetail.
+type TypedEvaluationDetail[T any] struct {
+	// Value is the result of the flag evaluation. This will be either one of the flag's variations or
+	// the default value that was passed to the evaluation method.
+	Value T
+	// VariationIndex is the index of the returned value within the flag's list of variations, or an
+	// undefined value if the application default value was returned. See [EvaluationDetail.VariationIndex].
+	VariationIndex ldvalue.OptionalInt
+	// Reason is an EvaluationReason object describing the main factor that influenced the flag
+	// evaluation value.
+	Reason EvaluationReason
+}
+
+// NewTypedEvaluationDetail constructs a TypedEvaluationDetail, specifying all fields. This assumes
+// that there is a defined value for variationIndex; if variationIndex is undefined, use
+// [NewTypedEvaluationDetailForError] or set the struct fields directly.
+func NewTypedEvaluationDetail[T any](
+	value T,
+	variationIndex int,
+	reason EvaluationReason,
+) TypedEvaluationDetail[T] {
+	return TypedEvaluationDetail[T]{Value: value, VariationIndex: ldvalue.NewOptionalInt(variationIndex), Reason: reason}
+}
+
+// NewTypedEvaluationDetailForError constructs a TypedEvaluationDetail for an error condition.
+func NewTypedEvaluationDetailForError[T any](errorKind EvalErrorKind, defaultValue T) TypedEvaluationDetail[T] {
+	return TypedEvaluationDetail[T]{Value: defaultValue, Reason: NewEvalReasonError(errorKind)}
+}
+
+// NewTypedEvaluationDetailFromDetail converts an EvaluationDetail to a TypedEvaluationDetail, using
+// the convert function to get a value of type T from the [ldvalue.Value]. The convert function
+// should return false if the value is not of the desired type.
+//
+// If the conversion fails, the result has the specified default value and an error reason of
+// [EvalErrorWrongType], just as the SDK does when a typed evaluation method gets a value of the wrong
+// type. The exception is if the original detail was already for the application default value (see
+// [EvaluationDetail.IsDefaultValue]); in that case its reason is kept.
+//
+// For the basic JSON types, [NewBoolEvaluationDetail], [NewIntEvaluationDetail],
+// [NewFloat64EvaluationDetail], and [NewStringEvaluationDetail] provide the same type checking as the
+// SDK's typed evaluation methods.
+func NewTypedEvaluationDetailFromDetail[T any](
+	detail EvaluationDetail,
+	defaultValue T,
+	convert func(ldvalue.Value) (T, bool),
+) TypedEvaluationDetail[T] {
+	if value, ok := convert(detail.Value); ok {
+		return TypedEvaluationDetail[T]{Value: value, VariationIndex: detail.VariationIndex, Reason: detail.Reason}
+	}
+	if detail.IsDefaultValue() && detail.Reason.IsDefined() {
+		return TypedEvaluationDetail[T]{Value: defaultValue, Reason: detail.Reason}
+	}
+	return NewTypedEvaluationDetailForError(EvalErrorWrongType, defaultValue)
+}
+
+// NewBoolEvaluationDetail converts an EvaluationDetail to a TypedEvaluationDetail for a boolean
+// value. See [NewTypedEvaluationDetailFromDetail].
+func NewBoolEvaluationDetail(detail EvaluationDetail, defaultValue bool) TypedEvaluationDetail[bool] {
+	return NewTypedEvaluationDetailFromDetail(detail, defaultValue, func(v ldvalue.Value) (bool, bool) {
+		return v.BoolValue(), v.IsBool()
+	})
+}
+
+// NewIntEvaluationDetail converts an EvaluationDetail to a TypedEvaluationDetail for an integer
+// value. Any numeric value is accepted; non-integer values are truncated as in
+// [ldvalue.Value.IntValue]. See [NewTypedEvaluationDetailFromDetail].
+func NewIntEvaluationDetail(detail EvaluationDetail, defaultValue int) TypedEvaluationDetail[int] {
+	return NewTypedEvaluationDetailFromDetail(detail, defaultValue, func(v ldvalue.Value) (int, bool) {
+		return v.IntValue(), v.IsNumber()
+	})
+}
+
+// NewFloat64EvaluationDetail converts an EvaluationDetail to a TypedEvaluationDetail for a
+// floating-point value. See [NewTypedEvaluationDetailFromDetail].
+func NewFloat64EvaluationDetail(detail EvaluationDetail, defaultValue float64) TypedEvaluationDetail[float64] {
+	return NewTypedEvaluationDetailFromDetail(detail, defaultValue, func(v ldvalue.Value) (float64, bool) {
+		return v.Float64Value(), v.IsNumber()
+	})
+}
+
+// NewStringEvaluationDetail converts an EvaluationDetail to a TypedEvaluationDetail for a string
+// value. See [NewTypedEvaluationDetailFromDetail].
+func NewStringEvaluationDetail(detail EvaluationDetail, defaultValue string) TypedEvaluationDetail[string] {
+	return NewTypedEvaluationDetailFromDetail(detail, defaultValue, func(v ldvalue.Value) (string, bool) {
+		return v.StringValue(), v.IsString()
+	})
+}
+
+// IsDefaultValue returns true if the result of the evaluation was the application default value.
+// See [EvaluationDetail.IsDefaultValue].
+func (d TypedEvaluationDetail[T]) IsDefaultValue() bool {
+	return !d.VariationIndex.IsDefined()
+}
+
+// ToEvaluationDetail converts the TypedEvaluationDetail to an EvaluationDetail. The value is
+// converted as if by [ldvalue.CopyArbitraryValue].
+func (d TypedEvaluationDetail[T]) ToEvaluationDetail() EvaluationDetail {
+	return EvaluationDetail{
+		Value:          ldvalue.CopyArbitraryValue(d.Value),
+		VariationIndex: d.VariationIndex,
+		Reason:         d.Reason,
+	}
+}
+
+// MarshalJSON implements custom JSON serialization for TypedEvaluationDetail. The output is the same
+// as for the equivalent EvaluationDetail.
+func (d TypedEvaluationDetail[T]) MarshalJSON() ([]byte, error) {
+	return jwriter.MarshalJSONWithWriter(d)
+}
+
+// UnmarshalJSON implements custom JSON deserialization for TypedEvaluationDetail. It accepts the
+// same input as EvaluationDetail; the value must be either null or a JSON representation of a
+// value of type T.
+func (d *TypedEvaluationDetail[T]) UnmarshalJSON(data []byte) error {
+	return jreader.UnmarshalJSONWithReader(data, d)
+}
+
+// ReadFromJSONReader provides JSON deserialization for use with the jsonstream API.
+//
+// The value is read in the same way as for [ldvalue.Optional.ReadFromJSONReader], so this does not
+// use reflection if T is bool, int, float64, string, or [ldvalue.Value]. A null value is read as the
+// zero value of T. See [github.com/launchdarkly/go-jsonstream/v3] for more details.
+func (d *TypedEvaluationDetail[T]) ReadFromJSONReader(reader *jreader.Reader) {
+	var value ldvalue.Optional[T]
+	var ret TypedEvaluationDetail[T]
+	for obj := reader.ObjectOrNull(); obj.Next(); {
+		switch string(obj.Name()) {
+		case "value":
+			value.ReadFromJSONReader(reader)
+		case "variationIndex":
+			ret.VariationIndex.ReadFromJSONReader(reader)
+		case "reason":
+			ret.Reason.ReadFromJSONReader(reader)
+		}
+	}
+	if reader.Error() == nil {
+		ret.Value = value.Value()
+		*d = ret
+	}
+}
+
+// WriteToJSONWriter provides JSON serialization for use with the jsonstream API.
+//
+// The value is written in the same way as for [ldvalue.Optional.WriteToJSONWriter], so this does not
+// use reflection if T is bool, int, float64, or string, or if T implements jwriter.Writable. See
+// [github.com/launchdarkly/go-jsonstream/v3] for more details.
+func (d TypedEvaluationDetail[T]) WriteToJSONWriter(w *jwriter.Writer) {
+	obj := w.Object()
+	ldvalue.NewOptional(d.Value).WriteToJSONWriter(obj.Name("value"))
+	if d.VariationIndex.IsDefined() {
+		obj.Name("variationIndex").Int(d.VariationIndex.IntValue())
+	}
+	if d.Reason.IsDefined() {
+		d.Reason.WriteToJSONWriter(obj.Name("reason"))
+	}
+	obj.End()
+}
@@ -0,0 +1,147 @@
+package ldreason
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+func TestTypedDetailConstructor(t *testing.T) {
+	detail := NewTypedEvaluationDetail("x", 1, NewEvalReasonFallthrough())
+	assert.Equal(t, "x", detail.Value)
+	assert.Equal(t, ldvalue.NewOptionalInt(1), detail.VariationIndex)
+	assert.Equal(t, NewEvalReasonFallthrough(), detail.Reason)
+	assert.False(t, detail.IsDefaultValue())
+}
+
+func TestTypedDetailErrorConstructor(t *testing.T) {
+	detail := NewTypedEvaluationDetailForError(EvalErrorFlagNotFound, 3)
+	assert.Equal(t, 3, detail.Value)
+	assert.Equal(t, ldvalue.OptionalInt{}, detail.VariationIndex)
+	assert.Equal(t, NewEvalReasonError(EvalErrorFlagNotFound), detail.Reason)
+	assert.True(t, detail.IsDefaultValue())
+}
+
+func TestTypedDetailToEvaluationDetail(t *testing.T) {
+	assert.Equal(t, NewEvaluationDetail(ldvalue.Bool(true), 1, NewEvalReasonFallthrough()),
+		NewTypedEvaluationDetail(true, 1, NewEvalReasonFallthrough()).ToEvaluationDetail())
+	assert.Equal(t, NewEvaluationDetailForError(EvalErrorFlagNotFound, ldvalue.String("x")),
+		NewTypedEvaluationDetailForError(EvalErrorFlagNotFound, "x").ToEvaluationDetail())
+	assert.Equal(t, NewEvaluationDetail(ldvalue.ArrayOf(ldvalue.Int(1)), 0, NewEvalReasonOff()),
+		NewTypedEvaluationDetail([]int{1}, 0, NewEvalReasonOff()).ToEvaluationDetail())
+}
+
+func TestTypedDetailFromDetail(t *testing.T) {
+	reason := NewEvalReasonRuleMatch(0, "id")
+
+	t.Run("bool", func(t *testing.T) {
+		assert.Equal(t, NewTypedEvaluationDetail(true, 1, reason),
+			NewBoolEvaluationDetail(NewEvaluationDetail(ldvalue.Bool(true), 1, reason), false))
+		assert.Equal(t, NewTypedEvaluationDetailForError(EvalErrorWrongType, false),
+			NewBoolEvaluationDetail(NewEvaluationDetail(ldvalue.String("x"), 1, reason), false))
+	})
+
+	t.Run("int", func(t *testing.T) {
+		assert.Equal(t, NewTypedEvaluationDetail(2, 1, reason),
+			NewIntEvaluationDetail(NewEvaluationDetail(ldvalue.Float64(2.5), 1, reason), 0))
+		assert.Equal(t, NewTypedEvaluationDetailForError(EvalErrorWrongType, 9),
+			NewIntEvaluationDetail(NewEvaluationDetail(ldvalue.Bool(true), 1, reason), 9))
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		assert.Equal(t, NewTypedEvaluationDetail(2.5, 1, reason),
+			NewFloat64EvaluationDetail(NewEvaluationDetail(ldvalue.Float64(2.5), 1, reason), 0))
+		assert.Equal(t, NewTypedEvaluationDetailForError(EvalErrorWrongType, 1.5),
+			NewFloat64EvaluationDetail(NewEvaluationDetail(ldvalue.Null(), 1, reason), 1.5))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		assert.Equal(t, NewTypedEvaluationDetail("x", 1, reason),
+			NewStringEvaluationDetail(NewEvaluationDetail(ldvalue.String("x"), 1, reason), ""))
+		assert.Equal(t, NewTypedEvaluationDetailForError(EvalErrorWrongType, "d"),
+			NewStringEvaluationDetail(NewEvaluationDetail(ldvalue.Int(1), 1, reason), "d"))
+	})
+
+	t.Run("existing error reason is kept", func(t *testing.T) {
+		detail := NewEvaluationDetailForError(EvalErrorFlagNotFound, ldvalue.Null())
+		assert.Equal(t, NewTypedEvaluationDetailForError(EvalErrorFlagNotFound, "d"),
+			NewStringEvaluationDetail(detail, "d"))
+	})
+
+	t.Run("custom conversion", func(t *testing.T) {
+		detail := NewEvaluationDetail(ldvalue.ArrayOf(ldvalue.String("a")), 0, reason)
+		typed := NewTypedEvaluationDetailFromDetail(detail, nil, func(v ldvalue.Value) ([]string, bool) {
+			if v.Type() != ldvalue.ArrayType {
+				return nil, false
+			}
+			var ret []string
+			for _, item := range v.AsValueArray().AsSlice() {
+				ret = append(ret, item.StringValue())
+			}
+			return ret, true
+		})
+		assert.Equal(t, NewTypedEvaluationDetail([]string{"a"}, 0, reason), typed)
+	})
+}
+
+func TestTypedDetailJSON(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		typedDetailJSONTest(t, NewTypedEvaluationDetail("x", 1, NewEvalReasonRuleMatch(0, "id")),
+			`{"value":"x","variationIndex":1,"reason":{"kind":"RULE_MATCH","ruleIndex":0,"ruleId":"id"}}`)
+		typedDetailJSONTest(t, NewTypedEvaluationDetailForError(EvalErrorFlagNotFound, "d"),
+			`{"value":"d","reason":{"kind":"ERROR","errorKind":"FLAG_NOT_FOUND"}}`)
+	})
+	t.Run("bool", func(t *testing.T) {
+		typedDetailJSONTest(t, NewTypedEvaluationDetail(true, 0, NewEvalReasonFallthrough()),
+			`{"value":true,"variationIndex":0,"reason":{"kind":"FALLTHROUGH"}}`)
+	})
+	t.Run("int", func(t *testing.T) {
+		typedDetailJSONTest(t, NewTypedEvaluationDetail(3, 2, NewEvalReasonOff()),
+			`{"value":3,"variationIndex":2,"reason":{"kind":"OFF"}}`)
+	})
+	t.Run("float64", func(t *testing.T) {
+		typedDetailJSONTest(t, NewTypedEvaluationDetail(1.5, 2, NewEvalReasonOff()),
+			`{"value":1.5,"variationIndex":2,"reason":{"kind":"OFF"}}`)
+	})
+	t.Run("Value", func(t *testing.T) {
+		typedDetailJSONTest(t, NewTypedEvaluationDetail(ldvalue.ArrayOf(ldvalue.Int(1)), 0, NewEvalReasonOff()),
+			`{"value":[1],"variationIndex":0,"reason":{"kind":"OFF"}}`)
+	})
+	t.Run("other type", func(t *testing.T) {
+		typedDetailJSONTest(t, NewTypedEvaluationDetail([]string{"a"}, 0, NewEvalReasonOff()),
+			`{"value":["a"],"variationIndex":0,"reason":{"kind":"OFF"}}`)
+	})
+	t.Run("no reason", func(t *testing.T) {
+		typedDetailJSONTest(t, TypedEvaluationDetail[string]{Value: "x"}, `{"value":"x"}`)
+	})
+
+	t.Run("null value is read as zero value", func(t *testing.T) {
+		var detail TypedEvaluationDetail[string]
+		require.NoError(t, json.Unmarshal([]byte(`{"value":null,"variationIndex":1}`), &detail))
+		assert.Equal(t, TypedEvaluationDetail[string]{VariationIndex: ldvalue.NewOptionalInt(1)}, detail)
+	})
+
+	t.Run("wrong value type", func(t *testing.T) {
+		var detail TypedEvaluationDetail[int]
+		data, _ := json.Marshal(NewTypedEvaluationDetail("x", 1, NewEvalReasonOff()))
+		assert.Error(t, json.Unmarshal(data, &detail))
+	})
+}
+
+func typedDetailJSONTest[T any](t *testing.T, detail TypedEvaluationDetail[T], expectedJSON string) {
+	actual, err := json.Marshal(detail)
+	require.NoError(t, err)
+	assert.Equal(t, expectedJSON, string(actual))
+
+	untyped, err := json.Marshal(detail.ToEvaluationDetail())
+	require.NoError(t, err)
+	assert.Equal(t, expectedJSON, string(untyped))
+
+	var detail1 TypedEvaluationDetail[T]
+	require.NoError(t, json.Unmarshal(actual, &detail1))
+	assert.Equal(t, detail, detail1)
+}
@@ -1,6 +1,7 @@
 package ldreason
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
@@ -83,6 +84,10 @@ const (
 // EvaluationReason describes the reason that a flag evaluation producted a particular value.
 //
 // This struct is immutable; its properties can be accessed only via getter methods.
+//
+// An EvaluationReason may optionally include trace information describing the full evaluation path,
+// such as [EvaluationReason.GetPrerequisites]. Like other EvaluationReason properties, it is compared
+// by value when using the == operator.
 type EvaluationReason struct {
 	kind              EvalReasonKind
 	ruleIndex         ldvalue.OptionalInt
@@ -91,6 +96,9 @@ type EvaluationReason struct {
 	inExperiment      bool
 	errorKind         EvalErrorKind
 	bigSegmentsStatus BigSegmentsStatus
+	clauseIndex       ldvalue.OptionalInt
+	targetContextKind string
+	prerequisites     string // JSON representation; see encodePrerequisites
 }
 
 // IsDefined returns true if this EvaluationReason has a non-empty [EvaluationReason.GetKind]. It is
@@ -254,6 +262,12 @@ func (r *EvaluationReason) ReadFromJSONReader(reader *jreader.Reader) {
 			ret.inExperiment = reader.Bool()
 		case "bigSegmentsStatus":
 			ret.bigSegmentsStatus = BigSegmentsStatus(reader.String())
+		case "prerequisites":
+			ret.prerequisites = encodePrerequisites(readPrerequisites(reader))
+		case "clauseIndex":
+			ret.clauseIndex = ldvalue.NewOptionalInt(reader.Int())
+		case "contextKind":
+			ret.targetContextKind = reader.String()
 		}
 	}
 	if reader.Error() == nil {
@@ -286,5 +300,27 @@ func (r EvaluationReason) WriteToJSONWriter(w *jwriter.Writer) {
 	if r.bigSegmentsStatus != "" {
 		obj.Name("bigSegmentsStatus").String(string(r.bigSegmentsStatus))
 	}
+	if r.clauseIndex.IsDefined() {
+		obj.Name("clauseIndex").Int(r.clauseIndex.OrElse(0))
+	}
+	obj.Maybe("contextKind", r.targetContextKind != "").String(r.targetContextKind)
+	obj.Maybe("prerequisites", r.prerequisites != "").Raw(json.RawMessage(r.prerequisites))
 	obj.End()
 }
+
+func readPrerequisites(reader *jreader.Reader) []PrerequisiteEvaluation {
+	var ret []PrerequisiteEvaluation
+	for arr := reader.ArrayOrNull(); arr.Next(); {
+		var p PrerequisiteEvaluation
+		for obj := reader.Object(); obj.Next(); {
+			switch string(obj.Name()) {
+			case "key":
+				p.FlagKey = reader.String()
+			case "reason":
+				p.Reason.ReadFromJSONReader(reader)
+			}
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
@@ -0,0 +1,119 @@
+package ldreason
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// ReasonBuilder is a mutable object that uses the builder pattern to construct an [EvaluationReason]
+// with any combination of properties.
+//
+// The NewEvalReason constructors cover the usual cases; ReasonBuilder is for cases where several
+// optional properties need to be combined, such as a rule match that was part of an experiment and
+// also involved a Big Segment query:
+//
+//	reason := ldreason.NewReasonBuilder(ldreason.EvalReasonRuleMatch).
+//		RuleIndex(0).
+//		RuleID("rule-id").
+//		InExperiment(true).
+//		BigSegmentsStatus(ldreason.BigSegmentsHealthy).
+//		Build()
+//
+// ReasonBuilder does not check whether the properties make sense for the Kind; for instance, it is
+// possible to set an error kind on a reason whose Kind is not [EvalReasonError]. Properties that are
+// not meaningful for the Kind are still returned by the corresponding getters, but are not always
+// included in the JSON representation.
+//
+// A ReasonBuilder should not be accessed by multiple goroutines at once.
+type ReasonBuilder struct {
+	reason EvaluationReason
+}
+
+// NewReasonBuilder creates a ReasonBuilder for a reason with the specified Kind.
+func NewReasonBuilder(kind EvalReasonKind) *ReasonBuilder {
+	return &ReasonBuilder{reason: EvaluationReason{kind: kind}}
+}
+
+// NewReasonBuilderFromReason creates a ReasonBuilder whose properties are the same as an existing
+// EvaluationReason.
+func NewReasonBuilderFromReason(reason EvaluationReason) *ReasonBuilder {
+	return &ReasonBuilder{reason: reason}
+}
+
+// Build creates an EvaluationReason from the current properties of the ReasonBuilder.
+//
+// The ReasonBuilder can still be modified after this; doing so does not affect the returned
+// EvaluationReason.
+func (b *ReasonBuilder) Build() EvaluationReason {
+	return b.reason
+}
+
+// Kind sets the general category of the reason. See [EvaluationReason.GetKind].
+func (b *ReasonBuilder) Kind(kind EvalReasonKind) *ReasonBuilder {
+	b.reason.kind = kind
+	return b
+}
+
+// RuleIndex sets the index of the rule that was matched. A negative value means there is no rule
+// index. See [EvaluationReason.GetRuleIndex].
+func (b *ReasonBuilder) RuleIndex(ruleIndex int) *ReasonBuilder {
+	if ruleIndex < 0 {
+		b.reason.ruleIndex = ldvalue.OptionalInt{}
+	} else {
+		b.reason.ruleIndex = ldvalue.NewOptionalInt(ruleIndex)
+	}
+	return b
+}
+
+// RuleID sets the unique identifier of the rule that was matched. See [EvaluationReason.GetRuleID].
+func (b *ReasonBuilder) RuleID(ruleID string) *ReasonBuilder {
+	b.reason.ruleID = ruleID
+	return b
+}
+
+// PrerequisiteKey sets the flag key of the prerequisite that failed. See
+// [EvaluationReason.GetPrerequisiteKey].
+func (b *ReasonBuilder) PrerequisiteKey(prerequisiteKey string) *ReasonBuilder {
+	b.reason.prerequisiteKey = prerequisiteKey
+	return b
+}
+
+// InExperiment sets whether the evaluation was part of an experiment. See
+// [EvaluationReason.IsInExperiment].
+func (b *ReasonBuilder) InExperiment(inExperiment bool) *ReasonBuilder {
+	b.reason.inExperiment = inExperiment
+	return b
+}
+
+// ErrorKind sets the general category of the error. See [EvaluationReason.GetErrorKind].
+func (b *ReasonBuilder) ErrorKind(errorKind EvalErrorKind) *ReasonBuilder {
+	b.reason.errorKind = errorKind
+	return b
+}
+
+// BigSegmentsStatus sets the validity of Big Segment information. See
+// [EvaluationReason.GetBigSegmentsStatus].
+func (b *ReasonBuilder) BigSegmentsStatus(bigSegmentsStatus BigSegmentsStatus) *ReasonBuilder {
+	b.reason.bigSegmentsStatus = bigSegmentsStatus
+	return b
+}
+
+// Prerequisites sets the prerequisite flags that were evaluated. See
+// [EvaluationReason.GetPrerequisites].
+func (b *ReasonBuilder) Prerequisites(prerequisites []PrerequisiteEvaluation) *ReasonBuilder {
+	b.reason = NewEvalReasonFromReasonWithPrerequisites(b.reason, prerequisites)
+	return b
+}
+
+// ClauseIndex sets the index of the clause that was matched within the matched rule. A negative
+// value means there is no clause index. See [EvaluationReason.GetClauseIndex].
+func (b *ReasonBuilder) ClauseIndex(clauseIndex int) *ReasonBuilder {
+	b.reason = NewEvalReasonFromReasonWithClauseIndex(b.reason, clauseIndex)
+	return b
+}
+
+// TargetContextKind sets the context kind of the individual targets list that was matched. See
+// [EvaluationReason.GetTargetContextKind].
+func (b *ReasonBuilder) TargetContextKind(contextKind string) *ReasonBuilder {
+	b.reason = NewEvalReasonFromReasonWithTargetContextKind(b.reason, contextKind)
+	return b
+}
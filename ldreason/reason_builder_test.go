@@ -0,0 +1,60 @@
+package ldreason
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReasonBuilderMatchesConstructors(t *testing.T) {
+	assert.Equal(t, NewEvalReasonOff(), NewReasonBuilder(EvalReasonOff).Build())
+	assert.Equal(t, NewEvalReasonFallthroughExperiment(true),
+		NewReasonBuilder(EvalReasonFallthrough).InExperiment(true).Build())
+	assert.Equal(t, NewEvalReasonTargetMatch(), NewReasonBuilder(EvalReasonTargetMatch).Build())
+	assert.Equal(t, NewEvalReasonRuleMatchExperiment(1, "id", true),
+		NewReasonBuilder(EvalReasonRuleMatch).RuleIndex(1).RuleID("id").InExperiment(true).Build())
+	assert.Equal(t, NewEvalReasonPrerequisiteFailed("key"),
+		NewReasonBuilder(EvalReasonPrerequisiteFailed).PrerequisiteKey("key").Build())
+	assert.Equal(t, NewEvalReasonError(EvalErrorWrongType),
+		NewReasonBuilder(EvalReasonError).ErrorKind(EvalErrorWrongType).Build())
+}
+
+func TestReasonBuilderCombinations(t *testing.T) {
+	prereqs := []PrerequisiteEvaluation{{FlagKey: "a", Reason: NewEvalReasonOff()}}
+	r := NewReasonBuilder(EvalReasonRuleMatch).
+		RuleIndex(2).
+		RuleID("id").
+		ClauseIndex(1).
+		InExperiment(true).
+		BigSegmentsStatus(BigSegmentsStale).
+		Prerequisites(prereqs).
+		Build()
+	assert.Equal(t, EvalReasonRuleMatch, r.GetKind())
+	assert.Equal(t, 2, r.GetRuleIndex())
+	assert.Equal(t, "id", r.GetRuleID())
+	assert.Equal(t, 1, r.GetClauseIndex())
+	assert.True(t, r.IsInExperiment())
+	assert.Equal(t, BigSegmentsStale, r.GetBigSegmentsStatus())
+	assert.Equal(t, prereqs, r.GetPrerequisites())
+
+	r1 := NewReasonBuilder(EvalReasonTargetMatch).TargetContextKind("org").Build()
+	assert.Equal(t, "org", r1.GetTargetContextKind())
+}
+
+func TestReasonBuilderFromReason(t *testing.T) {
+	original := NewEvalReasonRuleMatch(1, "id")
+	b := NewReasonBuilderFromReason(original)
+	assert.Equal(t, original, b.Build())
+
+	modified := b.RuleIndex(-1).Kind(EvalReasonFallthrough).RuleID("").Build()
+	assert.Equal(t, NewEvalReasonFallthrough(), modified)
+	assert.Equal(t, NewEvalReasonRuleMatch(1, "id"), original)
+}
+
+func TestReasonBuilderIsIndependentOfBuiltReason(t *testing.T) {
+	b := NewReasonBuilder(EvalReasonRuleMatch).ClauseIndex(1)
+	r := b.Build()
+	b.ClauseIndex(2).InExperiment(true)
+	assert.Equal(t, 1, r.GetClauseIndex())
+	assert.False(t, r.IsInExperiment())
+}
@@ -0,0 +1,55 @@
+package ldreason
+
+// ReasonMatcher is an interface with one method for each kind of [EvaluationReason]. It is used with
+// [MatchReason] to branch on the kind of a reason.
+//
+// Because a type must implement every method in order to be used as a ReasonMatcher, the compiler
+// ensures that all kinds are handled, which is not the case with a switch statement on
+// [EvaluationReason.GetKind]. Each method receives the reason itself, so that it can access any
+// other properties.
+type ReasonMatcher[T any] interface {
+	// Off is called for a reason whose Kind is [EvalReasonOff].
+	Off(reason EvaluationReason) T
+	// Fallthrough is called for a reason whose Kind is [EvalReasonFallthrough].
+	Fallthrough(reason EvaluationReason) T
+	// TargetMatch is called for a reason whose Kind is [EvalReasonTargetMatch].
+	TargetMatch(reason EvaluationReason) T
+	// RuleMatch is called for a reason whose Kind is [EvalReasonRuleMatch].
+	RuleMatch(reason EvaluationReason) T
+	// PrerequisiteFailed is called for a reason whose Kind is [EvalReasonPrerequisiteFailed].
+	PrerequisiteFailed(reason EvaluationReason) T
+	// Error is called for a reason whose Kind is [EvalReasonError].
+	Error(reason EvaluationReason) T
+	// Other is called for an undefined EvaluationReason{}, or one whose Kind is not recognized
+	// (for instance, a kind added in a newer version of LaunchDarkly that was read from JSON).
+	Other(reason EvaluationReason) T
+}
+
+// MatchReason calls the method of the ReasonMatcher that corresponds to the reason's Kind, and
+// returns its result.
+//
+//	type describer struct{}
+//
+//	func (describer) Off(ldreason.EvaluationReason) string         { return "flag is off" }
+//	func (describer) Fallthrough(ldreason.EvaluationReason) string { return "default rule" }
+//	// ...and so on for each kind
+//
+//	description := ldreason.MatchReason[string](reason, describer{})
+func MatchReason[T any](reason EvaluationReason, matcher ReasonMatcher[T]) T {
+	switch reason.kind {
+	case EvalReasonOff:
+		return matcher.Off(reason)
+	case EvalReasonFallthrough:
+		return matcher.Fallthrough(reason)
+	case EvalReasonTargetMatch:
+		return matcher.TargetMatch(reason)
+	case EvalReasonRuleMatch:
+		return matcher.RuleMatch(reason)
+	case EvalReasonPrerequisiteFailed:
+		return matcher.PrerequisiteFailed(reason)
+	case EvalReasonError:
+		return matcher.Error(reason)
+	default:
+		return matcher.Other(reason)
+	}
+}
@@ -0,0 +1,40 @@
+package ldreason
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reasonDescriber struct{}
+
+func (reasonDescriber) Off(EvaluationReason) string         { return "off" }
+func (reasonDescriber) Fallthrough(EvaluationReason) string { return "fallthrough" }
+func (reasonDescriber) TargetMatch(EvaluationReason) string { return "target" }
+func (reasonDescriber) RuleMatch(r EvaluationReason) string { return "rule " + r.GetRuleID() }
+func (reasonDescriber) PrerequisiteFailed(r EvaluationReason) string {
+	return "prereq " + r.GetPrerequisiteKey()
+}
+func (reasonDescriber) Error(r EvaluationReason) string { return "error " + string(r.GetErrorKind()) }
+func (reasonDescriber) Other(r EvaluationReason) string { return "other " + string(r.GetKind()) }
+
+func TestMatchReason(t *testing.T) {
+	params := []struct {
+		reason   EvaluationReason
+		expected string
+	}{
+		{NewEvalReasonOff(), "off"},
+		{NewEvalReasonFallthrough(), "fallthrough"},
+		{NewEvalReasonTargetMatch(), "target"},
+		{NewEvalReasonRuleMatch(0, "id"), "rule id"},
+		{NewEvalReasonPrerequisiteFailed("key"), "prereq key"},
+		{NewEvalReasonError(EvalErrorWrongType), "error WRONG_TYPE"},
+		{EvaluationReason{}, "other "},
+		{NewReasonBuilder("NEW_KIND").Build(), "other NEW_KIND"},
+	}
+	for _, p := range params {
+		t.Run(p.expected, func(t *testing.T) {
+			assert.Equal(t, p.expected, MatchReason[string](p.reason, reasonDescriber{}))
+		})
+	}
+}
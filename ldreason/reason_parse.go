@@ -0,0 +1,59 @@
+package ldreason
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseEvaluationReason parses the string representation produced by [EvaluationReason.String],
+// such as "OFF", "RULE_MATCH(0,rule-id)", or "ERROR(WRONG_TYPE)", back into an EvaluationReason.
+// This is mainly useful for analyzing log output.
+//
+// The string representation only includes the Kind and the property that is specific to that Kind,
+// so other properties such as [EvaluationReason.IsInExperiment] cannot be recovered. An empty string
+// produces an undefined EvaluationReason{}, since that is the string representation of one.
+//
+// An error is returned if the string is not in the expected format or the kind is not recognized.
+func ParseEvaluationReason(s string) (EvaluationReason, error) {
+	if s == "" {
+		return EvaluationReason{}, nil
+	}
+	kindStr, params, hasParams := s, "", false
+	if open := strings.IndexByte(s, '('); open >= 0 {
+		if !strings.HasSuffix(s, ")") {
+			return EvaluationReason{}, fmt.Errorf("evaluation reason %q is missing a closing parenthesis", s)
+		}
+		kindStr, params, hasParams = s[:open], s[open+1:len(s)-1], true
+	}
+	kind := EvalReasonKind(kindStr)
+	switch kind {
+	case EvalReasonOff, EvalReasonFallthrough, EvalReasonTargetMatch:
+		if hasParams {
+			return EvaluationReason{}, fmt.Errorf("evaluation reason kind %s does not take parameters", kind)
+		}
+		return EvaluationReason{kind: kind}, nil
+	case EvalReasonRuleMatch:
+		if !hasParams {
+			return EvaluationReason{}, fmt.Errorf("evaluation reason kind %s requires a rule index", kind)
+		}
+		indexStr, ruleID, _ := strings.Cut(params, ",")
+		ruleIndex, err := strconv.Atoi(indexStr)
+		if err != nil || ruleIndex < 0 {
+			return EvaluationReason{}, fmt.Errorf("invalid rule index %q in evaluation reason", indexStr)
+		}
+		return NewEvalReasonRuleMatch(ruleIndex, ruleID), nil
+	case EvalReasonPrerequisiteFailed:
+		if !hasParams {
+			return EvaluationReason{}, fmt.Errorf("evaluation reason kind %s requires a prerequisite key", kind)
+		}
+		return NewEvalReasonPrerequisiteFailed(params), nil
+	case EvalReasonError:
+		if !hasParams {
+			return EvaluationReason{}, fmt.Errorf("evaluation reason kind %s requires an error kind", kind)
+		}
+		return NewEvalReasonError(EvalErrorKind(params)), nil
+	default:
+		return EvaluationReason{}, fmt.Errorf("unrecognized evaluation reason kind %q", kindStr)
+	}
+}
@@ -0,0 +1,48 @@
+package ldreason
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvaluationReason(t *testing.T) {
+	for _, r := range []EvaluationReason{
+		{},
+		NewEvalReasonOff(),
+		NewEvalReasonFallthrough(),
+		NewEvalReasonTargetMatch(),
+		NewEvalReasonRuleMatch(0, "id"),
+		NewEvalReasonRuleMatch(12, ""),
+		NewEvalReasonRuleMatch(1, "id,with,commas(and parens)"),
+		NewEvalReasonPrerequisiteFailed("key"),
+		NewEvalReasonPrerequisiteFailed(""),
+		NewEvalReasonError(EvalErrorWrongType),
+	} {
+		t.Run(r.String(), func(t *testing.T) {
+			parsed, err := ParseEvaluationReason(r.String())
+			require.NoError(t, err)
+			assert.Equal(t, r, parsed)
+		})
+	}
+}
+
+func TestParseEvaluationReasonErrors(t *testing.T) {
+	for _, s := range []string{
+		"UNKNOWN",
+		"off",
+		"OFF(x)",
+		"RULE_MATCH",
+		"RULE_MATCH(x,id)",
+		"RULE_MATCH(-1,id)",
+		"RULE_MATCH(0,id",
+		"PREREQUISITE_FAILED",
+		"ERROR",
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParseEvaluationReason(s)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package ldreason
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
+)
+
+// PrerequisiteEvaluation describes the evaluation of one prerequisite flag, as part of the optional
+// evaluation trace in an [EvaluationReason]. See [EvaluationReason.GetPrerequisites].
+type PrerequisiteEvaluation struct {
+	// FlagKey is the key of the prerequisite flag.
+	FlagKey string
+	// Reason is the result of evaluating the prerequisite flag. This can itself contain prerequisite
+	// information, if the prerequisite flag had prerequisites of its own.
+	Reason EvaluationReason
+}
+
+// encodePrerequisites converts a list of prerequisites to the JSON representation that is stored
+// in EvaluationReason. Storing them as a string, rather than as a slice, keeps EvaluationReason
+// immutable and comparable with the == operator; since the encoding is always produced by this
+// function, two equivalent lists always have the same encoding.
+func encodePrerequisites(prerequisites []PrerequisiteEvaluation) string {
+	if len(prerequisites) == 0 {
+		return ""
+	}
+	w := jwriter.NewWriter()
+	arr := w.Array()
+	for _, p := range prerequisites {
+		obj := arr.Object()
+		obj.Name("key").String(p.FlagKey)
+		p.Reason.WriteToJSONWriter(obj.Name("reason"))
+		obj.End()
+	}
+	arr.End()
+	return string(w.Bytes())
+}
+
+// Equal tests whether this EvaluationReason is equal to another, including any trace information.
+// This is the same as comparing them with the == operator.
+func (r EvaluationReason) Equal(other EvaluationReason) bool {
+	return r == other
+}
+
+// GetPrerequisites returns the prerequisite flags that were evaluated before this flag, in the order
+// they were evaluated, if that information was included in the reason. Otherwise it returns nil.
+//
+// Each element has its own reason, so the complete chain of prerequisites can be explored
+// recursively. If the Kind is [EvalReasonPrerequisiteFailed], the last element is normally the
+// prerequisite whose key is returned by [EvaluationReason.GetPrerequisiteKey].
+//
+// The returned slice is a copy; modifying it does not affect the EvaluationReason.
+func (r EvaluationReason) GetPrerequisites() []PrerequisiteEvaluation {
+	if r.prerequisites == "" {
+		return nil
+	}
+	reader := jreader.NewReader([]byte(r.prerequisites))
+	return readPrerequisites(&reader)
+}
+
+// GetClauseIndex provides the index of the clause that was matched within the matched rule (0 being
+// the first), if the Kind is [EvalReasonRuleMatch] and that information was included in the reason.
+// Otherwise it returns -1.
+func (r EvaluationReason) GetClauseIndex() int {
+	return r.clauseIndex.OrElse(-1)
+}
+
+// GetTargetContextKind provides the context kind of the individual targets list that was matched, if
+// the Kind is [EvalReasonTargetMatch] and that information was included in the reason. Otherwise it
+// returns an empty string.
+func (r EvaluationReason) GetTargetContextKind() string {
+	return r.targetContextKind
+}
+
+// NewEvalReasonFromReasonWithPrerequisites returns a copy of an EvaluationReason with information
+// about the prerequisite flags that were evaluated. Any prerequisite information that was already
+// present is replaced.
+//
+// The slice is copied, so modifying it afterward does not affect the EvaluationReason.
+func NewEvalReasonFromReasonWithPrerequisites(
+	reason EvaluationReason,
+	prerequisites []PrerequisiteEvaluation,
+) EvaluationReason {
+	reason.prerequisites = encodePrerequisites(prerequisites)
+	return reason
+}
+
+// NewEvalReasonFromReasonWithClauseIndex returns a copy of an EvaluationReason with the index of the
+// clause that was matched within the matched rule. This is only meaningful if the Kind is
+// [EvalReasonRuleMatch]; a negative index removes any clause index that was already present.
+func NewEvalReasonFromReasonWithClauseIndex(reason EvaluationReason, clauseIndex int) EvaluationReason {
+	if clauseIndex < 0 {
+		reason.clauseIndex = ldvalue.OptionalInt{}
+	} else {
+		reason.clauseIndex = ldvalue.NewOptionalInt(clauseIndex)
+	}
+	return reason
+}
+
+// NewEvalReasonFromReasonWithTargetContextKind returns a copy of an EvaluationReason with the context
+// kind of the individual targets list that was matched. This is only meaningful if the Kind is
+// [EvalReasonTargetMatch].
+func NewEvalReasonFromReasonWithTargetContextKind(
+	reason EvaluationReason,
+	contextKind string,
+) EvaluationReason {
+	reason.targetContextKind = contextKind
+	return reason
+}
@@ -0,0 +1,157 @@
+package ldreason
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReasonTraceDefaults(t *testing.T) {
+	for _, r := range []EvaluationReason{
+		NewEvalReasonOff(), NewEvalReasonFallthrough(), NewEvalReasonTargetMatch(),
+		NewEvalReasonRuleMatch(0, "id"), NewEvalReasonPrerequisiteFailed("key"),
+		NewEvalReasonError(EvalErrorFlagNotFound),
+	} {
+		t.Run(string(r.GetKind()), func(t *testing.T) {
+			assert.Nil(t, r.GetPrerequisites())
+			assert.Equal(t, -1, r.GetClauseIndex())
+			assert.Equal(t, "", r.GetTargetContextKind())
+		})
+	}
+}
+
+func TestReasonPrerequisites(t *testing.T) {
+	inner := NewEvalReasonFromReasonWithPrerequisites(NewEvalReasonFallthrough(),
+		[]PrerequisiteEvaluation{{FlagKey: "c", Reason: NewEvalReasonOff()}})
+	prereqs := []PrerequisiteEvaluation{
+		{FlagKey: "a", Reason: NewEvalReasonTargetMatch()},
+		{FlagKey: "b", Reason: inner},
+	}
+	r := NewEvalReasonFromReasonWithPrerequisites(NewEvalReasonPrerequisiteFailed("b"), prereqs)
+
+	prereqs[0].FlagKey = "modified"
+	assert.Equal(t, []PrerequisiteEvaluation{
+		{FlagKey: "a", Reason: NewEvalReasonTargetMatch()},
+		{FlagKey: "b", Reason: inner},
+	}, r.GetPrerequisites())
+	assert.Equal(t, "b", r.GetPrerequisiteKey())
+
+	r.GetPrerequisites()[0].FlagKey = "modified"
+	assert.Equal(t, "a", r.GetPrerequisites()[0].FlagKey)
+
+	assert.Equal(t, NewEvalReasonOff(), NewEvalReasonFromReasonWithPrerequisites(NewEvalReasonOff(), nil))
+}
+
+func TestReasonClauseIndex(t *testing.T) {
+	r := NewEvalReasonFromReasonWithClauseIndex(NewEvalReasonRuleMatch(1, "id"), 2)
+	assert.Equal(t, 1, r.GetRuleIndex())
+	assert.Equal(t, 2, r.GetClauseIndex())
+
+	r1 := NewEvalReasonFromReasonWithClauseIndex(r, 3)
+	assert.Equal(t, 2, r.GetClauseIndex())
+	assert.Equal(t, 3, r1.GetClauseIndex())
+
+	assert.Equal(t, NewEvalReasonRuleMatch(1, "id"), NewEvalReasonFromReasonWithClauseIndex(r, -1))
+}
+
+func TestReasonTargetContextKind(t *testing.T) {
+	r := NewEvalReasonFromReasonWithTargetContextKind(NewEvalReasonTargetMatch(), "org")
+	assert.Equal(t, EvalReasonTargetMatch, r.GetKind())
+	assert.Equal(t, "org", r.GetTargetContextKind())
+	assert.Equal(t, "TARGET_MATCH", r.String())
+}
+
+func TestReasonTraceSerialization(t *testing.T) {
+	nested := NewEvalReasonFromReasonWithPrerequisites(
+		NewEvalReasonFromReasonWithClauseIndex(NewEvalReasonRuleMatch(0, "r0"), 1),
+		[]PrerequisiteEvaluation{{FlagKey: "c", Reason: NewEvalReasonFallthrough()}},
+	)
+	params := []serializationTestParams{
+		{
+			NewEvalReasonFromReasonWithClauseIndex(NewEvalReasonRuleMatch(1, "x"), 2),
+			"RULE_MATCH(1,x)",
+			`{"kind":"RULE_MATCH","ruleIndex":1,"ruleId":"x","clauseIndex":2}`,
+		},
+		{
+			NewEvalReasonFromReasonWithTargetContextKind(NewEvalReasonTargetMatch(), "org"),
+			"TARGET_MATCH",
+			`{"kind":"TARGET_MATCH","contextKind":"org"}`,
+		},
+		{
+			NewEvalReasonFromReasonWithPrerequisites(NewEvalReasonPrerequisiteFailed("b"), []PrerequisiteEvaluation{
+				{FlagKey: "a", Reason: NewEvalReasonTargetMatch()},
+				{FlagKey: "b", Reason: nested},
+			}),
+			"PREREQUISITE_FAILED(b)",
+			`{"kind":"PREREQUISITE_FAILED","prerequisiteKey":"b","prerequisites":[` +
+				`{"key":"a","reason":{"kind":"TARGET_MATCH"}},` +
+				`{"key":"b","reason":{"kind":"RULE_MATCH","ruleIndex":0,"ruleId":"r0","clauseIndex":1,` +
+				`"prerequisites":[{"key":"c","reason":{"kind":"FALLTHROUGH"}}]}}]}`,
+		},
+	}
+	for _, param := range params {
+		t.Run(param.expectedJSON, func(t *testing.T) {
+			actual, err := json.Marshal(param.reason)
+			require.NoError(t, err)
+			assert.JSONEq(t, param.expectedJSON, string(actual))
+			assert.Equal(t, param.stringRep, param.reason.String())
+
+			var r1 EvaluationReason
+			require.NoError(t, json.Unmarshal(actual, &r1))
+			assert.True(t, param.reason == r1, "expected %s, got %s", param.reason, r1)
+		})
+	}
+
+	t.Run("consumers that only know the basic properties ignore trace properties", func(t *testing.T) {
+		data := []byte(`{"kind":"RULE_MATCH","ruleIndex":1,"ruleId":"x","clauseIndex":2,` +
+			`"prerequisites":[{"key":"a","reason":{"kind":"OFF"}}]}`)
+		var basic struct {
+			Kind      string `json:"kind"`
+			RuleIndex int    `json:"ruleIndex"`
+			RuleID    string `json:"ruleId"`
+		}
+		require.NoError(t, json.Unmarshal(data, &basic))
+		assert.Equal(t, "RULE_MATCH", basic.Kind)
+		assert.Equal(t, 1, basic.RuleIndex)
+		assert.Equal(t, "x", basic.RuleID)
+	})
+
+	t.Run("malformed prerequisites", func(t *testing.T) {
+		reader := jreader.NewReader([]byte(`{"kind":"OFF","prerequisites":[{"key":1}]}`))
+		var r EvaluationReason
+		r.ReadFromJSONReader(&reader)
+		assert.Error(t, reader.Error())
+	})
+}
+
+func TestReasonEqual(t *testing.T) {
+	makeTraced := func(prereqKey string, clauseIndex int) EvaluationReason {
+		return NewEvalReasonFromReasonWithPrerequisites(
+			NewEvalReasonFromReasonWithClauseIndex(NewEvalReasonRuleMatch(0, "r0"), clauseIndex),
+			[]PrerequisiteEvaluation{
+				{FlagKey: prereqKey, Reason: NewEvalReasonFromReasonWithTargetContextKind(NewEvalReasonTargetMatch(), "org")},
+			},
+		)
+	}
+
+	assert.True(t, NewEvalReasonOff().Equal(NewEvalReasonOff()))
+	assert.False(t, NewEvalReasonOff().Equal(NewEvalReasonFallthrough()))
+	assert.False(t, NewEvalReasonRuleMatch(0, "a").Equal(NewEvalReasonRuleMatch(0, "b")))
+
+	r1, r2 := makeTraced("a", 1), makeTraced("a", 1)
+	assert.True(t, r1 == r2)
+	assert.True(t, r1.Equal(r2))
+	assert.True(t, r1.Equal(r1))
+
+	assert.False(t, r1 == makeTraced("b", 1))
+	assert.False(t, r1.Equal(makeTraced("b", 1)))
+	assert.False(t, r1.Equal(makeTraced("a", 2)))
+	assert.False(t, r1.Equal(NewEvalReasonRuleMatch(0, "r0")))
+	assert.False(t, NewEvalReasonRuleMatch(0, "r0").Equal(r1))
+	assert.False(t, NewEvalReasonFromReasonWithTargetContextKind(NewEvalReasonTargetMatch(), "org").Equal(
+		NewEvalReasonFromReasonWithTargetContextKind(NewEvalReasonTargetMatch(), "user")))
+}
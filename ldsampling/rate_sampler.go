@@ -0,0 +1,62 @@
+package ldsampling
+
+import (
+	"time"
+)
+
+// NewRateSampler creates a *RateSampler that selects at most limit items in each interval.
+//
+// Intervals are measured using the current system time.
+func NewRateSampler(limit int, interval time.Duration) *RateSampler {
+	return NewRateSamplerWithClock(limit, interval, time.Now)
+}
+
+// NewRateSamplerWithClock creates a *RateSampler similar to NewRateSampler with the additional
+// benefit of providing the function that reports the current time. This is mainly useful for
+// testing.
+func NewRateSamplerWithClock(limit int, interval time.Duration, now func() time.Time) *RateSampler {
+	return &RateSampler{limit: limit, interval: interval, now: now}
+}
+
+// RateSampler is a [Sampler] that selects at most a fixed number of items per time interval.
+//
+// Time is divided into consecutive fixed-length windows, starting with the first call to Sample.
+// Within each window, the first limit calls to Sample return true and any further calls return
+// false until the next window begins.
+//
+// A non-positive limit effectively disables sampling, resulting in Sample always returning false.
+// A non-positive interval means that there is only a single window, so at most limit items will
+// ever be selected.
+//
+// A RateSampler is not safe for concurrent use.
+type RateSampler struct {
+	limit       int
+	interval    time.Duration
+	now         func() time.Time
+	windowStart time.Time
+	count       int
+	started     bool
+}
+
+// Sample returns true if fewer than the configured limit of items have been selected in the
+// current interval. It should not be called concurrently.
+func (r *RateSampler) Sample() bool {
+	if r.limit <= 0 {
+		return false
+	}
+	now := r.now()
+	if !r.started {
+		r.started = true
+		r.windowStart = now
+	} else if r.interval > 0 {
+		if elapsed := now.Sub(r.windowStart); elapsed >= r.interval {
+			r.windowStart = r.windowStart.Add(elapsed - elapsed%r.interval)
+			r.count = 0
+		}
+	}
+	if r.count >= r.limit {
+		return false
+	}
+	r.count++
+	return true
+}
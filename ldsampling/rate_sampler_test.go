@@ -0,0 +1,66 @@
+package ldsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestRateSampler(t *testing.T) {
+	var _ Sampler = NewRateSampler(1, time.Second)
+
+	t.Run("non-positive limit", func(t *testing.T) {
+		assert.False(t, NewRateSampler(0, time.Second).Sample())
+		assert.False(t, NewRateSampler(-1, time.Second).Sample())
+	})
+
+	t.Run("limit per interval", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		sampler := NewRateSamplerWithClock(2, time.Second, clock.Now)
+
+		assert.True(t, sampler.Sample())
+		clock.Advance(100 * time.Millisecond)
+		assert.True(t, sampler.Sample())
+		assert.False(t, sampler.Sample())
+
+		clock.Advance(900 * time.Millisecond)
+		assert.True(t, sampler.Sample())
+		assert.True(t, sampler.Sample())
+		assert.False(t, sampler.Sample())
+	})
+
+	t.Run("windows stay aligned after idle periods", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		sampler := NewRateSamplerWithClock(1, time.Second, clock.Now)
+
+		assert.True(t, sampler.Sample())
+		clock.Advance(3500 * time.Millisecond)
+		assert.True(t, sampler.Sample())
+		clock.Advance(400 * time.Millisecond)
+		assert.False(t, sampler.Sample())
+		clock.Advance(100 * time.Millisecond)
+		assert.True(t, sampler.Sample())
+	})
+
+	t.Run("non-positive interval", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		sampler := NewRateSamplerWithClock(1, 0, clock.Now)
+
+		assert.True(t, sampler.Sample())
+		clock.Advance(time.Hour)
+		assert.False(t, sampler.Sample())
+	})
+}
@@ -0,0 +1,137 @@
+package ldsampling
+
+import (
+	"math/rand"
+	"time"
+)
+
+// NewReservoirSampler creates a *ReservoirSampler that maintains a uniform sample of at most size
+// items from a stream of unknown length.
+//
+// The random number generator used is seeded with the current system time.
+func NewReservoirSampler(size int) *ReservoirSampler {
+	return NewReservoirSamplerFromSource(size, rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewReservoirSamplerFromSource creates a *ReservoirSampler similar to NewReservoirSampler with the
+// additional benefit of providing the random number source.
+func NewReservoirSamplerFromSource(size int, source rand.Source) *ReservoirSampler {
+	return &ReservoirSampler{size: size, rng: rand.New(source)} //nolint:gosec // doesn't need cryptographic security
+}
+
+// ReservoirSampler makes the decisions for reservoir sampling: keeping a uniformly random sample of
+// a fixed number of items from a stream whose length is not known in advance.
+//
+// The sampler does not store items itself; it only reports, for each item offered, whether that item
+// belongs in the sample and which slot of the caller's reservoir it should occupy. For a ready-made
+// container that stores the items, see [Reservoir].
+//
+// A non-positive size effectively disables sampling, resulting in Sample always returning false.
+//
+// As a ReservoirSampler relies on rand.Source, the sampler is not safe for concurrent use.
+type ReservoirSampler struct {
+	size int
+	seen int
+	rng  *rand.Rand
+}
+
+// Next offers one more item to the sampler. If the item should be kept, it returns the index of the
+// reservoir slot it should be stored in and true; the slot index is always less than the reservoir
+// size, and any item previously in that slot is evicted. If the item should be discarded, it returns
+// (-1, false). It should not be called concurrently.
+func (r *ReservoirSampler) Next() (int, bool) {
+	if r.size <= 0 {
+		return -1, false
+	}
+	r.seen++
+	if r.seen <= r.size {
+		return r.seen - 1, true
+	}
+	if slot := r.rng.Intn(r.seen); slot < r.size {
+		return slot, true
+	}
+	return -1, false
+}
+
+// Sample offers one more item to the sampler and returns true if it should be kept. This is the same
+// as calling Next and ignoring the slot index, so it is only useful when the caller does not need to
+// know which previous item was evicted. It should not be called concurrently.
+func (r *ReservoirSampler) Sample() bool {
+	_, keep := r.Next()
+	return keep
+}
+
+// Seen returns the number of items that have been offered to the sampler since it was created or
+// last reset.
+func (r *ReservoirSampler) Seen() int {
+	return r.seen
+}
+
+// Reset starts a new sample, forgetting all items that have been offered so far.
+func (r *ReservoirSampler) Reset() {
+	r.seen = 0
+}
+
+// NewReservoir creates a *Reservoir that holds a uniform sample of at most size items.
+//
+// The random number generator used is seeded with the current system time.
+func NewReservoir[T any](size int) *Reservoir[T] {
+	return NewReservoirFromSource[T](size, rand.NewSource(time.Now().UnixNano()))
+}
+
+// NewReservoirFromSource creates a *Reservoir similar to NewReservoir with the additional benefit of
+// providing the random number source.
+func NewReservoirFromSource[T any](size int, source rand.Source) *Reservoir[T] {
+	return &Reservoir[T]{sampler: NewReservoirSamplerFromSource(size, source)}
+}
+
+// Reservoir stores a uniformly random sample of items from a stream, using a [ReservoirSampler] to
+// decide which items to keep.
+//
+// A Reservoir is not safe for concurrent use.
+type Reservoir[T any] struct {
+	sampler *ReservoirSampler
+	items   []T
+}
+
+// Add offers an item to the reservoir, and returns true if it was kept. Keeping an item may evict one
+// that was added earlier.
+func (r *Reservoir[T]) Add(item T) bool {
+	slot, keep := r.sampler.Next()
+	if !keep {
+		return false
+	}
+	if slot == len(r.items) {
+		r.items = append(r.items, item)
+	} else {
+		r.items[slot] = item
+	}
+	return true
+}
+
+// Items returns a copy of the items currently in the sample. The order of the items is unspecified.
+func (r *Reservoir[T]) Items() []T {
+	if len(r.items) == 0 {
+		return nil
+	}
+	ret := make([]T, len(r.items))
+	copy(ret, r.items)
+	return ret
+}
+
+// Len returns the number of items currently in the sample.
+func (r *Reservoir[T]) Len() int {
+	return len(r.items)
+}
+
+// Seen returns the total number of items that have been offered to the reservoir since it was
+// created or last reset.
+func (r *Reservoir[T]) Seen() int {
+	return r.sampler.Seen()
+}
+
+// Reset empties the reservoir and starts a new sample.
+func (r *Reservoir[T]) Reset() {
+	r.sampler.Reset()
+	r.items = nil
+}
@@ -0,0 +1,107 @@
+package ldsampling
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservoirSampler(t *testing.T) {
+	var _ Sampler = NewReservoirSampler(1)
+
+	t.Run("non-positive size", func(t *testing.T) {
+		assert.False(t, NewReservoirSampler(0).Sample())
+		assert.False(t, NewReservoirSampler(-1).Sample())
+	})
+
+	t.Run("first items fill the reservoir in order", func(t *testing.T) {
+		sampler := NewReservoirSamplerFromSource(3, rand.NewSource(1))
+		for i := 0; i < 3; i++ {
+			slot, keep := sampler.Next()
+			assert.True(t, keep)
+			assert.Equal(t, i, slot)
+		}
+		assert.Equal(t, 3, sampler.Seen())
+	})
+
+	t.Run("later items replace existing slots", func(t *testing.T) {
+		sampler := NewReservoirSamplerFromSource(3, rand.NewSource(1))
+		kept := 0
+		for i := 0; i < 1_000; i++ {
+			slot, keep := sampler.Next()
+			if keep {
+				kept++
+				assert.GreaterOrEqual(t, slot, 0)
+				assert.Less(t, slot, 3)
+			} else {
+				assert.Equal(t, -1, slot)
+			}
+		}
+		assert.Less(t, kept, 100)
+		assert.Equal(t, 1_000, sampler.Seen())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		sampler := NewReservoirSamplerFromSource(1, rand.NewSource(1))
+		sampler.Sample()
+		sampler.Sample()
+		sampler.Reset()
+		assert.Equal(t, 0, sampler.Seen())
+		slot, keep := sampler.Next()
+		assert.True(t, keep)
+		assert.Equal(t, 0, slot)
+	})
+}
+
+func TestReservoir(t *testing.T) {
+	t.Run("holds all items when stream is smaller than size", func(t *testing.T) {
+		r := NewReservoir[string](5)
+		assert.Nil(t, r.Items())
+		r.Add("a")
+		r.Add("b")
+		assert.Equal(t, []string{"a", "b"}, r.Items())
+		assert.Equal(t, 2, r.Len())
+		assert.Equal(t, 2, r.Seen())
+	})
+
+	t.Run("holds at most size items", func(t *testing.T) {
+		r := NewReservoirFromSource[int](10, rand.NewSource(1))
+		for i := 0; i < 10_000; i++ {
+			r.Add(i)
+		}
+		assert.Equal(t, 10, r.Len())
+		assert.Equal(t, 10_000, r.Seen())
+		distinct := make(map[int]bool)
+		for _, item := range r.Items() {
+			distinct[item] = true
+		}
+		assert.Len(t, distinct, 10)
+	})
+
+	t.Run("distribution is uniform", func(t *testing.T) {
+		// With a fixed seed, each of 10 items should be chosen for a sample of size 1 roughly 10% of
+		// the time. Since we control the seed, this should be safe for testing.
+		counts := make([]int, 10)
+		source := rand.NewSource(1)
+		for trial := 0; trial < 10_000; trial++ {
+			r := NewReservoirFromSource[int](1, source)
+			for i := 0; i < 10; i++ {
+				r.Add(i)
+			}
+			counts[r.Items()[0]]++
+		}
+		for _, count := range counts {
+			assert.InDelta(t, 1_000, count, 100)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		r := NewReservoir[string](2)
+		r.Add("a")
+		r.Reset()
+		assert.Equal(t, 0, r.Len())
+		assert.Equal(t, 0, r.Seen())
+		assert.Nil(t, r.Items())
+	})
+}
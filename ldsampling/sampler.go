@@ -5,6 +5,16 @@ import (
 	"time"
 )
 
+// Sampler is the common interface for making a sampling decision about a single item.
+//
+// Each call to Sample represents one item being offered to the sampler; the return value indicates
+// whether that item was selected. Implementations in this package are not safe for concurrent use
+// unless otherwise noted.
+type Sampler interface {
+	// Sample returns true if the item currently being considered should be sampled.
+	Sample() bool
+}
+
 // NewSampler creates a *RatioSampler instance that can be used to
 // determine sampling selections.
 //
@@ -48,3 +58,19 @@ func (r *RatioSampler) Sample(ratio int) bool {
 
 	return r.rng.Float64() < 1/float64(ratio)
 }
+
+// WithRatio returns a [Sampler] that makes decisions with a fixed ratio, using the same random
+// number generator as this RatioSampler. Calling Sample on the returned value is equivalent to
+// calling r.Sample(ratio).
+func (r *RatioSampler) WithRatio(ratio int) Sampler {
+	return fixedRatioSampler{sampler: r, ratio: ratio}
+}
+
+type fixedRatioSampler struct {
+	sampler *RatioSampler
+	ratio   int
+}
+
+func (f fixedRatioSampler) Sample() bool {
+	return f.sampler.Sample(f.ratio)
+}
@@ -32,3 +32,23 @@ func TestRatioSampler(t *testing.T) {
 		assert.Equal(t, 508, picks)
 	})
 }
+
+func TestRatioSamplerWithRatio(t *testing.T) {
+	var _ Sampler = NewSampler().WithRatio(1)
+
+	t.Run("fixed ratios", func(t *testing.T) {
+		sampler := NewSampler()
+
+		assert.False(t, sampler.WithRatio(0).Sample())
+		assert.True(t, sampler.WithRatio(1).Sample())
+	})
+
+	t.Run("same decisions as underlying sampler", func(t *testing.T) {
+		s1 := NewSamplerFromSource(rand.NewSource(1))
+		s2 := NewSamplerFromSource(rand.NewSource(1)).WithRatio(3)
+
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, s1.Sample(3), s2.Sample())
+		}
+	})
+}
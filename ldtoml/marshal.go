@@ -0,0 +1,284 @@
+package ldtoml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// Marshal converts a Value to a TOML document.
+//
+// The Value must be a JSON object. Its properties are sorted by key; properties whose values are
+// objects are written as [table] sections, properties whose values are non-empty arrays of objects
+// are written as [[array]] sections, and all other properties are written as "key = value" lines.
+// Converting the output back with [Parse] produces a Value that is equal to the original one. A
+// number with an integral value is written as a TOML integer, even if it was parsed from a float.
+//
+// If the Value cannot be represented in TOML, because it is not an object or because it contains a
+// null value or an integer greater than [math.MaxInt64], the error is of type
+// [lderrors.ErrTOMLUnrepresentable]. If it contains an unparsed JSON value created with [ldvalue.Raw]
+// that is not valid JSON, the error is [lderrors.ErrValueRawJSONInvalid].
+func Marshal(v ldvalue.Value) ([]byte, error) {
+	v, err := parseIfRaw(v)
+	if err != nil {
+		return nil, err
+	}
+	if v.Type() != ldvalue.ObjectType {
+		return nil, lderrors.ErrTOMLUnrepresentable{
+			Message: fmt.Sprintf("a TOML document must be a table, so the value must be a JSON object, not %s", v.Type()),
+		}
+	}
+	var w writer
+	if err := w.writeTable(nil, "", v, false); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// MarshalContext converts a Context to a TOML document, by converting its JSON representation (see
+// [ldcontext.Context.MarshalJSON]) with [Marshal].
+func MarshalContext(c ldcontext.Context) ([]byte, error) {
+	data, err := c.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(ldvalue.ParseLossless(data))
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// writeTable writes the properties of an object. The keys are the components of the table's name,
+// which are used in section headers, and path is the table's location in the format used for error
+// messages.
+func (w *writer) writeTable(keys []string, path string, obj ldvalue.Value, arrayElement bool) error {
+	properties := obj.AsValueMap()
+	sortedKeys := properties.SortedKeys(nil)
+	values := make([]ldvalue.Value, len(sortedKeys))
+	hasSimpleValues := false
+	for i, key := range sortedKeys {
+		v, err := parseIfRaw(properties.Get(key))
+		if err != nil {
+			return err
+		}
+		values[i] = v
+		if v.Type() != ldvalue.ObjectType && !isArrayOfTables(v) {
+			hasSimpleValues = true
+		}
+	}
+
+	// A table that only contains other tables doesn't need a header of its own.
+	if len(keys) != 0 && (arrayElement || hasSimpleValues || len(sortedKeys) == 0) {
+		if w.buf.Len() != 0 {
+			w.buf.WriteByte('\n')
+		}
+		header := formatKeyPath(keys)
+		if arrayElement {
+			header = "[" + header + "]"
+		}
+		w.buf.WriteString("[" + header + "]\n")
+	}
+	for i, key := range sortedKeys {
+		if values[i].Type() == ldvalue.ObjectType || isArrayOfTables(values[i]) {
+			continue
+		}
+		w.buf.WriteString(formatKey(key) + " = ")
+		if err := w.writeInlineValue(childPath(path, key), values[i]); err != nil {
+			return err
+		}
+		w.buf.WriteByte('\n')
+	}
+	for i, key := range sortedKeys {
+		subKeys := append(keys[:len(keys):len(keys)], key)
+		switch {
+		case values[i].Type() == ldvalue.ObjectType:
+			if err := w.writeTable(subKeys, childPath(path, key), values[i], false); err != nil {
+				return err
+			}
+		case isArrayOfTables(values[i]):
+			for j := 0; j < values[i].Count(); j++ {
+				element, _ := parseIfRaw(values[i].GetByIndex(j)) // isArrayOfTables has already checked this
+				if err := w.writeTable(subKeys, indexPath(childPath(path, key), j), element, true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (w *writer) writeInlineValue(path string, v ldvalue.Value) error {
+	v, err := parseIfRaw(v)
+	if err != nil {
+		return err
+	}
+	switch v.Type() {
+	case ldvalue.NullType:
+		return lderrors.ErrTOMLUnrepresentable{Path: path, Message: "TOML does not have a null value"}
+	case ldvalue.BoolType:
+		w.buf.WriteString(strconv.FormatBool(v.BoolValue()))
+	case ldvalue.NumberType:
+		return w.writeNumber(path, v)
+	case ldvalue.StringType:
+		writeString(&w.buf, v.StringValue())
+	case ldvalue.ArrayType:
+		w.buf.WriteByte('[')
+		for i := 0; i < v.Count(); i++ {
+			if i > 0 {
+				w.buf.WriteString(", ")
+			}
+			if err := w.writeInlineValue(indexPath(path, i), v.GetByIndex(i)); err != nil {
+				return err
+			}
+		}
+		w.buf.WriteByte(']')
+	case ldvalue.ObjectType:
+		properties := v.AsValueMap()
+		if properties.Count() == 0 {
+			w.buf.WriteString("{}")
+			return nil
+		}
+		w.buf.WriteString("{ ")
+		for i, key := range properties.SortedKeys(nil) {
+			if i > 0 {
+				w.buf.WriteString(", ")
+			}
+			w.buf.WriteString(formatKey(key) + " = ")
+			if err := w.writeInlineValue(childPath(path, key), properties.Get(key)); err != nil {
+				return err
+			}
+		}
+		w.buf.WriteString(" }")
+	}
+	return nil
+}
+
+func (w *writer) writeNumber(path string, v ldvalue.Value) error {
+	f := v.Float64Value()
+	switch {
+	case math.IsNaN(f):
+		w.buf.WriteString("nan")
+		return nil
+	case math.IsInf(f, 1):
+		w.buf.WriteString("inf")
+		return nil
+	case math.IsInf(f, -1):
+		w.buf.WriteString("-inf")
+		return nil
+	}
+	if v.IsInt() {
+		if _, ok := v.Int64Value(); ok {
+			w.buf.WriteString(string(v.JSONNumber()))
+			return nil
+		}
+		if _, ok := v.Uint64Value(); ok {
+			return lderrors.ErrTOMLUnrepresentable{Path: path,
+				Message: fmt.Sprintf("%s is outside the range of TOML integers", v.JSONNumber())}
+		}
+	}
+	// A TOML float needs a decimal point or an exponent; the JSON representation of a number has one
+	// unless the number is integral.
+	text := string(v.JSONNumber())
+	if !strings.ContainsAny(text, ".eE") {
+		text = strconv.FormatFloat(f, 'g', -1, 64) // always uses an exponent for integral values this large
+	}
+	w.buf.WriteString(text)
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// formatKey returns a TOML key, which is quoted unless it consists only of the characters that are
+// allowed in a bare key.
+func formatKey(key string) string {
+	for i := 0; i < len(key); i++ {
+		if !isBareKeyChar(key[i]) {
+			var buf bytes.Buffer
+			writeString(&buf, key)
+			return buf.String()
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+func formatKeyPath(keys []string) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, formatKey(key))
+	}
+	return strings.Join(parts, ".")
+}
+
+func childPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// isArrayOfTables returns true if the value is a non-empty array whose elements are all objects, so
+// that it can be written as a series of [[array]] sections.
+func isArrayOfTables(v ldvalue.Value) bool {
+	if v.Type() != ldvalue.ArrayType || v.Count() == 0 {
+		return false
+	}
+	for i := 0; i < v.Count(); i++ {
+		element, err := parseIfRaw(v.GetByIndex(i))
+		if err != nil || element.Type() != ldvalue.ObjectType {
+			return false
+		}
+	}
+	return true
+}
+
+func parseIfRaw(v ldvalue.Value) (ldvalue.Value, error) {
+	if v.Type() != ldvalue.RawType {
+		return v, nil
+	}
+	if !json.Valid(v.AsRaw()) {
+		return ldvalue.Null(), lderrors.ErrValueRawJSONInvalid{}
+	}
+	return ldvalue.ParseLossless(v.AsRaw()), nil
+}
@@ -0,0 +1,187 @@
+package ldtoml
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	v := ldvalue.Parse([]byte(`{
+		"name": "My flag",
+		"on": true,
+		"weight": 2.5,
+		"huge": 1e300,
+		"version": 9007199254740993,
+		"tags": ["a", "b"],
+		"quoted key": "tab\tand \"quote\"\n",
+		"nested": {
+			"values": [1, {"x": []}],
+			"deeper": {"empty": {}, "n": 1}
+		},
+		"rules": [
+			{"id": "r1", "clauses": [{"attribute": "key"}]},
+			{"id": "r2", "target": {"variation": 0}}
+		],
+		"parent": {"child": {"a": 1}}
+	}`))
+	data, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `huge = 1e+300
+name = "My flag"
+on = true
+"quoted key" = "tab\tand \"quote\"\n"
+tags = ["a", "b"]
+version = 9007199254740993
+weight = 2.5
+
+[nested]
+values = [1, { x = [] }]
+
+[nested.deeper]
+n = 1
+
+[nested.deeper.empty]
+
+[parent.child]
+a = 1
+
+[[rules]]
+id = "r1"
+
+[[rules.clauses]]
+attribute = "key"
+
+[[rules]]
+id = "r2"
+
+[rules.target]
+variation = 0
+`, string(data))
+
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(v), "round trip: %s", parsed)
+}
+
+func TestMarshalSimpleValues(t *testing.T) {
+	for _, p := range []struct {
+		value    ldvalue.Value
+		expected string
+	}{
+		{ldvalue.Int(-3), "-3"},
+		{ldvalue.Int64(math.MaxInt64), "9223372036854775807"},
+		{ldvalue.Float64(1e20), "1e+20"},
+		{ldvalue.Number("1.50"), "1.50"},
+		{ldvalue.Float64(math.NaN()), "nan"},
+		{ldvalue.Float64(math.Inf(1)), "inf"},
+		{ldvalue.Float64(math.Inf(-1)), "-inf"},
+		{ldvalue.String(""), `""`},
+		{ldvalue.String("\x00\x1f\x7f\b\f\r\\é"), `"\u0000\u001F\u007F\b\f\r\\é"`},
+		{ldvalue.ArrayOf(), "[]"},
+		{ldvalue.ArrayOf(ldvalue.ArrayOf(ldvalue.ObjectBuild().Set("a b", ldvalue.Int(1)).Build())), `[[{ "a b" = 1 }]]`},
+		{ldvalue.Raw(json.RawMessage(`[1, 2]`)), "[1, 2]"},
+	} {
+		t.Run(p.expected, func(t *testing.T) {
+			data, err := Marshal(ldvalue.ObjectBuild().Set("v", p.value).Build())
+			require.NoError(t, err)
+			assert.Equal(t, "v = "+p.expected+"\n", string(data))
+		})
+	}
+}
+
+func TestMarshalKeys(t *testing.T) {
+	v := ldvalue.ObjectBuild().
+		Set("", ldvalue.Int(1)).
+		Set("a.b", ldvalue.ObjectBuild().Set("c d", ldvalue.ObjectBuild().Set("x", ldvalue.Int(2)).Build()).Build()).
+		Set("under_score-dash", ldvalue.Int(3)).
+		Build()
+	data, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `"" = 1
+under_score-dash = 3
+
+["a.b"."c d"]
+x = 2
+`, string(data))
+
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, v, parsed)
+}
+
+func TestMarshalEmptyObject(t *testing.T) {
+	data, err := Marshal(ldvalue.ObjectBuild().Build())
+	require.NoError(t, err)
+	assert.Equal(t, "", string(data))
+}
+
+func TestMarshalErrors(t *testing.T) {
+	for _, p := range []struct {
+		name  string
+		value ldvalue.Value
+		err   error
+	}{
+		{"not an object", ldvalue.ArrayOf(),
+			lderrors.ErrTOMLUnrepresentable{
+				Message: "a TOML document must be a table, so the value must be a JSON object, not array"}},
+		{"null property", ldvalue.Parse([]byte(`{"a":null}`)),
+			lderrors.ErrTOMLUnrepresentable{Path: "a", Message: "TOML does not have a null value"}},
+		{"nested null", ldvalue.Parse([]byte(`{"a":{"b":[1,null]}}`)),
+			lderrors.ErrTOMLUnrepresentable{Path: "a.b[1]", Message: "TOML does not have a null value"}},
+		{"null in array of tables", ldvalue.Parse([]byte(`{"a":[{"b":{"c":null}}]}`)),
+			lderrors.ErrTOMLUnrepresentable{Path: "a[0].b.c", Message: "TOML does not have a null value"}},
+		{"integer too large", ldvalue.ObjectBuild().Set("a", ldvalue.Uint64(math.MaxUint64)).Build(),
+			lderrors.ErrTOMLUnrepresentable{Path: "a",
+				Message: "18446744073709551615 is outside the range of TOML integers"}},
+		{"invalid raw JSON", ldvalue.ObjectBuild().Set("a", ldvalue.Raw(json.RawMessage(`{`))).Build(),
+			lderrors.ErrValueRawJSONInvalid{}},
+		{"invalid raw JSON in array", ldvalue.ObjectBuild().
+			Set("a", ldvalue.ArrayOf(ldvalue.Raw(json.RawMessage(`{`)))).Build(),
+			lderrors.ErrValueRawJSONInvalid{}},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			_, err := Marshal(p.value)
+			assert.Equal(t, p.err, err)
+		})
+	}
+}
+
+func TestMarshalContext(t *testing.T) {
+	c := ldcontext.NewMulti(
+		ldcontext.NewBuilder("user-key").Name("Lucy").Build(),
+		ldcontext.NewBuilder("org-key").Kind("org").SetValue("address", ldvalue.ObjectBuild().Set("city", ldvalue.String("Oakland")).Build()).
+			Private("/address/city").Build(),
+	)
+	data, err := MarshalContext(c)
+	require.NoError(t, err)
+	assert.Equal(t, `kind = "multi"
+
+[org]
+key = "org-key"
+
+[org._meta]
+privateAttributes = ["/address/city"]
+
+[org.address]
+city = "Oakland"
+
+[user]
+key = "user-key"
+name = "Lucy"
+`, string(data))
+
+	roundTrip, err := ParseContext(data)
+	require.NoError(t, err)
+	assert.Equal(t, c, roundTrip)
+
+	_, err = MarshalContext(ldcontext.Context{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,8 @@
+// Package ldtoml converts between TOML documents and the LaunchDarkly SDK's [ldvalue.Value] type,
+// so that flag variation values and contexts can be written in TOML.
+//
+// Version 1.0.0 of the TOML specification (https://toml.io/en/v1.0.0) is supported. A TOML document
+// is always a table, so it corresponds to a JSON object. TOML has no null value, and JSON has no
+// date or time values, so Values containing nulls cannot be converted to TOML, and TOML dates and
+// times are converted to strings.
+package ldtoml
@@ -0,0 +1,744 @@
+package ldtoml
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// maxNestingDepth is the maximum depth of nested arrays and inline tables, which protects against
+// stack exhaustion when parsing untrusted input.
+const maxNestingDepth = 1000
+
+var ( //nolint:gochecknoglobals // patterns for the TOML value grammar
+	decimalIntPattern = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	hexIntPattern     = regexp.MustCompile(`^0x[0-9A-Fa-f](_?[0-9A-Fa-f])*$`)
+	octalIntPattern   = regexp.MustCompile(`^0o[0-7](_?[0-7])*$`)
+	binaryIntPattern  = regexp.MustCompile(`^0b[01](_?[01])*$`)
+	floatPattern      = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	localDatePattern  = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+	dateTimePattern   = regexp.MustCompile(
+		`^([0-9]{4})-([0-9]{2})-([0-9]{2})` +
+			`(?:[Tt ]([0-9]{2}):([0-9]{2}):([0-9]{2})(?:\.[0-9]+)?(?:[Zz]|[+-]([0-9]{2}):([0-9]{2}))?)?$`)
+	localTimePattern = regexp.MustCompile(`^([0-9]{2}):([0-9]{2}):([0-9]{2})(?:\.[0-9]+)?$`)
+)
+
+// Parse converts a TOML document to a Value.
+//
+// The document becomes a JSON object. Integers are stored exactly (see [ldvalue.Int64]), and floats
+// such as "1.5" become non-integer numbers. However, a float with an integral value, such as "1.0"
+// or "1e3", becomes the same Value as the corresponding integer, because a Value does not
+// distinguish between those (see [ldvalue.Float64]). Dates and times become strings in RFC 3339
+// format; the original text is kept, except that a space between the date and time is changed to
+// "T".
+//
+// If the data is not valid TOML, or contains a value that cannot be represented in JSON (nan or
+// inf), the error is of type [lderrors.ErrTOMLConversion].
+func Parse(data []byte) (ldvalue.Value, error) {
+	p := parser{data: data, root: newTable()}
+	if err := p.parseDocument(); err != nil {
+		return ldvalue.Null(), err
+	}
+	return p.root.toValue(), nil
+}
+
+// ParseContext converts a TOML document to a Context, by first converting it with [Parse] and then
+// interpreting the result in the same way as the JSON representation of a Context. See
+// [ldcontext.Context.UnmarshalJSON].
+func ParseContext(data []byte) (ldcontext.Context, error) {
+	var c ldcontext.Context
+	v, err := Parse(data)
+	if err != nil {
+		return c, err
+	}
+	err = c.UnmarshalJSON([]byte(v.JSONString()))
+	return c, err
+}
+
+// table is a TOML table that is still being parsed. Tables that are defined by headers or dotted keys
+// can be extended by later lines of the document, so they are not converted to Values until the end.
+// Inline tables cannot be extended, so they are stored as Values.
+type table struct {
+	entries map[string]any // each value is an ldvalue.Value, a *table, or an *arrayOfTables
+	defined bool           // true if the table was defined by a [table] header
+	dotted  bool           // true if the table was created by a dotted key
+}
+
+type arrayOfTables struct {
+	tables []*table
+}
+
+type keyPart struct {
+	name string
+	pos  int
+}
+
+type parser struct {
+	data    []byte
+	pos     int
+	root    *table
+	current *table
+}
+
+func newTable() *table {
+	return &table{entries: make(map[string]any)}
+}
+
+func (t *table) toValue() ldvalue.Value {
+	builder := ldvalue.ObjectBuildWithCapacity(len(t.entries))
+	for key, entry := range t.entries {
+		switch e := entry.(type) {
+		case *table:
+			builder.Set(key, e.toValue())
+		case *arrayOfTables:
+			items := ldvalue.ArrayBuildWithCapacity(len(e.tables))
+			for _, item := range e.tables {
+				items.Add(item.toValue())
+			}
+			builder.Set(key, items.Build())
+		case ldvalue.Value:
+			builder.Set(key, e)
+		}
+	}
+	return builder.Build()
+}
+
+func (p *parser) errorAt(pos int, format string, args ...any) error {
+	line, column := 1, 1
+	for _, b := range p.data[:pos] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return lderrors.ErrTOMLConversion{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// describe returns a description of the next character for use in error messages.
+func (p *parser) describe() string {
+	if p.pos >= len(p.data) {
+		return "end of input"
+	}
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.data[p.pos:], []byte(s))
+}
+
+func (p *parser) parseDocument() error {
+	for pos := 0; pos < len(p.data); {
+		r, size := utf8.DecodeRune(p.data[pos:])
+		if r == utf8.RuneError && size == 1 {
+			return p.errorAt(pos, "invalid UTF-8 encoding")
+		}
+		pos += size
+	}
+	if p.hasPrefix("\uFEFF") { // an optional byte order mark
+		p.pos += len("\uFEFF")
+	}
+	p.current = p.root
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil
+		}
+		switch p.data[p.pos] {
+		case '#', '\r', '\n':
+		case '[':
+			if err := p.parseTableHeader(); err != nil {
+				return err
+			}
+		default:
+			if err := p.parseKeyValue(p.current, 0); err != nil {
+				return err
+			}
+		}
+		if err := p.parseEndOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) skipWhitespace() {
+	for p.pos < len(p.data) && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) skipComment() error {
+	if p.pos >= len(p.data) || p.data[p.pos] != '#' {
+		return nil
+	}
+	for p.pos++; p.pos < len(p.data) && p.data[p.pos] != '\n' && !p.hasPrefix("\r\n"); p.pos++ {
+		if isControl(p.data[p.pos]) {
+			return p.errorAt(p.pos, "control characters are not allowed in comments")
+		}
+	}
+	return nil
+}
+
+func (p *parser) consumeNewline() bool {
+	switch {
+	case p.hasPrefix("\n"):
+		p.pos++
+	case p.hasPrefix("\r\n"):
+		p.pos += 2
+	default:
+		return false
+	}
+	return true
+}
+
+func (p *parser) skipWhitespaceCommentsAndNewlines() error {
+	for {
+		p.skipWhitespace()
+		if err := p.skipComment(); err != nil {
+			return err
+		}
+		if !p.consumeNewline() {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseEndOfLine() error {
+	p.skipWhitespace()
+	if err := p.skipComment(); err != nil {
+		return err
+	}
+	if p.pos >= len(p.data) || p.consumeNewline() {
+		return nil
+	}
+	return p.errorAt(p.pos, "expected end of line, found %s", p.describe())
+}
+
+func (p *parser) parseTableHeader() error {
+	isArray := p.hasPrefix("[[")
+	closing := "]"
+	if isArray {
+		closing = "]]"
+	}
+	p.pos += len(closing)
+	p.skipWhitespace()
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if !p.hasPrefix(closing) {
+		return p.errorAt(p.pos, "expected %q, found %s", closing, p.describe())
+	}
+	p.pos += len(closing)
+
+	t := p.root
+	for i, key := range keys[:len(keys)-1] {
+		switch existing := t.entries[key.name].(type) {
+		case nil:
+			child := newTable()
+			t.entries[key.name] = child
+			t = child
+		case *table:
+			t = existing
+		case *arrayOfTables:
+			t = existing.tables[len(existing.tables)-1]
+		default:
+			return p.errorAt(key.pos, "cannot define a table inside %s, which is not a table", keyPath(keys[:i+1]))
+		}
+	}
+	last := keys[len(keys)-1]
+	if isArray {
+		array, ok := t.entries[last.name].(*arrayOfTables)
+		if !ok {
+			if t.entries[last.name] != nil {
+				return p.errorAt(last.pos, "cannot define array of tables %s, because it is already defined as a table or value",
+					keyPath(keys))
+			}
+			array = &arrayOfTables{}
+			t.entries[last.name] = array
+		}
+		p.current = newTable()
+		array.tables = append(array.tables, p.current)
+		return nil
+	}
+	switch existing := t.entries[last.name].(type) {
+	case nil:
+		p.current = newTable()
+		p.current.defined = true
+		t.entries[last.name] = p.current
+	case *table:
+		if existing.defined || existing.dotted {
+			return p.errorAt(last.pos, "table %s is defined more than once", keyPath(keys))
+		}
+		existing.defined = true
+		p.current = existing
+	default:
+		return p.errorAt(last.pos, "cannot define table %s, because it is already defined as a value or array of tables",
+			keyPath(keys))
+	}
+	return nil
+}
+
+func (p *parser) parseKeyValue(t *table, depth int) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if !p.hasPrefix("=") {
+		return p.errorAt(p.pos, `expected "=" after key, found %s`, p.describe())
+	}
+	p.pos++
+	p.skipWhitespace()
+
+	for i, key := range keys[:len(keys)-1] {
+		switch existing := t.entries[key.name].(type) {
+		case nil:
+			child := newTable()
+			child.dotted = true
+			t.entries[key.name] = child
+			t = child
+		case *table:
+			if !existing.dotted {
+				return p.errorAt(key.pos, "cannot use a dotted key to add to table %s, which is defined elsewhere",
+					keyPath(keys[:i+1]))
+			}
+			t = existing
+		default:
+			return p.errorAt(key.pos, "cannot use a dotted key to add to %s, which is not a table", keyPath(keys[:i+1]))
+		}
+	}
+	last := keys[len(keys)-1]
+	if t.entries[last.name] != nil {
+		return p.errorAt(last.pos, "key %s is defined more than once", keyPath(keys))
+	}
+	value, err := p.parseValue(depth)
+	if err != nil {
+		return err
+	}
+	t.entries[last.name] = value
+	return nil
+}
+
+// parseKey parses a simple or dotted key, and any whitespace after it.
+func (p *parser) parseKey() ([]keyPart, error) {
+	var keys []keyPart
+	for {
+		start := p.pos
+		var name string
+		var err error
+		switch {
+		case p.hasPrefix(`"""`) || p.hasPrefix("'''"):
+			return nil, p.errorAt(p.pos, "a multi-line string cannot be used as a key")
+		case p.hasPrefix(`"`):
+			name, err = p.parseBasicString()
+		case p.hasPrefix("'"):
+			name, err = p.parseLiteralString()
+		default:
+			for p.pos < len(p.data) && isBareKeyChar(p.data[p.pos]) {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.errorAt(p.pos, "expected a key, found %s", p.describe())
+			}
+			name = string(p.data[start:p.pos])
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, keyPart{name: name, pos: start})
+		p.skipWhitespace()
+		if !p.hasPrefix(".") {
+			return keys, nil
+		}
+		p.pos++
+		p.skipWhitespace()
+	}
+}
+
+func (p *parser) parseValue(depth int) (ldvalue.Value, error) {
+	var s string
+	var err error
+	switch {
+	case p.hasPrefix(`"""`):
+		s, err = p.parseMultiLineBasicString()
+	case p.hasPrefix(`"`):
+		s, err = p.parseBasicString()
+	case p.hasPrefix("'''"):
+		s, err = p.parseMultiLineLiteralString()
+	case p.hasPrefix("'"):
+		s, err = p.parseLiteralString()
+	case p.hasPrefix("["):
+		return p.parseArray(depth + 1)
+	case p.hasPrefix("{"):
+		return p.parseInlineTable(depth + 1)
+	default:
+		return p.parseSimpleValue()
+	}
+	if err != nil {
+		return ldvalue.Null(), err
+	}
+	return ldvalue.String(s), nil
+}
+
+func (p *parser) parseArray(depth int) (ldvalue.Value, error) {
+	if depth > maxNestingDepth {
+		return ldvalue.Null(), p.errorAt(p.pos, "arrays and inline tables are nested too deeply")
+	}
+	p.pos++
+	builder := ldvalue.ArrayBuild()
+	for {
+		if err := p.skipWhitespaceCommentsAndNewlines(); err != nil {
+			return ldvalue.Null(), err
+		}
+		if p.hasPrefix("]") {
+			p.pos++
+			return builder.Build(), nil
+		}
+		item, err := p.parseValue(depth)
+		if err != nil {
+			return ldvalue.Null(), err
+		}
+		builder.Add(item)
+		if err := p.skipWhitespaceCommentsAndNewlines(); err != nil {
+			return ldvalue.Null(), err
+		}
+		switch {
+		case p.hasPrefix(","):
+			p.pos++
+		case p.hasPrefix("]"):
+			p.pos++
+			return builder.Build(), nil
+		default:
+			return ldvalue.Null(), p.errorAt(p.pos, `expected "," or "]" in array, found %s`, p.describe())
+		}
+	}
+}
+
+func (p *parser) parseInlineTable(depth int) (ldvalue.Value, error) {
+	if depth > maxNestingDepth {
+		return ldvalue.Null(), p.errorAt(p.pos, "arrays and inline tables are nested too deeply")
+	}
+	p.pos++
+	t := newTable()
+	p.skipWhitespace()
+	if p.hasPrefix("}") {
+		p.pos++
+		return t.toValue(), nil
+	}
+	for {
+		p.skipWhitespace()
+		if err := p.parseKeyValue(t, depth); err != nil {
+			return ldvalue.Null(), err
+		}
+		p.skipWhitespace()
+		switch {
+		case p.hasPrefix(","):
+			p.pos++
+		case p.hasPrefix("}"):
+			p.pos++
+			return t.toValue(), nil
+		default:
+			return ldvalue.Null(), p.errorAt(p.pos, `expected "," or "}" in inline table, found %s`, p.describe())
+		}
+	}
+}
+
+// parseSimpleValue parses a boolean, number, date, or time.
+func (p *parser) parseSimpleValue() (ldvalue.Value, error) {
+	start := p.pos
+	p.skipValueChars()
+	if p.pos == start {
+		return ldvalue.Null(), p.errorAt(p.pos, "expected a value, found %s", p.describe())
+	}
+	// A space can be used instead of "T" between a date and a time.
+	if localDatePattern.Match(p.data[start:p.pos]) && p.pos+3 < len(p.data) && p.data[p.pos] == ' ' &&
+		isDigit(p.data[p.pos+1]) && isDigit(p.data[p.pos+2]) && p.data[p.pos+3] == ':' {
+		p.pos++
+		p.skipValueChars()
+	}
+	token := string(p.data[start:p.pos])
+	switch {
+	case token == "true" || token == "false":
+		return ldvalue.Bool(token == "true"), nil
+	case strings.TrimLeft(token, "+-") == "inf" || strings.TrimLeft(token, "+-") == "nan":
+		return ldvalue.Null(), p.errorAt(start, "%s cannot be represented in JSON", token)
+	case decimalIntPattern.MatchString(token):
+		return p.parseInteger(start, token, token, 10)
+	case hexIntPattern.MatchString(token):
+		return p.parseInteger(start, token, token[2:], 16)
+	case octalIntPattern.MatchString(token):
+		return p.parseInteger(start, token, token[2:], 8)
+	case binaryIntPattern.MatchString(token):
+		return p.parseInteger(start, token, token[2:], 2)
+	case floatPattern.MatchString(token):
+		f, err := strconv.ParseFloat(strings.ReplaceAll(token, "_", ""), 64)
+		if err != nil || math.IsInf(f, 0) {
+			return ldvalue.Null(), p.errorAt(start, "number %s is out of range", token)
+		}
+		return ldvalue.Float64(f), nil
+	}
+	if m := dateTimePattern.FindStringSubmatch(token); m != nil {
+		if !validDate(m[1], m[2], m[3]) || (m[4] != "" && !validTime(m[4], m[5], m[6])) ||
+			(m[7] != "" && (atoi(m[7]) > 23 || atoi(m[8]) > 59)) {
+			return ldvalue.Null(), p.errorAt(start, "invalid date or time %s", token)
+		}
+		b := []byte(token)
+		if len(b) > len("2006-01-02") {
+			b[len("2006-01-02")] = 'T'
+		}
+		if b[len(b)-1] == 'z' {
+			b[len(b)-1] = 'Z'
+		}
+		return ldvalue.String(string(b)), nil
+	}
+	if m := localTimePattern.FindStringSubmatch(token); m != nil {
+		if !validTime(m[1], m[2], m[3]) {
+			return ldvalue.Null(), p.errorAt(start, "invalid date or time %s", token)
+		}
+		return ldvalue.String(token), nil
+	}
+	return ldvalue.Null(), p.errorAt(start, "invalid value %s (strings must be quoted)", token)
+}
+
+func (p *parser) skipValueChars() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if !isBareKeyChar(c) && c != '+' && c != '.' && c != ':' {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) parseInteger(start int, token, digits string, base int) (ldvalue.Value, error) {
+	n, err := strconv.ParseInt(strings.ReplaceAll(digits, "_", ""), base, 64)
+	if err != nil {
+		return ldvalue.Null(), p.errorAt(start, "integer %s is out of range", token)
+	}
+	return ldvalue.Int64(n), nil
+}
+
+func (p *parser) parseBasicString() (string, error) {
+	start := p.pos
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.data) {
+			return "", p.errorAt(start, "unterminated string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c == '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case c == '\n' || c == '\r':
+			return "", p.errorAt(p.pos, "line breaks are only allowed in multi-line strings")
+		case isControl(c):
+			return "", p.errorAt(p.pos, "control characters must be escaped in strings")
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) parseMultiLineBasicString() (string, error) {
+	start := p.pos
+	p.pos += len(`"""`)
+	p.consumeNewline() // a line break immediately after the opening delimiter is not part of the string
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.data) {
+			return "", p.errorAt(start, "unterminated string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case p.hasPrefix(`"""`):
+			p.parseMultiLineStringEnd(&sb, '"')
+			return sb.String(), nil
+		case c == '\\' && p.isLineEndingBackslash():
+			// A backslash at the end of a line removes all whitespace up to the next non-whitespace character.
+			for p.pos++; p.pos < len(p.data); p.pos++ {
+				if b := p.data[p.pos]; b != ' ' && b != '\t' && b != '\n' && !p.hasPrefix("\r\n") {
+					break
+				}
+			}
+		case c == '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case p.hasPrefix("\r\n"):
+			sb.WriteString("\r\n")
+			p.pos += 2
+		case isControl(c) && c != '\n':
+			return "", p.errorAt(p.pos, "control characters must be escaped in strings")
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) isLineEndingBackslash() bool {
+	pos := p.pos + 1
+	for pos < len(p.data) && (p.data[pos] == ' ' || p.data[pos] == '\t') {
+		pos++
+	}
+	return pos < len(p.data) && (p.data[pos] == '\n' || bytes.HasPrefix(p.data[pos:], []byte("\r\n")))
+}
+
+func (p *parser) parseLiteralString() (string, error) {
+	start := p.pos
+	p.pos++
+	for {
+		if p.pos >= len(p.data) {
+			return "", p.errorAt(start, "unterminated string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case c == '\'':
+			p.pos++
+			return string(p.data[start+1 : p.pos-1]), nil
+		case c == '\n' || c == '\r':
+			return "", p.errorAt(p.pos, "line breaks are only allowed in multi-line strings")
+		case isControl(c):
+			return "", p.errorAt(p.pos, "control characters are not allowed in literal strings")
+		default:
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) parseMultiLineLiteralString() (string, error) {
+	start := p.pos
+	p.pos += len("'''")
+	p.consumeNewline()
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.data) {
+			return "", p.errorAt(start, "unterminated string")
+		}
+		c := p.data[p.pos]
+		switch {
+		case p.hasPrefix("'''"):
+			p.parseMultiLineStringEnd(&sb, '\'')
+			return sb.String(), nil
+		case p.hasPrefix("\r\n"):
+			sb.WriteString("\r\n")
+			p.pos += 2
+		case isControl(c) && c != '\n':
+			return "", p.errorAt(p.pos, "control characters are not allowed in literal strings")
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseMultiLineStringEnd consumes the closing delimiter of a multi-line string. Up to two quote
+// characters can appear immediately before the delimiter, so """"" ends a string with "".
+func (p *parser) parseMultiLineStringEnd(sb *strings.Builder, quote byte) {
+	p.pos += 3
+	for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] == quote; i++ {
+		sb.WriteByte(quote)
+		p.pos++
+	}
+}
+
+func (p *parser) parseEscape(sb *strings.Builder) error {
+	start := p.pos
+	p.pos++
+	if p.pos >= len(p.data) {
+		return p.errorAt(start, "unterminated string")
+	}
+	c := p.data[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.data) {
+			return p.errorAt(start, "invalid Unicode escape sequence")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+n]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorAt(start, "invalid Unicode escape sequence %s", p.data[start:p.pos+n])
+		}
+		sb.WriteRune(rune(code))
+		p.pos += n
+	default:
+		r, _ := utf8.DecodeRune(p.data[start+1:])
+		return p.errorAt(start, `invalid escape sequence "\%c"`, r)
+	}
+	return nil
+}
+
+func validDate(year, month, day string) bool {
+	y, m, d := atoi(year), atoi(month), atoi(day)
+	daysInMonth := [...]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if m < 1 || m > 12 {
+		return false
+	}
+	if m == 2 && y%4 == 0 && (y%100 != 0 || y%400 == 0) {
+		return d >= 1 && d <= 29
+	}
+	return d >= 1 && d <= daysInMonth[m-1]
+}
+
+func validTime(hour, minute, second string) bool {
+	return atoi(hour) <= 23 && atoi(minute) <= 59 && atoi(second) <= 60 // 60 is allowed for leap seconds
+}
+
+// atoi converts a string of decimal digits that is already known to be valid.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func keyPath(keys []keyPart) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, formatKey(k.name))
+	}
+	return strings.Join(parts, ".")
+}
+
+func isBareKeyChar(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || isDigit(c) || c == '_' || c == '-'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isControl(c byte) bool {
+	return (c < 0x20 && c != '\t') || c == 0x7f
+}
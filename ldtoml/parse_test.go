@@ -0,0 +1,244 @@
+package ldtoml
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValues(t *testing.T) {
+	for _, p := range []struct {
+		toml     string
+		expected ldvalue.Value
+	}{
+		{"true", ldvalue.Bool(true)},
+		{"false", ldvalue.Bool(false)},
+		{"0", ldvalue.Int(0)},
+		{"+99", ldvalue.Int(99)},
+		{"-17", ldvalue.Int(-17)},
+		{"1_000", ldvalue.Int(1000)},
+		{"9007199254740993", ldvalue.Int64(9007199254740993)},
+		{"9223372036854775807", ldvalue.Int64(math.MaxInt64)},
+		{"-9223372036854775808", ldvalue.Int64(math.MinInt64)},
+		{"0xDEAD_beef", ldvalue.Int(0xdeadbeef)},
+		{"0o755", ldvalue.Int(0o755)},
+		{"0b1101", ldvalue.Int(13)},
+		{"1.5", ldvalue.Float64(1.5)},
+		{"-0.01", ldvalue.Float64(-0.01)},
+		{"5e+22", ldvalue.Float64(5e22)},
+		{"6.626e-34", ldvalue.Float64(6.626e-34)},
+		{"1e06", ldvalue.Int(1000000)},
+		{"224_617.445_991", ldvalue.Float64(224617.445991)},
+		{`"abc"`, ldvalue.String("abc")},
+		{`""`, ldvalue.String("")},
+		{`"tab\there \"quoted\" \\ \u00E9 \U0001F600"`, ldvalue.String("tab\there \"quoted\" \\ \u00e9 \U0001f600")},
+		{`'C:\Users\nodejs'`, ldvalue.String(`C:\Users\nodejs`)},
+		{"\"\"\"\nline 1\nline 2\"\"\"", ldvalue.String("line 1\nline 2")},
+		{"\"\"\"one \\\n    two \\\n\n    three\"\"\"", ldvalue.String("one two three")},
+		{`"""a "quoted" word"""""`, ldvalue.String(`a "quoted" word""`)},
+		{"'''\nraw \\n\n'''", ldvalue.String("raw \\n\n")},
+		{"''''one quote'''", ldvalue.String("'one quote")},
+		{"1979-05-27T07:32:00Z", ldvalue.String("1979-05-27T07:32:00Z")},
+		{"1979-05-27 00:32:00.999999-07:00", ldvalue.String("1979-05-27T00:32:00.999999-07:00")},
+		{"1979-05-27t07:32:00z", ldvalue.String("1979-05-27T07:32:00Z")},
+		{"1979-05-27T07:32:00", ldvalue.String("1979-05-27T07:32:00")},
+		{"2000-02-29", ldvalue.String("2000-02-29")},
+		{"07:32:00", ldvalue.String("07:32:00")},
+		{"[]", ldvalue.ArrayOf()},
+		{"[ 1, 2.5, \"x\", [true], ]", ldvalue.ArrayOf(ldvalue.Int(1), ldvalue.Float64(2.5), ldvalue.String("x"),
+			ldvalue.ArrayOf(ldvalue.Bool(true)))},
+		{"[\n  1, # first\n  2\n]", ldvalue.ArrayOf(ldvalue.Int(1), ldvalue.Int(2))},
+		{"{}", ldvalue.ObjectBuild().Build()},
+		{"{ x = 1, y.z = [2] }", ldvalue.ObjectBuild().Set("x", ldvalue.Int(1)).
+			Set("y", ldvalue.ObjectBuild().Set("z", ldvalue.ArrayOf(ldvalue.Int(2))).Build()).Build()},
+	} {
+		t.Run(p.toml, func(t *testing.T) {
+			v, err := Parse([]byte("value = " + p.toml))
+			require.NoError(t, err)
+			assert.Equal(t, ldvalue.ObjectBuild().Set("value", p.expected).Build(), v)
+		})
+	}
+}
+
+func TestParseIntegersAndFloatsAreDistinguished(t *testing.T) {
+	v, err := Parse([]byte("a = 2\nb = 2.5\nc = 9007199254740993\n"))
+	require.NoError(t, err)
+	assert.True(t, v.GetByKey("a").IsInt())
+	assert.False(t, v.GetByKey("b").IsInt())
+	assert.Equal(t, "9007199254740993", v.GetByKey("c").JSONString())
+}
+
+func TestParseIntegralFloatsBecomeIntegers(t *testing.T) {
+	v, err := Parse([]byte("a = 1.0\nb = 1e3\n"))
+	require.NoError(t, err)
+	assert.True(t, v.GetByKey("a").IsInt())
+	assert.True(t, v.GetByKey("b").IsInt())
+
+	output, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, "a = 1\nb = 1000\n", string(output))
+}
+
+func TestParseEmptyDocument(t *testing.T) {
+	for _, doc := range []string{"", "\n", "# comment\r\n", "\uFEFF"} {
+		v, err := Parse([]byte(doc))
+		require.NoError(t, err)
+		assert.Equal(t, ldvalue.ObjectBuild().Build(), v)
+	}
+}
+
+func TestParseTables(t *testing.T) {
+	v, err := Parse([]byte(`
+# This is a TOML document
+
+title = "TOML Example"
+"quoted key" = 1
+site."google.com" = true
+
+[owner]
+name = "Tom Preston-Werner"
+dob = 1979-05-27T07:32:00-08:00
+
+[database]
+enabled = true
+ports = [ 8000, 8001, 8002 ]
+temp_targets = { cpu = 79.5, case = 72.0 }
+
+[servers]
+
+  [servers.alpha]
+  ip = "10.0.0.1"
+
+[fruit]
+apple.color = "red"
+apple.taste.sweet = true
+
+[fruit.apple.texture]
+smooth = true
+
+[x.y.z.w]
+[x]
+a = 1
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+
+[[products]]
+name = "Nail"
+
+[[products.variants]]
+size = 1
+
+[products.details]
+color = "gray"
+`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "TOML Example",
+		"quoted key": 1,
+		"site": {"google.com": true},
+		"owner": {"name": "Tom Preston-Werner", "dob": "1979-05-27T07:32:00-08:00"},
+		"database": {"enabled": true, "ports": [8000, 8001, 8002], "temp_targets": {"cpu": 79.5, "case": 72.0}},
+		"servers": {"alpha": {"ip": "10.0.0.1"}},
+		"fruit": {"apple": {"color": "red", "taste": {"sweet": true}, "texture": {"smooth": true}}},
+		"x": {"a": 1, "y": {"z": {"w": {}}}},
+		"products": [
+			{"name": "Hammer"},
+			{},
+			{"name": "Nail", "variants": [{"size": 1}], "details": {"color": "gray"}}
+		]
+	}`, v.JSONString())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, p := range []struct {
+		name, toml      string
+		line, column    int
+		messageContains string
+	}{
+		{"missing value", "a =\n", 1, 4, "expected a value"},
+		{"missing equals", "a 1\n", 1, 3, `expected "="`},
+		{"missing key", "= 1\n", 1, 1, "expected a key"},
+		{"unquoted string", "a = abc\n", 1, 5, "strings must be quoted"},
+		{"two values on a line", "a = 1 b = 2\n", 1, 7, "expected end of line"},
+		{"duplicate key", "a = 1\nb = 2\na = 3\n", 3, 1, "key a is defined more than once"},
+		{"duplicate quoted key", "a = 1\n\"a\" = 2\n", 2, 1, "key a is defined more than once"},
+		{"duplicate table", "[a]\n[b]\n[a]\n", 3, 2, "table a is defined more than once"},
+		{"table defined by dotted key", "a.b = 1\n[a]\n", 2, 2, "table a is defined more than once"},
+		{"dotted key into table", "[a.b]\n[a]\nb.c = 1\n", 3, 1, "table b, which is defined elsewhere"},
+		{"dotted key into value", "a = 1\na.b = 2\n", 2, 1, "not a table"},
+		{"table over value", "a = 1\n[a]\n", 2, 2, "already defined as a value"},
+		{"table inside value", "a = {}\n[a.b]\n", 2, 2, "inside a, which is not a table"},
+		{"array of tables over array", "a = []\n[[a]]\n", 2, 3, "already defined"},
+		{"table over array of tables", "[[a]]\n[a]\n", 2, 2, "already defined"},
+		{"duplicate key in inline table", "a = {b = 1, b = 2}\n", 1, 13, "key b is defined more than once"},
+		{"trailing comma in inline table", "a = {b = 1,}\n", 1, 12, "expected a key"},
+		{"newline in inline table", "a = {b = 1\n}\n", 1, 11, `expected "," or "}"`},
+		{"unclosed array", "a = [1, 2\n", 2, 1, `expected "," or "]"`},
+		{"unclosed header", "[a\n", 1, 3, `expected "]"`},
+		{"unterminated string", "a = \"abc\n", 1, 9, "line breaks"},
+		{"unterminated multi-line string", "a = '''abc\n", 1, 5, "unterminated string"},
+		{"invalid escape", `a = "\x41"`, 1, 6, `invalid escape sequence "\x"`},
+		{"invalid unicode escape", `a = "\uD800"`, 1, 6, "invalid Unicode escape sequence"},
+		{"control character", "a = \"\x01\"", 1, 6, "control characters"},
+		{"control character in comment", "a = 1 # \x7f\n", 1, 9, "control characters"},
+		{"leading zero", "a = 01\n", 1, 5, "invalid value 01"},
+		{"bad underscore", "a = 1__0\n", 1, 5, "invalid value 1__0"},
+		{"integer out of range", "a = 9223372036854775808\n", 1, 5, "out of range"},
+		{"float out of range", "a = 1e400\n", 1, 5, "out of range"},
+		{"nan", "a = [nan]\n", 1, 6, "nan cannot be represented in JSON"},
+		{"infinity", "a = -inf\n", 1, 5, "-inf cannot be represented in JSON"},
+		{"invalid date", "a = 2001-02-29\n", 1, 5, "invalid date or time"},
+		{"invalid time", "a = 24:00:00\n", 1, 5, "invalid date or time"},
+		{"multi-line key", "\"\"\"a\"\"\" = 1\n", 1, 1, "multi-line string"},
+		{"invalid UTF-8", "a = \"\xff\"\n", 1, 6, "invalid UTF-8"},
+		{"too deeply nested", "a = " + strings.Repeat("[", maxNestingDepth+1), 1, 5 + maxNestingDepth, "nested too deeply"},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			_, err := Parse([]byte(p.toml))
+			require.Error(t, err)
+			require.IsType(t, lderrors.ErrTOMLConversion{}, err)
+			e := err.(lderrors.ErrTOMLConversion)
+			assert.Equal(t, p.line, e.Line, "line")
+			assert.Equal(t, p.column, e.Column, "column")
+			assert.Contains(t, e.Message, p.messageContains)
+		})
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	c, err := ParseContext([]byte(`
+kind = "multi"
+
+[user]
+key = "user-key"
+name = "Lucy"
+tags = ["a", "b"]
+
+[org]
+key = "org-key"
+address = { city = "Oakland" }
+_meta.privateAttributes = ["/address/city"]
+`))
+	require.NoError(t, err)
+	expected := ldcontext.NewMulti(
+		ldcontext.NewBuilder("user-key").Name("Lucy").SetValue("tags", ldvalue.ArrayOf(ldvalue.String("a"), ldvalue.String("b"))).Build(),
+		ldcontext.NewBuilder("org-key").Kind("org").SetValue("address", ldvalue.ObjectBuild().Set("city", ldvalue.String("Oakland")).Build()).
+			Private("/address/city").Build(),
+	)
+	assert.Equal(t, expected, c)
+
+	_, err = ParseContext([]byte(`kind = "user"`))
+	assert.Error(t, err)
+	_, err = ParseContext([]byte(`key = `))
+	assert.IsType(t, lderrors.ErrTOMLConversion{}, err)
+}
@@ -24,7 +24,7 @@ const (
 	// BoolType describes a boolean value. See [Bool].
 	BoolType ValueType = iota
 	// NumberType describes a numeric value. JSON does not have separate types for int and float, but
-	// you can convert to either. See [Int] and [Float64].
+	// you can convert to either. See [Int], [Int64], [Uint64], [Float64], and [Number].
 	NumberType ValueType = iota
 	// StringType describes a string value. See [String].
 	StringType ValueType = iota
@@ -16,11 +16,11 @@ package ldvalue
 //
 //	var myBoolPointer *int = NewOptionalBool(true).AsPointer()
 //
-// The reason LaunchDarkly code uses this specific type instead of a generic Optional[T] is for
-// efficiency in JSON marshaling/unmarshaling. A generic type would have to use reflection and
-// dynamic typecasting for its marshal/unmarshal methods.
+// The reason LaunchDarkly code uses this specific type instead of the generic [Optional] is for
+// efficiency in JSON marshaling/unmarshaling. A generic type has to use dynamic typecasting for its
+// marshal/unmarshal methods.
 type OptionalBool struct {
-	optValue optional[bool]
+	optValue Optional[bool]
 }
 
 // NewOptionalBool constructs an OptionalBool that has a bool value.
@@ -28,34 +28,39 @@ type OptionalBool struct {
 // There is no corresponding method for creating an OptionalBool with no value; simply use the
 // empty literal OptionalBool{}.
 func NewOptionalBool(value bool) OptionalBool {
-	return OptionalBool{optValue: newOptional(value)}
+	return OptionalBool{optValue: NewOptional(value)}
 }
 
 // NewOptionalBoolFromPointer constructs an OptionalBool from a bool pointer. If the pointer is
 // non-nil, then the OptionalBool copies its value; otherwise the OptionalBool has no value.
 func NewOptionalBoolFromPointer(valuePointer *bool) OptionalBool {
-	return OptionalBool{optValue: newOptionalFromPointer(valuePointer)}
+	return OptionalBool{optValue: NewOptionalFromPointer(valuePointer)}
+}
+
+// NewOptionalBoolFromOptional constructs an OptionalBool from the equivalent generic [Optional].
+func NewOptionalBoolFromOptional(o Optional[bool]) OptionalBool {
+	return OptionalBool{optValue: o}
 }
 
 // IsDefined returns true if the OptionalBool contains a bool value, or false if it has no value.
 func (o OptionalBool) IsDefined() bool {
-	return o.optValue.isDefined()
+	return o.optValue.IsDefined()
 }
 
 // BoolValue returns the OptionalBool's value, or false if it has no value.
 func (o OptionalBool) BoolValue() bool {
-	return o.optValue.getOrZeroValue()
+	return o.optValue.Value()
 }
 
 // Get is a combination of BoolValue and IsDefined. If the OptionalBool contains a bool value, it
 // returns that value and true; otherwise it returns false and false.
 func (o OptionalBool) Get() (bool, bool) {
-	return o.optValue.get()
+	return o.optValue.Get()
 }
 
 // OrElse returns the OptionalBool's value if it has one, or else the specified fallback value.
 func (o OptionalBool) OrElse(valueIfEmpty bool) bool {
-	return o.optValue.getOrElse(valueIfEmpty)
+	return o.optValue.OrElse(valueIfEmpty)
 }
 
 // AsPointer returns the OptionalBool's value as a bool pointer if it has a value, or nil
@@ -63,12 +68,17 @@ func (o OptionalBool) OrElse(valueIfEmpty bool) bool {
 //
 // The bool value, if any, is copied rather than returning to a pointer to the internal field.
 func (o OptionalBool) AsPointer() *bool {
-	return o.optValue.getAsPointer()
+	return o.optValue.AsPointer()
+}
+
+// AsOptional converts the OptionalBool to the equivalent generic [Optional].
+func (o OptionalBool) AsOptional() Optional[bool] {
+	return o.optValue
 }
 
 // AsValue converts the OptionalBool to a [Value], which is either [Null]() or a boolean value.
 func (o OptionalBool) AsValue() Value {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		return Bool(value)
 	}
 	return Null()
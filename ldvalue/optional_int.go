@@ -19,11 +19,11 @@ package ldvalue
 // This type is used in ldreason.EvaluationDetail.VariationIndex, and for other similar fields
 // in the LaunchDarkly Go SDK where an int value may or may not be defined.
 //
-// The reason LaunchDarkly code uses this specific type instead of a generic Optional[T] is for
-// efficiency in JSON marshaling/unmarshaling. A generic type would have to use reflection and
-// dynamic typecasting for its marshal/unmarshal methods.
+// The reason LaunchDarkly code uses this specific type instead of the generic [Optional] is for
+// efficiency in JSON marshaling/unmarshaling. A generic type has to use dynamic typecasting for its
+// marshal/unmarshal methods.
 type OptionalInt struct {
-	optValue optional[int]
+	optValue Optional[int]
 }
 
 // NewOptionalInt constructs an OptionalInt that has an int value.
@@ -31,34 +31,39 @@ type OptionalInt struct {
 // There is no corresponding method for creating an OptionalInt with no value; simply use the
 // empty literal OptionalInt{}.
 func NewOptionalInt(value int) OptionalInt {
-	return OptionalInt{optValue: newOptional(value)}
+	return OptionalInt{optValue: NewOptional(value)}
 }
 
 // NewOptionalIntFromPointer constructs an OptionalInt from an int pointer. If the pointer is
 // non-nil, then the OptionalInt copies its value; otherwise the OptionalInt has no value.
 func NewOptionalIntFromPointer(valuePointer *int) OptionalInt {
-	return OptionalInt{optValue: newOptionalFromPointer(valuePointer)}
+	return OptionalInt{optValue: NewOptionalFromPointer(valuePointer)}
+}
+
+// NewOptionalIntFromOptional constructs an OptionalInt from the equivalent generic [Optional].
+func NewOptionalIntFromOptional(o Optional[int]) OptionalInt {
+	return OptionalInt{optValue: o}
 }
 
 // IsDefined returns true if the OptionalInt contains an int value, or false if it has no value.
 func (o OptionalInt) IsDefined() bool {
-	return o.optValue.isDefined()
+	return o.optValue.IsDefined()
 }
 
 // IntValue returns the OptionalInt's value, or zero if it has no value.
 func (o OptionalInt) IntValue() int {
-	return o.optValue.getOrZeroValue()
+	return o.optValue.Value()
 }
 
 // Get is a combination of IntValue and IsDefined. If the OptionalInt contains an int value, it
 // returns that value and true; otherwise it returns zero and false.
 func (o OptionalInt) Get() (int, bool) {
-	return o.optValue.get()
+	return o.optValue.Get()
 }
 
 // OrElse returns the OptionalInt's value if it has one, or else the specified fallback value.
 func (o OptionalInt) OrElse(valueIfEmpty int) int {
-	return o.optValue.getOrElse(valueIfEmpty)
+	return o.optValue.OrElse(valueIfEmpty)
 }
 
 // AsPointer returns the OptionalInt's value as an int pointer if it has a value, or nil
@@ -66,12 +71,17 @@ func (o OptionalInt) OrElse(valueIfEmpty int) int {
 //
 // The int value, if any, is copied rather than returning to a pointer to the internal field.
 func (o OptionalInt) AsPointer() *int {
-	return o.optValue.getAsPointer()
+	return o.optValue.AsPointer()
+}
+
+// AsOptional converts the OptionalInt to the equivalent generic [Optional].
+func (o OptionalInt) AsOptional() Optional[int] {
+	return o.optValue
 }
 
 // AsValue converts the OptionalInt to a [Value], which is either [Null]() or a number value.
 func (o OptionalInt) AsValue() Value {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		return Int(value)
 	}
 	return Null()
@@ -18,11 +18,11 @@ package ldvalue
 //
 //	var myStringPointer *string = NewOptionalString("x").AsPointer()
 //
-// The reason LaunchDarkly code uses this specific type instead of a generic Optional[T] is for
-// efficiency in JSON marshaling/unmarshaling. A generic type would have to use reflection and
-// dynamic typecasting for its marshal/unmarshal methods.
+// The reason LaunchDarkly code uses this specific type instead of the generic [Optional] is for
+// efficiency in JSON marshaling/unmarshaling. A generic type has to use dynamic typecasting for its
+// marshal/unmarshal methods.
 type OptionalString struct {
-	optValue optional[string]
+	optValue Optional[string]
 }
 
 // NewOptionalString constructs an OptionalString that has a string value.
@@ -30,40 +30,45 @@ type OptionalString struct {
 // There is no corresponding method for creating an OptionalString with no value; simply use
 // the empty literal OptionalString{}.
 func NewOptionalString(value string) OptionalString {
-	return OptionalString{optValue: newOptional(value)}
+	return OptionalString{optValue: NewOptional(value)}
 }
 
 // NewOptionalStringFromPointer constructs an OptionalString from a string pointer. If the pointer
 // is non-nil, then the OptionalString copies its value; otherwise the OptionalString has no value.
 func NewOptionalStringFromPointer(valuePointer *string) OptionalString {
-	return OptionalString{optValue: newOptionalFromPointer(valuePointer)}
+	return OptionalString{optValue: NewOptionalFromPointer(valuePointer)}
+}
+
+// NewOptionalStringFromOptional constructs an OptionalString from the equivalent generic [Optional].
+func NewOptionalStringFromOptional(o Optional[string]) OptionalString {
+	return OptionalString{optValue: o}
 }
 
 // IsDefined returns true if the OptionalString contains a string value, or false if it has no value.
 func (o OptionalString) IsDefined() bool {
-	return o.optValue.isDefined()
+	return o.optValue.IsDefined()
 }
 
 // StringValue returns the OptionalString's value, or an empty string if it has no value.
 func (o OptionalString) StringValue() string {
-	return o.optValue.getOrZeroValue()
+	return o.optValue.Value()
 }
 
 // Get is a combination of StringValue and IsDefined. If the OptionalString contains a string value,
 // it returns that value and true; otherwise it returns an empty string and false.
 func (o OptionalString) Get() (string, bool) {
-	return o.optValue.get()
+	return o.optValue.Get()
 }
 
 // OrElse returns the OptionalString's value if it has one, or else the specified fallback value.
 func (o OptionalString) OrElse(valueIfEmpty string) string {
-	return o.optValue.getOrElse(valueIfEmpty)
+	return o.optValue.OrElse(valueIfEmpty)
 }
 
 // OnlyIfNonEmptyString returns the same OptionalString unless it contains an empty string (""), in
 // which case it returns an OptionalString that has no value.
 func (o OptionalString) OnlyIfNonEmptyString() OptionalString {
-	if value, ok := o.optValue.get(); ok && value == "" {
+	if value, ok := o.optValue.Get(); ok && value == "" {
 		return OptionalString{}
 	}
 	return o
@@ -74,12 +79,17 @@ func (o OptionalString) OnlyIfNonEmptyString() OptionalString {
 //
 // The string value, if any, is copied rather than returning to a pointer to the internal field.
 func (o OptionalString) AsPointer() *string {
-	return o.optValue.getAsPointer()
+	return o.optValue.AsPointer()
+}
+
+// AsOptional converts the OptionalString to the equivalent generic [Optional].
+func (o OptionalString) AsOptional() Optional[string] {
+	return o.optValue
 }
 
 // AsValue converts the OptionalString to a [Value], which is either [Null]() or a string value.
 func (o OptionalString) AsValue() Value {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		return String(value)
 	}
 	return Null()
@@ -1,46 +1,128 @@
 package ldvalue
 
-// This generic internal representation of optional values reduces the amount of boilerplate
-// in the implementation of OptionalBool, OptionalInt, and OptionalString.
-
-type optional[T any] struct {
+// Optional represents a value of any type that may or may not be defined. This is similar to using
+// a pointer to distinguish between a zero value and nil, but it is safer because it does not expose
+// a pointer to any mutable value.
+//
+// To create an instance with a value, use [NewOptional]. There is no corresponding function for
+// creating an instance with no value; simply use the empty literal Optional[T]{}.
+//
+//	o1 := ldvalue.NewOptional(1.5)
+//	o2 := ldvalue.NewOptional(time.Now())
+//	o3 := ldvalue.Optional[int64]{} // this does not have a value
+//
+// Optional has the same JSON, text, and EasyJSON behavior as [OptionalBool], [OptionalInt], and
+// [OptionalString]: an Optional with no value is represented as a JSON null, and an Optional with
+// a value is represented the same way as the value itself. Those types also use Optional as their
+// internal representation, and can be converted to and from it with methods such as
+// [OptionalInt.AsOptional] and [NewOptionalIntFromOptional].
+//
+// For bool, int, float64, and string values, Optional's JSON marshaling and unmarshaling are handled
+// directly. For all other types, they delegate to the type's own marshaling methods or to
+// [encoding/json]; this is less efficient, so LaunchDarkly code uses the concrete types such as
+// [OptionalInt] where possible.
+//
+// Since Go does not allow methods to have their own type parameters, transformations that change the
+// type of the value are provided as functions: see [MapOptional] and [FlatMapOptional].
+type Optional[T any] struct {
 	defined bool
 	value   T
 }
 
-func newOptional[T any](value T) optional[T] {
-	return optional[T]{defined: true, value: value}
+// NewOptional constructs an Optional that has a value.
+//
+// There is no corresponding function for creating an Optional with no value; simply use the empty
+// literal Optional[T]{}.
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{defined: true, value: value}
 }
 
-func newOptionalFromPointer[T any](valuePointer *T) optional[T] {
+// NewOptionalFromPointer constructs an Optional from a pointer. If the pointer is non-nil, then the
+// Optional copies its value; otherwise the Optional has no value.
+func NewOptionalFromPointer[T any](valuePointer *T) Optional[T] {
 	if valuePointer == nil {
-		return optional[T]{}
+		return Optional[T]{}
+	}
+	return Optional[T]{defined: true, value: *valuePointer}
+}
+
+// MapOptional transforms the value of an Optional with the specified function. If the Optional has no
+// value, the function is not called and the result has no value.
+//
+//	ldvalue.MapOptional(ldvalue.NewOptional(2), strconv.Itoa) // equal to ldvalue.NewOptional("2")
+func MapOptional[T, U any](o Optional[T], fn func(T) U) Optional[U] {
+	if !o.defined {
+		return Optional[U]{}
 	}
-	return optional[T]{defined: true, value: *valuePointer}
+	return NewOptional(fn(o.value))
 }
 
-func (o optional[T]) isDefined() bool {
+// FlatMapOptional is the same as [MapOptional], except that the function returns an Optional, so it
+// can also decide that there is no value.
+//
+//	parseIfPossible := func(s string) ldvalue.Optional[int] {
+//		if n, err := strconv.Atoi(s); err == nil {
+//			return ldvalue.NewOptional(n)
+//		}
+//		return ldvalue.Optional[int]{}
+//	}
+//	ldvalue.FlatMapOptional(ldvalue.NewOptional("x"), parseIfPossible) // has no value
+func FlatMapOptional[T, U any](o Optional[T], fn func(T) Optional[U]) Optional[U] {
+	if !o.defined {
+		return Optional[U]{}
+	}
+	return fn(o.value)
+}
+
+// IsDefined returns true if the Optional contains a value, or false if it has no value.
+func (o Optional[T]) IsDefined() bool {
 	return o.defined
 }
 
-func (o optional[T]) getOrZeroValue() T {
+// Value returns the Optional's value, or the zero value of T if it has no value.
+func (o Optional[T]) Value() T {
 	return o.value
 }
 
-func (o optional[T]) getOrElse(defaultValue T) T {
+// Get is a combination of Value and IsDefined. If the Optional contains a value, it returns that value
+// and true; otherwise it returns the zero value of T and false.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.defined
+}
+
+// OrElse returns the Optional's value if it has one, or else the specified fallback value.
+func (o Optional[T]) OrElse(valueIfEmpty T) T {
 	if o.defined {
 		return o.value
 	}
-	return defaultValue
-}
-
-func (o optional[T]) get() (T, bool) {
-	return o.value, o.defined
+	return valueIfEmpty
 }
 
-func (o optional[T]) getAsPointer() *T {
+// AsPointer returns the Optional's value as a pointer if it has a value, or nil otherwise.
+//
+// The value, if any, is copied rather than returning a pointer to the internal field.
+func (o Optional[T]) AsPointer() *T {
 	if !o.defined {
 		return nil
 	}
 	return &o.value
 }
+
+// Filter returns the same Optional if it has a value for which the specified function returns true.
+// Otherwise it returns an Optional with no value. The function is not called if there is no value.
+func (o Optional[T]) Filter(fn func(T) bool) Optional[T] {
+	if o.defined && fn(o.value) {
+		return o
+	}
+	return Optional[T]{}
+}
+
+// AsValue converts the Optional to a [Value]. If there is no value, the result is [Null](). Otherwise,
+// the value is converted in the same way as [CopyArbitraryValue]; if it cannot be represented in JSON,
+// the result is also [Null]().
+func (o Optional[T]) AsValue() Value {
+	if !o.defined {
+		return Null()
+	}
+	return CopyArbitraryValue(o.value)
+}
@@ -0,0 +1,109 @@
+package ldvalue
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyOptional(t *testing.T) {
+	o := Optional[float64]{}
+	assert.False(t, o.IsDefined())
+	assert.Equal(t, float64(0), o.Value())
+
+	n, ok := o.Get()
+	assert.Equal(t, float64(0), n)
+	assert.False(t, ok)
+
+	assert.Equal(t, 2.5, o.OrElse(2.5))
+	assert.Nil(t, o.AsPointer())
+	assert.Equal(t, Null(), o.AsValue())
+	assert.True(t, o == o)
+}
+
+func TestOptionalWithValue(t *testing.T) {
+	o := NewOptional(1.5)
+	assert.True(t, o.IsDefined())
+	assert.Equal(t, 1.5, o.Value())
+
+	n, ok := o.Get()
+	assert.Equal(t, 1.5, n)
+	assert.True(t, ok)
+
+	assert.Equal(t, 1.5, o.OrElse(2.5))
+	assert.NotNil(t, o.AsPointer())
+	assert.Equal(t, 1.5, *o.AsPointer())
+	assert.Equal(t, Float64(1.5), o.AsValue())
+	assert.True(t, o == o)
+	assert.False(t, o == Optional[float64]{})
+}
+
+func TestOptionalFromNilPointer(t *testing.T) {
+	o := NewOptionalFromPointer[int64](nil)
+	assert.True(t, o == Optional[int64]{})
+}
+
+func TestOptionalFromNonNilPointer(t *testing.T) {
+	v := int64(3)
+	p := &v
+	o := NewOptionalFromPointer(p)
+	assert.True(t, o == NewOptional(int64(3)))
+
+	assert.Equal(t, int64(3), *o.AsPointer())
+	assert.False(t, p == o.AsPointer()) // should not be the same pointer, just the same underlying value
+}
+
+func TestMapOptional(t *testing.T) {
+	assert.Equal(t, NewOptional("2"), MapOptional(NewOptional(2), strconv.Itoa))
+	assert.Equal(t, Optional[string]{}, MapOptional(Optional[int]{}, func(int) string {
+		assert.Fail(t, "function should not have been called")
+		return ""
+	}))
+}
+
+func TestFlatMapOptional(t *testing.T) {
+	parseIfPossible := func(s string) Optional[int] {
+		if n, err := strconv.Atoi(s); err == nil {
+			return NewOptional(n)
+		}
+		return Optional[int]{}
+	}
+	assert.Equal(t, NewOptional(2), FlatMapOptional(NewOptional("2"), parseIfPossible))
+	assert.Equal(t, Optional[int]{}, FlatMapOptional(NewOptional("x"), parseIfPossible))
+	assert.Equal(t, Optional[int]{}, FlatMapOptional(Optional[string]{}, parseIfPossible))
+}
+
+func TestOptionalFilter(t *testing.T) {
+	isPositive := func(n int64) bool { return n > 0 }
+	assert.Equal(t, NewOptional(int64(1)), NewOptional(int64(1)).Filter(isPositive))
+	assert.Equal(t, Optional[int64]{}, NewOptional(int64(-1)).Filter(isPositive))
+	assert.Equal(t, Optional[int64]{}, Optional[int64]{}.Filter(isPositive))
+}
+
+func TestOptionalAsValue(t *testing.T) {
+	assert.Equal(t, Int64(9007199254740993), NewOptional(int64(9007199254740993)).AsValue())
+	assert.Equal(t, String("x"), NewOptional("x").AsValue())
+	assert.Equal(t, ArrayOf(Int(1)), NewOptional(ArrayOf(Int(1))).AsValue())
+	assert.Equal(t, String("2020-01-02T03:04:05Z"),
+		NewOptional(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)).AsValue())
+	assert.Equal(t, Null(), NewOptional(make(chan int)).AsValue())
+}
+
+func TestOptionalInteroperatesWithConcreteTypes(t *testing.T) {
+	assert.Equal(t, NewOptional(true), NewOptionalBool(true).AsOptional())
+	assert.Equal(t, Optional[bool]{}, OptionalBool{}.AsOptional())
+	assert.Equal(t, NewOptionalBool(true), NewOptionalBoolFromOptional(NewOptional(true)))
+	assert.Equal(t, OptionalBool{}, NewOptionalBoolFromOptional(Optional[bool]{}))
+
+	assert.Equal(t, NewOptional(3), NewOptionalInt(3).AsOptional())
+	assert.Equal(t, Optional[int]{}, OptionalInt{}.AsOptional())
+	assert.Equal(t, NewOptionalInt(3), NewOptionalIntFromOptional(NewOptional(3)))
+	assert.Equal(t, OptionalInt{}, NewOptionalIntFromOptional(Optional[int]{}))
+
+	assert.Equal(t, NewOptional(""), NewOptionalString("").AsOptional())
+	assert.Equal(t, Optional[string]{}, OptionalString{}.AsOptional())
+	assert.Equal(t, NewOptionalString(""), NewOptionalStringFromOptional(NewOptional("")))
+	assert.Equal(t, OptionalString{}, NewOptionalStringFromOptional(Optional[string]{}))
+}
@@ -173,3 +173,130 @@ func (o OptionalInt) WriteToJSONWriter(w *jwriter.Writer) {
 func (o OptionalString) WriteToJSONWriter(w *jwriter.Writer) {
 	o.AsValue().WriteToJSONWriter(w)
 }
+
+// JSONString returns the JSON representation of the value as a string. This is
+// guaranteed to be logically equivalent to calling [json.Marshal] and converting the
+// first return value to a string.
+//
+// Unlike the concrete optional types, an Optional can contain a value that cannot be converted
+// to JSON; in that case, the result is "null".
+func (o Optional[T]) JSONString() string {
+	data, err := o.MarshalJSON()
+	if err != nil {
+		return nullAsJSON
+	}
+	return string(data)
+}
+
+// MarshalJSON converts the Optional to its JSON representation.
+//
+// The output will be either null, or the same JSON representation that the value itself would
+// have. Note that the "omitempty" tag for a struct field will not cause an empty Optional field to
+// be omitted; it will be output as null. If you want to completely omit a JSON property when there
+// is no value, it must be a pointer instead of an Optional; use [Optional.AsPointer] to get a pointer.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return jwriter.MarshalJSONWithWriter(o)
+}
+
+// UnmarshalJSON parses an Optional from JSON.
+//
+// The input must be either null, or a JSON representation that can be unmarshaled into a value of
+// type T.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var value T
+	switch any(&value).(type) {
+	case *bool, *int, *float64, *string:
+		return jreader.UnmarshalJSONWithReader(data, o)
+	}
+	if bytes.Equal(data, nullAsJSONBytes) {
+		*o = Optional[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*o = NewOptional(value)
+	return nil
+}
+
+// ReadFromJSONReader provides JSON deserialization for use with the jsonstream API.
+//
+// If T is bool, int, float64, string, or [Value], or if *T implements jreader.Readable, this does not
+// use reflection. See [github.com/launchdarkly/go-jsonstream/v3] for more details.
+//
+// For other types, the JSON value is first read as a [Value], so it is subject to the limitations of
+// the jsonstream API: for instance, integers that cannot be exactly represented as float64 lose
+// precision. [Optional.UnmarshalJSON] does not have this limitation.
+func (o *Optional[T]) ReadFromJSONReader(r *jreader.Reader) {
+	var value T
+	var nonNull bool
+	switch p := any(&value).(type) {
+	case *bool:
+		*p, nonNull = r.BoolOrNull()
+	case *int:
+		*p, nonNull = r.IntOrNull()
+	case *float64:
+		*p, nonNull = r.Float64OrNull()
+	case *string:
+		*p, nonNull = r.StringOrNull()
+	default:
+		var v Value
+		v.ReadFromJSONReader(r)
+		nonNull = !v.IsNull()
+		if nonNull && r.Error() == nil {
+			r.AddError(unmarshalFromValue(v, p))
+		}
+	}
+	if r.Error() == nil {
+		if nonNull {
+			*o = NewOptional(value)
+		} else {
+			*o = Optional[T]{}
+		}
+	}
+}
+
+// unmarshalFromValue is the fallback used by Optional[T] for types that do not have a specific
+// JSON reader method.
+func unmarshalFromValue(v Value, target any) error {
+	switch p := target.(type) {
+	case *Value:
+		*p = v
+		return nil
+	case jreader.Readable:
+		return jreader.UnmarshalJSONWithReader([]byte(v.JSONString()), p)
+	default:
+		return json.Unmarshal([]byte(v.JSONString()), p)
+	}
+}
+
+// WriteToJSONWriter provides JSON serialization for use with the jsonstream API.
+//
+// If T is bool, int, float64, or string, or if T implements jwriter.Writable, this does not use
+// reflection. See [github.com/launchdarkly/go-jsonstream/v3] for more details.
+func (o Optional[T]) WriteToJSONWriter(w *jwriter.Writer) {
+	value, ok := o.Get()
+	if !ok {
+		w.Null()
+		return
+	}
+	switch v := any(value).(type) {
+	case bool:
+		w.Bool(v)
+	case int:
+		w.Int(v)
+	case float64:
+		w.Float64(v)
+	case string:
+		w.String(v)
+	case jwriter.Writable:
+		v.WriteToJSONWriter(w)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			w.AddError(err)
+			return
+		}
+		w.Raw(data)
+	}
+}
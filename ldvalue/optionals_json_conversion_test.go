@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/launchdarkly/go-jsonstream/v3/jreader"
 	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
@@ -23,6 +24,16 @@ type structWithOptionalInts struct {
 	N3 OptionalInt `json:"n3"`
 }
 
+type structWithGenericOptionals struct {
+	F Optional[float64]   `json:"f"`
+	T Optional[time.Time] `json:"t"`
+	V Optional[Value]     `json:"v"`
+}
+
+type optionalTestStruct struct {
+	A int `json:"a"`
+}
+
 type structWithOptionalStrings struct {
 	S1 OptionalString `json:"s1"`
 	S2 OptionalString `json:"s2"`
@@ -238,3 +249,93 @@ func TestOptionalsJSONString(t *testing.T) {
 		})
 	}
 }
+
+func testOptionalJSONConversion[T any](t *testing.T, value T, expectedJSON string) {
+	t.Run(expectedJSON, func(t *testing.T) {
+		for _, o := range []Optional[T]{{}, NewOptional(value)} {
+			expected := expectedJSON
+			if !o.IsDefined() {
+				expected = nullAsJSON
+			}
+
+			bytes, err := json.Marshal(o)
+			assert.NoError(t, err)
+			assert.JSONEq(t, expected, string(bytes))
+			assert.Equal(t, string(bytes), o.JSONString())
+
+			w := jwriter.NewWriter()
+			o.WriteToJSONWriter(&w)
+			assert.NoError(t, w.Error())
+			assert.JSONEq(t, expected, string(w.Bytes()))
+
+			var o1 Optional[T]
+			assert.NoError(t, json.Unmarshal([]byte(expected), &o1))
+			assert.Equal(t, o, o1)
+
+			o2 := NewOptional(value) // verify that null replaces any previous value
+			r := jreader.NewReader([]byte(expected))
+			o2.ReadFromJSONReader(&r)
+			assert.NoError(t, r.Error())
+			assert.Equal(t, o, o2)
+		}
+	})
+}
+
+func TestOptionalJSONConversion(t *testing.T) {
+	testOptionalJSONConversion(t, true, `true`)
+	testOptionalJSONConversion(t, 3, `3`)
+	testOptionalJSONConversion(t, 1.5, `1.5`)
+	testOptionalJSONConversion(t, `a "good" string`, `"a \"good\" string"`)
+	testOptionalJSONConversion(t, int64(-9007199254740991), `-9007199254740991`)
+	testOptionalJSONConversion(t, uint(3), `3`)
+	testOptionalJSONConversion(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), `"2020-01-02T03:04:05Z"`)
+	testOptionalJSONConversion(t, optionalTestStruct{A: 1}, `{"a":1}`)
+	testOptionalJSONConversion(t, ObjectBuild().Set("a", ArrayOf(Int(1))).Build(), `{"a":[1]}`)
+	testOptionalJSONConversion(t, []string{"a", "b"}, `["a","b"]`)
+}
+
+func TestOptionalJSONUnmarshallingPreservesLargeIntegers(t *testing.T) {
+	var o1 Optional[int64]
+	assert.NoError(t, json.Unmarshal([]byte(`9007199254740993`), &o1))
+	assert.Equal(t, NewOptional(int64(9007199254740993)), o1)
+
+	var o2 Optional[Value]
+	assert.NoError(t, json.Unmarshal([]byte(`9007199254740993`), &o2))
+	assert.Equal(t, "9007199254740993", o2.Value().JSONString())
+}
+
+func TestOptionalJSONConversionInStruct(t *testing.T) {
+	var s structWithGenericOptionals
+	err := json.Unmarshal([]byte(`{"f":1.5,"t":null}`), &s)
+	assert.NoError(t, err)
+	assert.Equal(t, structWithGenericOptionals{F: NewOptional(1.5)}, s)
+
+	bytes, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"f":1.5,"t":null,"v":null}`, string(bytes))
+}
+
+func TestOptionalJSONUnmarshallingErrors(t *testing.T) {
+	var o1 Optional[int]
+	err := json.Unmarshal([]byte(`true`), &o1)
+	assert.IsType(t, &json.UnmarshalTypeError{}, err)
+
+	err = json.Unmarshal([]byte(`x`), &o1)
+	assert.IsType(t, &json.SyntaxError{}, err)
+
+	var o2 Optional[int64]
+	err = json.Unmarshal([]byte(`"x"`), &o2)
+	assert.IsType(t, &json.UnmarshalTypeError{}, err)
+	assert.Equal(t, Optional[int64]{}, o2)
+
+	var o3 Optional[time.Time]
+	assert.Error(t, json.Unmarshal([]byte(`3`), &o3))
+	assert.Equal(t, Optional[time.Time]{}, o3)
+}
+
+func TestOptionalJSONMarshallingError(t *testing.T) {
+	o := NewOptional(make(chan int))
+	_, err := json.Marshal(o)
+	assert.Error(t, err)
+	assert.Equal(t, nullAsJSON, o.JSONString())
+}
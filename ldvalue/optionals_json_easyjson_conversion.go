@@ -3,6 +3,9 @@
 package ldvalue
 
 import (
+	"encoding/json"
+
+	"github.com/mailru/easyjson"
 	"github.com/mailru/easyjson/jlexer"
 	ej_jwriter "github.com/mailru/easyjson/jwriter"
 )
@@ -12,7 +15,7 @@ import (
 // This method is only defined if the launchdarkly_easyjson build tag is set. For more information,
 // see the package documentation for ldvalue.
 func (v OptionalBool) MarshalEasyJSON(writer *ej_jwriter.Writer) {
-	if value, ok := v.optValue.get(); ok {
+	if value, ok := v.optValue.Get(); ok {
 		writer.Bool(value)
 	} else {
 		writer.Raw(nullAsJSONBytes, nil)
@@ -24,7 +27,7 @@ func (v OptionalBool) MarshalEasyJSON(writer *ej_jwriter.Writer) {
 // This method is only defined if the launchdarkly_easyjson build tag is set. For more information,
 // see the package documentation for ldvalue.
 func (v OptionalInt) MarshalEasyJSON(writer *ej_jwriter.Writer) {
-	if value, ok := v.optValue.get(); ok {
+	if value, ok := v.optValue.Get(); ok {
 		writer.Int(value)
 	} else {
 		writer.Raw(nullAsJSONBytes, nil)
@@ -36,7 +39,7 @@ func (v OptionalInt) MarshalEasyJSON(writer *ej_jwriter.Writer) {
 // This method is only defined if the launchdarkly_easyjson build tag is set. For more information,
 // see the package documentation for ldvalue.
 func (v OptionalString) MarshalEasyJSON(writer *ej_jwriter.Writer) {
-	if value, ok := v.optValue.get(); ok {
+	if value, ok := v.optValue.Get(); ok {
 		writer.String(value)
 	} else {
 		writer.Raw(nullAsJSONBytes, nil)
@@ -81,3 +84,57 @@ func (v *OptionalString) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
 	}
 	*v = NewOptionalString(lexer.String())
 }
+
+// MarshalEasyJSON implements the easyjson.Marshaler interface.
+//
+// This method is only defined if the launchdarkly_easyjson build tag is set. For more information,
+// see the package documentation for ldvalue.
+func (v Optional[T]) MarshalEasyJSON(writer *ej_jwriter.Writer) {
+	value, ok := v.Get()
+	if !ok {
+		writer.Raw(nullAsJSONBytes, nil)
+		return
+	}
+	switch x := any(value).(type) {
+	case bool:
+		writer.Bool(x)
+	case int:
+		writer.Int(x)
+	case float64:
+		writer.Float64(x)
+	case string:
+		writer.String(x)
+	case easyjson.Marshaler:
+		x.MarshalEasyJSON(writer)
+	default:
+		writer.Raw(json.Marshal(x))
+	}
+}
+
+// UnmarshalEasyJSON implements the easyjson.Unmarshaler interface.
+//
+// This method is only defined if the launchdarkly_easyjson build tag is set. For more information,
+// see the package documentation for ldvalue.
+func (v *Optional[T]) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
+	if lexer.IsNull() {
+		lexer.Null()
+		*v = Optional[T]{}
+		return
+	}
+	var value T
+	switch p := any(&value).(type) {
+	case *bool:
+		*p = lexer.Bool()
+	case *int:
+		*p = lexer.Int()
+	case *float64:
+		*p = lexer.Float64()
+	case *string:
+		*p = lexer.String()
+	case easyjson.Unmarshaler:
+		p.UnmarshalEasyJSON(lexer)
+	default:
+		lexer.AddError(json.Unmarshal(lexer.Raw(), p))
+	}
+	*v = NewOptional(value)
+}
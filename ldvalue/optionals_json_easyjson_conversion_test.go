@@ -4,6 +4,7 @@ package ldvalue
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mailru/easyjson"
 	"github.com/stretchr/testify/assert"
@@ -95,3 +96,45 @@ func TestOptionalStringEasyJSONUnmarshalling(t *testing.T) {
 	err = easyjson.Unmarshal([]byte("3"), &o)
 	assert.Error(t, err)
 }
+
+func testOptionalEasyJSONConversion[T any](t *testing.T, value T, expectedJSON string) {
+	t.Run(expectedJSON, func(t *testing.T) {
+		for _, o := range []Optional[T]{{}, NewOptional(value)} {
+			expected := expectedJSON
+			if !o.IsDefined() {
+				expected = nullAsJSON
+			}
+
+			bytes, err := easyjson.Marshal(o)
+			assert.NoError(t, err)
+			assert.JSONEq(t, expected, string(bytes))
+
+			o1 := NewOptional(value) // verify that null replaces any previous value
+			assert.NoError(t, easyjson.Unmarshal([]byte(expected), &o1))
+			assert.Equal(t, o, o1)
+		}
+	})
+}
+
+func TestOptionalEasyJSONConversion(t *testing.T) {
+	testOptionalEasyJSONConversion(t, true, `true`)
+	testOptionalEasyJSONConversion(t, 3, `3`)
+	testOptionalEasyJSONConversion(t, 1.5, `1.5`)
+	testOptionalEasyJSONConversion(t, `a "good" string`, `"a \"good\" string"`)
+	testOptionalEasyJSONConversion(t, int64(9007199254740993), `9007199254740993`)
+	testOptionalEasyJSONConversion(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), `"2020-01-02T03:04:05Z"`)
+	testOptionalEasyJSONConversion(t, optionalTestStruct{A: 1}, `{"a":1}`)
+	testOptionalEasyJSONConversion(t, ObjectBuild().Set("a", ArrayOf(Int(1))).Build(), `{"a":[1]}`)
+}
+
+func TestOptionalEasyJSONUnmarshallingErrors(t *testing.T) {
+	var o1 Optional[int]
+	assert.Error(t, easyjson.Unmarshal([]byte(`true`), &o1))
+	assert.Error(t, easyjson.Unmarshal([]byte(`x`), &o1))
+
+	var o2 Optional[time.Time]
+	assert.Error(t, easyjson.Unmarshal([]byte(`3`), &o2))
+
+	_, err := easyjson.Marshal(NewOptional(make(chan int)))
+	assert.Error(t, err)
+}
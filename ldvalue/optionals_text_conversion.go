@@ -1,6 +1,11 @@
 package ldvalue
 
-import "strconv"
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
 
 // Note: the implementations of encoding.TextMarshaler and encoding.TextUnmarshaler are provided
 // for convenience for use with general-purpose parsing/configuration tools. LaunchDarkly SDK code
@@ -12,7 +17,7 @@ import "strconv"
 // However, the specific representation format is subject to change and should not be relied on in
 // code; it is intended for convenience in logging or debugging.
 func (o OptionalBool) String() string {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		if value {
 			return trueString
 		}
@@ -27,7 +32,7 @@ func (o OptionalBool) String() string {
 // However, the specific representation format is subject to change and should not be relied on in
 // code; it is intended for convenience in logging or debugging.
 func (o OptionalInt) String() string {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		return strconv.Itoa(value)
 	}
 	return noneDescription
@@ -46,7 +51,7 @@ func (o OptionalInt) String() string {
 //	fmt.Printf("it is '%s'", s) // prints "it is '[none]'"
 //	fmt.Printf("it is '%s'", s.StringValue()) // prints "it is ''"
 func (o OptionalString) String() string {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		if value == "" {
 			return "[empty]"
 		}
@@ -62,7 +67,7 @@ func (o OptionalString) String() string {
 // The behavior for OptionalBool is that a true or false value produces the string "true" or
 // "false", and an undefined value produces an empty string.
 func (o OptionalBool) MarshalText() ([]byte, error) {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		if value {
 			return trueBytes, nil
 		}
@@ -78,7 +83,7 @@ func (o OptionalBool) MarshalText() ([]byte, error) {
 // The behavior for OptionalBool is that a true or false value produces a decimal numeric
 // string, and an undefined value produces an empty string.
 func (o OptionalInt) MarshalText() ([]byte, error) {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		return []byte(strconv.Itoa(value)), nil
 	}
 	return []byte(""), nil
@@ -91,7 +96,7 @@ func (o OptionalInt) MarshalText() ([]byte, error) {
 // The behavior for OptionalString is that a defined string value produces the same string,
 // and an undefined value produces nil.
 func (o OptionalString) MarshalText() ([]byte, error) {
-	if value, ok := o.optValue.get(); ok {
+	if value, ok := o.optValue.Get(); ok {
 		return []byte(value), nil
 	}
 	return nil, nil
@@ -151,3 +156,80 @@ func (o *OptionalString) UnmarshalText(text []byte) error {
 	}
 	return nil
 }
+
+// String returns a human-readable string representation of the value.
+//
+// Currently, this is defined as being either the result of formatting the value with fmt.Sprint, or
+// "[none]" if it has no value. However, the specific representation format is subject to change and
+// should not be relied on in code; it is intended for convenience in logging or debugging.
+func (o Optional[T]) String() string {
+	if value, ok := o.Get(); ok {
+		return fmt.Sprint(value)
+	}
+	return noneDescription
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+//
+// This may be useful with packages that support describing arbitrary types via that interface.
+//
+// The behavior for Optional is the same as for the corresponding concrete type, such as [OptionalInt]:
+// an undefined value produces an empty string, except that if T is string, it produces nil as for
+// [OptionalString] so that [Optional.UnmarshalText] can distinguish it from an empty string. A string
+// value produces the same string; a value whose type implements [encoding.TextMarshaler] produces the result of its
+// MarshalText method; and any other value produces its JSON representation, so for instance a bool
+// value produces "true" or "false".
+func (o Optional[T]) MarshalText() ([]byte, error) {
+	value, ok := o.Get()
+	if !ok {
+		if _, isString := any(value).(string); isString {
+			return nil, nil
+		}
+		return []byte(""), nil
+	}
+	switch v := any(value).(type) {
+	case string:
+		return []byte(v), nil
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+//
+// This may be useful with packages that support parsing arbitrary types via that interface,
+// such as gcfg.
+//
+// This is the reverse of [Optional.MarshalText]. If T is string, a nil byte slice produces an empty
+// Optional[T]{} and any other input produces a string value, as for [OptionalString]. For all other
+// types, a nil or empty byte slice produces an empty Optional[T]{}; otherwise, the input is parsed
+// with the value's UnmarshalText method if *T implements [encoding.TextUnmarshaler], or else as JSON.
+func (o *Optional[T]) UnmarshalText(text []byte) error {
+	var value T
+	switch p := any(&value).(type) {
+	case *string:
+		if text == nil {
+			*o = Optional[T]{}
+			return nil
+		}
+		*p = string(text)
+	default:
+		if len(text) == 0 {
+			*o = Optional[T]{}
+			return nil
+		}
+		var err error
+		if u, ok := p.(encoding.TextUnmarshaler); ok {
+			err = u.UnmarshalText(text)
+		} else {
+			err = json.Unmarshal(text, p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	*o = NewOptional(value)
+	return nil
+}
@@ -1,7 +1,9 @@
 package ldvalue
 
 import (
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -114,3 +116,85 @@ func TestOptionalStringTextUnmarshalling(t *testing.T) {
 	assert.NoError(t, o3.UnmarshalText(nil))
 	assert.Equal(t, OptionalString{}, o3)
 }
+
+func TestOptionalAsStringer(t *testing.T) {
+	assert.Equal(t, "[none]", Optional[float64]{}.String())
+	assert.Equal(t, "1.5", NewOptional(1.5).String())
+	assert.Equal(t, "", NewOptional("").String())
+	assert.Equal(t, "[1 2]", NewOptional([]int{1, 2}).String())
+}
+
+func TestOptionalTextMarshalling(t *testing.T) {
+	for _, p := range []struct {
+		name     string
+		marshal  func() ([]byte, error)
+		expected []byte
+	}{
+		{"empty", Optional[int64]{}.MarshalText, []byte("")},
+		{"empty with string type", Optional[string]{}.MarshalText, nil},
+		{"bool", NewOptional(true).MarshalText, []byte("true")},
+		{"int64", NewOptional(int64(-3)).MarshalText, []byte("-3")},
+		{"float64", NewOptional(1.5).MarshalText, []byte("1.5")},
+		{"string", NewOptional(`a "b"`).MarshalText, []byte(`a "b"`)},
+		{"empty string", NewOptional("").MarshalText, []byte{}},
+		{"TextMarshaler", NewOptional(net.IPv4(127, 0, 0, 1)).MarshalText, []byte("127.0.0.1")},
+		{"other", NewOptional([]int{1, 2}).MarshalText, []byte("[1,2]")},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			b, e := p.marshal()
+			assert.NoError(t, e)
+			assert.Equal(t, p.expected, b)
+			assert.Equal(t, p.expected == nil, b == nil)
+		})
+	}
+}
+
+func TestOptionalTextMarshallingIsSameAsConcreteOptionals(t *testing.T) {
+	for _, p := range []struct {
+		name              string
+		generic, concrete func() ([]byte, error)
+	}{
+		{"empty bool", Optional[bool]{}.MarshalText, OptionalBool{}.MarshalText},
+		{"bool", NewOptional(true).MarshalText, NewOptionalBool(true).MarshalText},
+		{"empty int", Optional[int]{}.MarshalText, OptionalInt{}.MarshalText},
+		{"int", NewOptional(-3).MarshalText, NewOptionalInt(-3).MarshalText},
+		{"empty string", Optional[string]{}.MarshalText, OptionalString{}.MarshalText},
+		{"string", NewOptional("").MarshalText, NewOptionalString("").MarshalText},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			expected, err := p.concrete()
+			assert.NoError(t, err)
+			b, err := p.generic()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, b)
+			assert.Equal(t, expected == nil, b == nil) // assert.Equal does not distinguish nil from empty
+		})
+	}
+}
+
+func TestOptionalTextUnmarshalling(t *testing.T) {
+	var o1 Optional[float64]
+	assert.NoError(t, o1.UnmarshalText([]byte("1.5")))
+	assert.Equal(t, NewOptional(1.5), o1)
+	assert.NoError(t, o1.UnmarshalText([]byte("")))
+	assert.Equal(t, Optional[float64]{}, o1)
+	assert.NoError(t, o1.UnmarshalText(nil))
+	assert.Equal(t, Optional[float64]{}, o1)
+	assert.Error(t, o1.UnmarshalText([]byte("x")))
+
+	var o2 Optional[bool]
+	assert.NoError(t, o2.UnmarshalText([]byte("false")))
+	assert.Equal(t, NewOptional(false), o2)
+	assert.Error(t, o2.UnmarshalText([]byte("1")))
+
+	var o3 Optional[string]
+	assert.NoError(t, o3.UnmarshalText([]byte("")))
+	assert.Equal(t, NewOptional(""), o3)
+	assert.NoError(t, o3.UnmarshalText(nil))
+	assert.Equal(t, Optional[string]{}, o3)
+
+	var o4 Optional[time.Time]
+	assert.NoError(t, o4.UnmarshalText([]byte("2020-01-02T03:04:05Z")))
+	assert.Equal(t, NewOptional(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)), o4)
+	assert.Error(t, o4.UnmarshalText([]byte("x")))
+}
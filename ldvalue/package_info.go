@@ -4,7 +4,7 @@
 //
 // This package also provides several helper types:
 //   - [OptionalBool], [OptionalInt], and [OptionalString], which are safer alternatives to using
-//     pointers for values.
+//     pointers for values; and the generic [Optional], for values of any other type.
 //   - [ValueArray] and [ValueMap], which provide immutable representations of JSON arrays and objects.
 //
 // All value types in this package support several kinds of marshaling/unmarshaling, as follows:
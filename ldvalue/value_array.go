@@ -19,8 +19,14 @@ var emptyArray = []Value{} //nolint:gochecknoglobals
 // Like a Go slice, there is a distinction between an array in a nil state-- which is the zero
 // value of ValueArray{}-- and a non-nil aray that is empty. The former is represented in JSON as a
 // null; the latter is an empty JSON array [].
+//
+// To create a copy of a ValueArray with more elements, you can either use [ValueArrayBuildFromArray],
+// which copies the whole array, or use [ValueArray.Append], which is more efficient if you will be
+// adding many elements one at a time to a large array.
 type ValueArray struct {
 	data []Value
+	// Used instead of data if the array was created by Append.
+	vec *persistentVector
 }
 
 // ValueArrayBuilder is a builder created by [ValueArrayBuild], for creating immutable JSON arrays.
@@ -52,8 +58,8 @@ func (b *ValueArrayBuilder) Add(value Value) *ValueArrayBuilder {
 
 // AddAllFromValueArray appends all elements from an existing ValueArray.
 func (b *ValueArrayBuilder) AddAllFromValueArray(a ValueArray) *ValueArrayBuilder {
-	for _, v := range a.data {
-		b.Add(v)
+	for i := 0; i < a.Count(); i++ {
+		b.Add(a.Get(i))
 	}
 	return b
 }
@@ -65,10 +71,10 @@ func (b *ValueArrayBuilder) Build() ValueArray {
 		return ValueArray{}
 	}
 	if b.output == nil {
-		return ValueArray{emptyArray}
+		return ValueArray{data: emptyArray}
 	}
 	b.copyOnWrite = true
-	return ValueArray{b.output}
+	return ValueArray{data: b.output}
 }
 
 // ValueArrayBuild creates a builder for constructing an immutable [ValueArray].
@@ -94,7 +100,7 @@ func ValueArrayBuildWithCapacity(capacity int) *ValueArrayBuilder {
 // The builder has copy-on-write behavior, so if you make no changes before calling Build(), the
 // original array is used as-is.
 func ValueArrayBuildFromArray(a ValueArray) *ValueArrayBuilder {
-	return &ValueArrayBuilder{output: a.data, copyOnWrite: true}
+	return &ValueArrayBuilder{output: a.plainData(), copyOnWrite: true}
 }
 
 // ValueArrayOf creates a ValueArray from a list of [Value]s.
@@ -106,7 +112,7 @@ func ValueArrayOf(items ...Value) ValueArray {
 	// ValueArrayOf() with no parameters will pass nil rather than a zero-length slice; logically we
 	// still want it to create a non-nil array.
 	if items == nil {
-		return ValueArray{emptyArray}
+		return ValueArray{data: emptyArray}
 	}
 	return CopyValueArray(items)
 }
@@ -119,7 +125,7 @@ func CopyValueArray(data []Value) ValueArray {
 		return ValueArray{}
 	}
 	if len(data) == 0 {
-		return ValueArray{emptyArray}
+		return ValueArray{data: emptyArray}
 	}
 	return ValueArray{data: slices.Clone(data)}
 }
@@ -141,47 +147,85 @@ func CopyArbitraryValueArray(data []any) ValueArray {
 
 // IsDefined returns true if the array is non-nil.
 func (a ValueArray) IsDefined() bool {
-	return a.data != nil
+	return a.data != nil || a.vec != nil
 }
 
 // Count returns the number of elements in the array. For an uninitialized ValueArray{}, this is zero.
 func (a ValueArray) Count() int {
+	if a.vec != nil {
+		return a.vec.count
+	}
 	return len(a.data)
 }
 
 // AsValue converts the ValueArray to a Value which is either [Null]() or an array. This does not
 // cause any new allocations.
 func (a ValueArray) AsValue() Value {
-	if a.data == nil {
+	if !a.IsDefined() {
 		return Null()
 	}
-	return Value{valueType: ArrayType, arrayValue: a}
+	return newArrayValue(a)
 }
 
 // Get gets a value from the array by index.
 //
 // If the index is out of range, it returns [Null]().
 func (a ValueArray) Get(index int) Value {
-	if index < 0 || index >= len(a.data) {
-		return Null()
-	}
-	return a.data[index]
+	ret, _ := a.TryGet(index)
+	return ret
 }
 
 // TryGet gets a value from the map by index, with a second return value of true if successful.
 //
 // If the index is out of range, it returns ([Null](), false).
 func (a ValueArray) TryGet(index int) (Value, bool) {
-	if index < 0 || index >= len(a.data) {
+	if index < 0 || index >= a.Count() {
 		return Null(), false
 	}
+	if a.vec != nil {
+		return a.vec.get(index), true
+	}
 	return a.data[index], true
 }
 
+// Append returns a ValueArray that is the same as this one, except that the specified value is
+// added at the end. The original ValueArray is unchanged. If this ValueArray is uninitialized, the
+// result is an array containing only that value.
+//
+// Unlike adding to a copy of the array with [ValueArrayBuildFromArray], this does not copy the whole
+// array: the result shares most of its data with the original, so each call takes a small, constant
+// amount of time on average regardless of the size of the array.
+//
+//	a1 := ldvalue.ValueArrayOf(ldvalue.Int(1))
+//	a2 := a1.Append(ldvalue.Int(2)) // a1 is still [1]; a2 is [1, 2]
+func (a ValueArray) Append(value Value) ValueArray {
+	vec := a.vec
+	if vec == nil {
+		vec = newPersistentVector(a.data)
+	}
+	return ValueArray{vec: vec.append(value)}
+}
+
+// plainData returns the wrapped slice if there is one, or else a new slice with the same contents.
+// The caller must not modify the result.
+func (a ValueArray) plainData() []Value {
+	if a.vec == nil {
+		return a.data
+	}
+	ret := make([]Value, a.vec.count)
+	for i := range ret {
+		ret[i] = a.vec.get(i)
+	}
+	return ret
+}
+
 // AsSlice returns a copy of the wrapped data as a simple Go slice whose values are of type [Value].
 //
 // For an uninitialized ValueArray{}, this returns nil.
 func (a ValueArray) AsSlice() []Value {
+	if a.vec != nil {
+		return a.plainData()
+	}
 	return slices.Clone(a.data)
 }
 
@@ -190,12 +234,16 @@ func (a ValueArray) AsSlice() []Value {
 //
 // For an uninitialized ValueArray{}, this returns nil.
 func (a ValueArray) AsArbitraryValueSlice() []any {
-	if a.data == nil {
+	return a.asArbitraryValueSlice(false)
+}
+
+func (a ValueArray) asArbitraryValueSlice(exactNumbers bool) []any {
+	if !a.IsDefined() {
 		return nil
 	}
-	ret := make([]any, len(a.data))
-	for i, v := range a.data {
-		ret[i] = v.AsArbitraryValue()
+	ret := make([]any, a.Count())
+	for i := range ret {
+		ret[i] = a.Get(i).asArbitraryValue(exactNumbers)
 	}
 	return ret
 }
@@ -206,7 +254,18 @@ func (a ValueArray) Equal(other ValueArray) bool {
 	if a.IsDefined() != other.IsDefined() {
 		return false
 	}
-	return slices.EqualFunc(a.data, other.data, Value.Equal)
+	if a.vec == nil && other.vec == nil {
+		return slices.EqualFunc(a.data, other.data, Value.Equal)
+	}
+	if a.Count() != other.Count() {
+		return false
+	}
+	for i := 0; i < a.Count(); i++ {
+		if !a.Get(i).Equal(other.Get(i)) {
+			return false
+		}
+	}
+	return true
 }
 
 // Transform applies a transformation function to a ValueArray, returning a new ValueArray.
@@ -218,26 +277,32 @@ func (a ValueArray) Equal(other ValueArray) bool {
 // Otherwise, fn is called for each value. It should return a transformed value and true, or else
 // return false for the second return value if the property should be dropped.
 func (a ValueArray) Transform(fn func(index int, value Value) (Value, bool)) ValueArray {
-	if len(a.data) == 0 {
+	if a.Count() == 0 {
 		return a
 	}
-	ret := a.data
+	var ret []Value
 	startedNewSlice := false
-	for i, v := range a.data {
+	for i := 0; i < a.Count(); i++ {
+		v := a.Get(i)
 		transformedValue, ok := fn(i, v)
 		modified := !ok || !transformedValue.Equal(v)
 		if modified && !startedNewSlice {
 			// This is the first change we've seen, so we should start building a new slice and
 			// retroactively add any values to it that already passed the test without changes.
 			startedNewSlice = true
-			ret = make([]Value, i, len(a.data))
-			copy(ret, a.data)
+			ret = make([]Value, i, a.Count())
+			for j := range ret {
+				ret[j] = a.Get(j)
+			}
 		}
 		if startedNewSlice && ok {
 			ret = append(ret, transformedValue)
 		}
 	}
-	return ValueArray{ret}
+	if !startedNewSlice {
+		return a
+	}
+	return ValueArray{data: ret}
 }
 
 // String converts the value to a string representation, equivalent to [ValueArray.JSONString].
@@ -255,7 +320,7 @@ func copyArbitraryArrayString(data []string) Value {
 		a[i] = String(v)
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayBool(data []bool) Value {
@@ -264,16 +329,16 @@ func copyArbitraryArrayBool(data []bool) Value {
 		a[i] = Bool(v)
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayInt(data []int) Value {
 	a := make([]Value, len(data))
 	for i, v := range data {
-		a[i] = Float64(float64(v))
+		a[i] = Int(v)
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayInt8(data []int8) Value {
@@ -282,7 +347,7 @@ func copyArbitraryArrayInt8(data []int8) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayInt16(data []int16) Value {
@@ -291,7 +356,7 @@ func copyArbitraryArrayInt16(data []int16) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayInt32(data []int32) Value {
@@ -300,25 +365,25 @@ func copyArbitraryArrayInt32(data []int32) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayInt64(data []int64) Value {
 	a := make([]Value, len(data))
 	for i, v := range data {
-		a[i] = Float64(float64(v))
+		a[i] = Int64(v)
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayUint(data []uint) Value {
 	a := make([]Value, len(data))
 	for i, v := range data {
-		a[i] = Float64(float64(v))
+		a[i] = Uint64(uint64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayUint8(data []uint8) Value {
@@ -327,7 +392,7 @@ func copyArbitraryArrayUint8(data []uint8) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayUint16(data []uint16) Value {
@@ -336,7 +401,7 @@ func copyArbitraryArrayUint16(data []uint16) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayUint32(data []uint32) Value {
@@ -345,16 +410,16 @@ func copyArbitraryArrayUint32(data []uint32) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayUint64(data []uint64) Value {
 	a := make([]Value, len(data))
 	for i, v := range data {
-		a[i] = Float64(float64(v))
+		a[i] = Uint64(v)
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayFloat32(data []float32) Value {
@@ -363,7 +428,7 @@ func copyArbitraryArrayFloat32(data []float32) Value {
 		a[i] = Float64(float64(v))
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
 
 func copyArbitraryArrayFloat64(data []float64) Value {
@@ -372,5 +437,5 @@ func copyArbitraryArrayFloat64(data []float64) Value {
 		a[i] = Float64(v)
 	}
 
-	return Value{valueType: ArrayType, arrayValue: ValueArray{data: a}}
+	return newArrayValue(ValueArray{data: a})
 }
@@ -2,6 +2,7 @@ package ldvalue
 
 import (
 	"encoding/json"
+	"math"
 
 	"golang.org/x/exp/slices"
 )
@@ -48,20 +49,30 @@ import (
 // # Comparisons
 //
 // You cannot compare Value instances with the == operator, because the struct may contain a slice. or a
-// map. Value has the [Value.Equal] method for this purpose; [reflect.DeepEqual] will also work.
+// map. Value has the [Value.Equal] method for this purpose; [reflect.DeepEqual] will also work, except
+// for arrays and objects that were created with [ValueArray.Append], [ValueMap.With], or
+// [ValueMap.Without], since those can have the same contents but a different internal structure.
 type Value struct {
-	valueType ValueType
+	// The ValueType, stored in a single byte so that it shares a word with the next two fields.
+	valueType uint8
 	// Used when the value is a boolean.
 	boolValue bool
-	// Used when the value is a number.
-	numberValue float64
-	// Used when the value is a string.
+	// Used when the value is a number, to indicate how numberBits is used.
+	numberKind numberKind
+	// Used when the value is a number, as described by numberKind.
+	numberBits uint64
+	// Used when the value is a string, or when it is a number that retains its original JSON text.
 	stringValue string
-	// Used when the value is an array, zero-valued otherwise.
-	arrayValue ValueArray
-	// Used when the value is an object, zero-valued otherwise.
-	objectValue ValueMap
-	rawValue    []byte
+	// Used when the value is an array, unless it was created by ValueArray.Append.
+	arrayData []Value
+	// Used when the value is unparsed JSON.
+	rawValue []byte
+	// Used when the value is an object (a map[string]Value, or a *persistentMap if it was created by
+	// ValueMap.With or ValueMap.Without), an array that was created by ValueArray.Append (a
+	// *persistentVector), or an unparsed JSON array or object that was created with LazyRaw (a
+	// *lazyNode). Sharing one field for all of these keeps Value from being any larger than it would
+	// be otherwise.
+	ref any
 }
 
 // ValueType indicates which JSON type is contained in a [Value].
@@ -91,30 +102,34 @@ func (t ValueType) String() string {
 
 // Null creates a null Value.
 func Null() Value {
-	return Value{valueType: NullType}
+	return Value{valueType: uint8(NullType)}
 }
 
 // Bool creates a boolean Value.
 func Bool(value bool) Value {
-	return Value{valueType: BoolType, boolValue: value}
+	return Value{valueType: uint8(BoolType), boolValue: value}
 }
 
 // Int creates a numeric Value from an integer.
 //
-// Note that all numbers are represented internally as the same type (float64), so Int(2) is
-// exactly equal to Float64(2).
+// Integers are stored exactly, even if they cannot be exactly represented as a float64. A float64
+// with an integral value is stored the same way, so Int(2) is exactly equal to Float64(2).
 func Int(value int) Value {
-	return Float64(float64(value))
+	return Int64(int64(value))
 }
 
 // Float64 creates a numeric Value from a float64.
+//
+// If the value is an integer within the range of int64 or uint64, the result is exactly the same
+// as [Int64] or [Uint64]; for instance, Float64(2) is equal to Int(2), and [Value.IsInt] returns
+// true for it.
 func Float64(value float64) Value {
-	return Value{valueType: NumberType, numberValue: value}
+	return float64Number(value)
 }
 
 // String creates a string Value.
 func String(value string) Value {
-	return Value{valueType: StringType, stringValue: value}
+	return Value{valueType: uint8(StringType), stringValue: value}
 }
 
 // Raw creates an unparsed JSON Value.
@@ -128,7 +143,8 @@ func String(value string) Value {
 // value, or evaluating a feature flag that references the value in a context attribute), the JSON
 // data will be parsed automatically each time that happens, so there will be no efficiency gain.
 // Therefore, if you expect any such operations to happen, it is better to use [Parse] instead to
-// parse the JSON immediately, or use value builder methods such as [ObjectBuild].
+// parse the JSON immediately, or use value builder methods such as [ObjectBuild]. If you only expect
+// to access a few properties of a large JSON object, [LazyRaw] may be more efficient.
 //
 // If you pass malformed data that is not valid JSON, you will get malformed data if it is re-encoded
 // to JSON. It is the caller's responsibility to make sure the json.RawMessage really is valid JSON.
@@ -139,7 +155,7 @@ func Raw(value json.RawMessage) Value {
 	if len(value) == 0 {
 		return Null()
 	}
-	return Value{valueType: RawType, rawValue: slices.Clone(value)}
+	return Value{valueType: uint8(RawType), rawValue: slices.Clone(value)}
 }
 
 // FromJSONMarshal creates a Value from the JSON representation of any Go value.
@@ -165,8 +181,9 @@ func FromJSONMarshal(anyValue any) Value {
 
 // CopyArbitraryValue creates a Value from an arbitrary value of any type.
 //
-// If the value is nil, a boolean, an integer, a floating-point number, or a string, it becomes the
-// corresponding JSON primitive value type. If it is a slice of values ([]any or []Value), it is
+// If the value is nil, a boolean, an integer, a floating-point number, a [json.Number], or a string, it
+// becomes the corresponding JSON primitive value type; integers are stored exactly, as described for
+// [Int64] and [Uint64]. If it is a slice of values ([]any or []Value), it is
 // deep-copied to an array value. If it is a map of strings to values (map[string]any or
 // map[string]Value), it is deep-copied to an object value.
 //
@@ -234,19 +251,19 @@ func CopyArbitraryValue(anyValue any) Value { //nolint:gocyclo // yes, we know i
 		}
 		return Float64(float64(*o))
 	case int:
-		return Float64(float64(o))
+		return Int(o)
 	case *int:
 		if o == nil {
 			return Null()
 		}
-		return Float64(float64(*o))
+		return Int(*o)
 	case uint:
-		return Float64(float64(o))
+		return Uint64(uint64(o))
 	case *uint:
 		if o == nil {
 			return Null()
 		}
-		return Float64(float64(*o))
+		return Uint64(uint64(*o))
 	case int32:
 		return Float64(float64(o))
 	case *int32:
@@ -261,6 +278,20 @@ func CopyArbitraryValue(anyValue any) Value { //nolint:gocyclo // yes, we know i
 			return Null()
 		}
 		return Float64(float64(*o))
+	case int64:
+		return Int64(o)
+	case *int64:
+		if o == nil {
+			return Null()
+		}
+		return Int64(*o)
+	case uint64:
+		return Uint64(o)
+	case *uint64:
+		if o == nil {
+			return Null()
+		}
+		return Uint64(*o)
 	case float32:
 		return Float64(float64(o))
 	case *float32:
@@ -275,6 +306,8 @@ func CopyArbitraryValue(anyValue any) Value { //nolint:gocyclo // yes, we know i
 			return Null()
 		}
 		return Float64(*o)
+	case json.Number:
+		return Number(o)
 	case string:
 		return String(o)
 	case *string:
@@ -379,21 +412,21 @@ func CopyArbitraryValue(anyValue any) Value { //nolint:gocyclo // yes, we know i
 }
 
 func copyArbitraryValueArray(o []any) Value {
-	return Value{valueType: ArrayType, arrayValue: CopyArbitraryValueArray(o)}
+	return newArrayValue(CopyArbitraryValueArray(o))
 }
 
 func copyArbitraryValueMap(o map[string]any) Value {
-	return Value{valueType: ObjectType, objectValue: CopyArbitraryValueMap(o)}
+	return newObjectValue(CopyArbitraryValueMap(o))
 }
 
 // Type returns the ValueType of the Value.
 func (v Value) Type() ValueType {
-	return v.valueType
+	return ValueType(v.valueType)
 }
 
 // IsNull returns true if the Value is a null.
 func (v Value) IsNull() bool {
-	return v.valueType == NullType || (v.valueType == RawType && v.parseIfRaw().IsNull())
+	return v.Type() == NullType || (v.Type() == RawType && v.lazyNode() == nil && v.parseIfRaw().IsNull())
 }
 
 // IsDefined returns true if the Value is anything other than null.
@@ -406,34 +439,34 @@ func (v Value) IsDefined() bool {
 
 // IsBool returns true if the Value is a boolean.
 func (v Value) IsBool() bool {
-	return v.valueType == BoolType || (v.valueType == RawType && v.parseIfRaw().IsBool())
+	return v.Type() == BoolType || (v.Type() == RawType && v.lazyNode() == nil && v.parseIfRaw().IsBool())
 }
 
 // IsNumber returns true if the Value is numeric.
 func (v Value) IsNumber() bool {
-	return v.valueType == NumberType || (v.valueType == RawType && v.parseIfRaw().IsNumber())
+	return v.Type() == NumberType || (v.Type() == RawType && v.lazyNode() == nil && v.parseIfRaw().IsNumber())
 }
 
 // IsInt returns true if the Value is an integer.
 //
 // JSON does not have separate types for integer and floating-point values; they are both just numbers.
-// IsInt returns true if and only if the actual numeric value has no fractional component, so
-// Int(2).IsInt() and Float64(2.0).IsInt() are both true.
+// IsInt returns true if and only if the actual numeric value has no fractional component and is within
+// the range of either int64 or uint64, so Int(2).IsInt() and Float64(2.0).IsInt() are both true.
 func (v Value) IsInt() bool {
-	return (v.valueType == NumberType && v.numberValue == float64(int(v.numberValue))) ||
-		(v.valueType == RawType && v.parseIfRaw().IsInt())
+	return (v.Type() == NumberType && v.numberIsInt()) ||
+		(v.Type() == RawType && v.lazyNode() == nil && v.parseIfRaw().IsInt())
 }
 
 // IsString returns true if the Value is a string.
 func (v Value) IsString() bool {
-	return v.valueType == StringType || (v.valueType == RawType && v.parseIfRaw().IsString())
+	return v.Type() == StringType || (v.Type() == RawType && v.lazyNode() == nil && v.parseIfRaw().IsString())
 }
 
 // BoolValue returns the Value as a boolean.
 //
 // If the Value is not a boolean, it returns false.
 func (v Value) BoolValue() bool {
-	switch v.valueType {
+	switch v.Type() {
 	case BoolType:
 		return v.boolValue
 	case RawType:
@@ -446,18 +479,27 @@ func (v Value) BoolValue() bool {
 // IntValue returns the value as an int.
 //
 // If the Value is not numeric, it returns zero. If the value is a number but not an integer, it is
-// rounded toward zero (truncated).
+// rounded toward zero (truncated). If it is outside of the range of int, the result is the closest
+// value that is within the range; to detect this, use [Value.Int64Value] or [Value.Uint64Value].
 func (v Value) IntValue() int {
-	return int(v.Float64Value())
+	n, _ := v.Int64Value()
+	switch {
+	case n > math.MaxInt:
+		return math.MaxInt
+	case n < math.MinInt:
+		return math.MinInt
+	}
+	return int(n)
 }
 
 // Float64Value returns the value as a float64.
 //
-// If the Value is not numeric, it returns zero.
+// If the Value is not numeric, it returns zero. If it is an integer that cannot be exactly
+// represented as a float64, the result is the closest float64 value.
 func (v Value) Float64Value() float64 {
-	switch v.valueType {
+	switch v.Type() {
 	case NumberType:
-		return v.numberValue
+		return v.numberValue()
 	case RawType:
 		return v.parseIfRaw().Float64Value()
 	default:
@@ -472,7 +514,7 @@ func (v Value) Float64Value() float64 {
 // This is different from [String], which returns a string representation of any value type,
 // including any necessary JSON delimiters.
 func (v Value) StringValue() string {
-	switch v.valueType {
+	switch v.Type() {
 	case StringType:
 		return v.stringValue
 	case RawType:
@@ -485,7 +527,7 @@ func (v Value) StringValue() string {
 // AsOptionalString converts the value to the OptionalString type, which contains either a string
 // value or nothing if the original value was not a string.
 func (v Value) AsOptionalString() OptionalString {
-	switch v.valueType {
+	switch v.Type() {
 	case StringType:
 		return NewOptionalString(v.stringValue)
 	case RawType:
@@ -504,7 +546,7 @@ func (v Value) AsOptionalString() OptionalString {
 // a malformed string such as ldvalue.Raw(json.RawMessage("{{{")), you will get back the same string
 // from AsRaw().
 func (v Value) AsRaw() json.RawMessage {
-	if v.valueType == RawType {
+	if v.Type() == RawType {
 		return v.rawValue
 	}
 	bytes, err := json.Marshal(v)
@@ -518,26 +560,42 @@ func (v Value) AsRaw() json.RawMessage {
 //
 // This is nil for a null value; for primitive types, it is bool, float64, or string (all numbers
 // are represented as float64 because that is Go's default when parsing from JSON). For unparsed
-// JSON data created with [Raw], it returns a [json.RawMessage].
+// JSON data created with [Raw], it returns a [json.RawMessage]. To get numbers that cannot be
+// represented exactly as float64, use [Value.AsArbitraryValueWithExactNumbers].
 //
 // Arrays and objects are represented as []any and map[string]any. They are deep-copied, which
 // preserves immutability of the Value but may be an expensive operation. To examine array and
 // object values without copying the whole data structure, use getter methods: [Value.Count],
 // [Value.Keys], [Value.GetByIndex], [Value.TryGetByIndex], [Value.GetByKey], [Value.TryGetByKey].
 func (v Value) AsArbitraryValue() any {
-	switch v.valueType {
+	return v.asArbitraryValue(false)
+}
+
+// AsArbitraryValueWithExactNumbers is the same as [Value.AsArbitraryValue], except that a number
+// that would lose precision as a float64 is represented as int64 or uint64 if it is an integer, or
+// as a [json.Number] if it was created with [Number] or [ParseLossless]. This also applies to
+// numbers within arrays and objects.
+func (v Value) AsArbitraryValueWithExactNumbers() any {
+	return v.asArbitraryValue(true)
+}
+
+func (v Value) asArbitraryValue(exactNumbers bool) any {
+	switch v.Type() {
 	case NullType:
 		return nil
 	case BoolType:
 		return v.boolValue
 	case NumberType:
-		return v.numberValue
+		if exactNumbers {
+			return v.numberAsExactValue()
+		}
+		return v.numberValue()
 	case StringType:
 		return v.stringValue
 	case ArrayType:
-		return v.arrayValue.AsArbitraryValueSlice()
+		return v.arrayValue().asArbitraryValueSlice(exactNumbers)
 	case ObjectType:
-		return v.objectValue.AsArbitraryValueMap()
+		return v.objectValue().asArbitraryValueMap(exactNumbers)
 	case RawType:
 		return v.AsRaw()
 	default:
@@ -562,27 +620,30 @@ func (v Value) String() string {
 // Equal tests whether this Value is equal to another, in both type and value.
 //
 // For arrays and objects, this is a deep equality test. This method behaves the same as
-// [reflect.DeepEqual], but is slightly more efficient.
+// [reflect.DeepEqual], but is slightly more efficient; the only exception is that numbers are
+// compared by their numeric value, regardless of any original JSON text retained by [Number] or
+// [ParseLossless]. Integers within the range of int64 or uint64 are compared exactly, and all other
+// numbers are compared as float64 values.
 //
 // Unparsed JSON values created with [Raw] will be parsed in order to do this comparison.
 func (v Value) Equal(other Value) bool {
-	if v.valueType == RawType || other.valueType == RawType {
+	if v.Type() == RawType || other.Type() == RawType {
 		return v.parseIfRaw().Equal(other.parseIfRaw())
 	}
-	if v.valueType == other.valueType {
-		switch v.valueType {
+	if v.Type() == other.Type() {
+		switch v.Type() {
 		case NullType:
 			return true
 		case BoolType:
 			return v.boolValue == other.boolValue
 		case NumberType:
-			return v.numberValue == other.numberValue
+			return v.numberEqual(other)
 		case StringType, RawType:
 			return v.stringValue == other.stringValue
 		case ArrayType:
-			return v.arrayValue.Equal(other.arrayValue)
+			return v.arrayValue().Equal(other.arrayValue())
 		case ObjectType:
-			return v.objectValue.Equal(other.objectValue)
+			return v.objectValue().Equal(other.objectValue())
 		}
 	}
 	return false
@@ -605,8 +666,51 @@ func (v Value) AsPointer() *Value {
 	return &v
 }
 
+func newArrayValue(a ValueArray) Value {
+	if a.vec != nil {
+		return Value{valueType: uint8(ArrayType), ref: a.vec}
+	}
+	return Value{valueType: uint8(ArrayType), arrayData: a.data}
+}
+
+func newObjectValue(m ValueMap) Value {
+	if m.trie != nil {
+		return Value{valueType: uint8(ObjectType), ref: m.trie}
+	}
+	return Value{valueType: uint8(ObjectType), ref: m.data}
+}
+
+// arrayValue returns the array that the Value contains, or an empty ValueArray{} if it is not an
+// array.
+func (v Value) arrayValue() ValueArray {
+	if vec, ok := v.ref.(*persistentVector); ok {
+		return ValueArray{vec: vec}
+	}
+	return ValueArray{data: v.arrayData}
+}
+
+// objectValue returns the object that the Value contains, or an empty ValueMap{} if it is not an
+// object.
+func (v Value) objectValue() ValueMap {
+	switch ref := v.ref.(type) {
+	case map[string]Value:
+		return ValueMap{data: ref}
+	case *persistentMap:
+		return ValueMap{trie: ref}
+	default:
+		return ValueMap{}
+	}
+}
+
+// lazyNode returns the index of an unparsed JSON array or object that was created with LazyRaw, or
+// nil if the Value is not one.
+func (v Value) lazyNode() *lazyNode {
+	node, _ := v.ref.(*lazyNode)
+	return node
+}
+
 func (v Value) parseIfRaw() Value {
-	if v.valueType != RawType {
+	if v.Type() != RawType {
 		return v
 	}
 	return Parse(v.rawValue)
@@ -404,7 +404,7 @@ func valuesShouldNotBeEqual(t *testing.T, value0 Value, value1 Value) {
 func TestValueWithInvalidType(t *testing.T) {
 	// Application code has no way to construct a Value like this, but we'll still prove
 	// that we would handle it gracefully if we did it somehow
-	v := Value{valueType: ValueType(99)}
+	v := Value{valueType: 99}
 
 	assert.False(t, v.IsNull())
 	assert.False(t, v.IsNumber())
@@ -0,0 +1,241 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+)
+
+// CanonicalJSON returns the canonical JSON representation of the Value, as defined by the JSON
+// Canonicalization Scheme (JCS) in RFC 8785.
+//
+// Unlike the output of [Value.JSONString] or [Value.MarshalJSON], which does not specify an ordering
+// for the properties of a JSON object, the canonical representation is always exactly the same for
+// Values that are equal according to [Value.Equal]. This makes it suitable for computing a hash of
+// the Value's contents, or for comparing JSON output in tests. Specifically:
+//
+//   - There is no whitespace.
+//   - Object properties are sorted by name, comparing the names as UTF-16 code units.
+//   - Strings are escaped minimally: only '"', '\', and control characters are escaped.
+//   - Numbers are formatted in the same way as JavaScript's Number.prototype.toString(). As RFC 8785
+//     requires, every number is treated as a float64, so an integer that cannot be exactly represented
+//     as a float64, or a number with a different original JSON representation that was created by
+//     [Number] or [ParseLossless], is formatted as the closest float64 value.
+//
+// An unparsed JSON value created with [Raw] is parsed in order to produce this output; if it is not
+// valid JSON, the error is [lderrors.ErrValueRawJSONInvalid]. If the Value contains a number that is
+// NaN or infinite, the error is [lderrors.ErrValueNumberNotFinite].
+func (v Value) CanonicalJSON() ([]byte, error) {
+	return v.appendCanonicalJSON(nil)
+}
+
+// CanonicalJSON returns the canonical JSON representation of the ValueArray, as defined by RFC 8785.
+// See [Value.CanonicalJSON] for details.
+//
+// Like a Go slice, a ValueArray in an uninitialized/nil state produces a JSON null rather than an empty [].
+func (a ValueArray) CanonicalJSON() ([]byte, error) {
+	return a.AsValue().CanonicalJSON()
+}
+
+// CanonicalJSON returns the canonical JSON representation of the ValueMap, as defined by RFC 8785.
+// See [Value.CanonicalJSON] for details.
+//
+// Like a Go map, a ValueMap in an uninitialized/nil state produces a JSON null rather than an empty {}.
+func (m ValueMap) CanonicalJSON() ([]byte, error) {
+	return m.AsValue().CanonicalJSON()
+}
+
+// SortedKeys returns the keys of the map as a slice, sorted in ascending order.
+//
+// If a non-nil slice is passed in, it will be reused to hold the return values if it has enough capacity.
+// Otherwise, a new slice is allocated if there are any keys.
+//
+// Keys are compared in the same way as by the < operator, which, for valid UTF-8 strings, is the same as
+// comparing them by their Unicode code points.
+func (m ValueMap) SortedKeys(sliceIn []string) []string {
+	ret := m.Keys(sliceIn)
+	sort.Strings(ret)
+	return ret
+}
+
+// RangeSorted calls fn for each key-value pair in the map, in the order of the keys as described for
+// [ValueMap.SortedKeys], stopping early if fn returns false. Unlike [ValueMap.Range], this needs to
+// allocate a slice to sort the keys.
+//
+// For an uninitialized ValueMap{}, or a zero-length map, fn is never called.
+func (m ValueMap) RangeSorted(fn func(key string, value Value) bool) {
+	for _, key := range m.SortedKeys(nil) {
+		if !fn(key, m.Get(key)) {
+			return
+		}
+	}
+}
+
+func (v Value) appendCanonicalJSON(buf []byte) ([]byte, error) {
+	var err error
+	switch v.Type() {
+	case NullType:
+		buf = append(buf, nullAsJSON...)
+	case BoolType:
+		buf = strconv.AppendBool(buf, v.boolValue)
+	case NumberType:
+		if math.IsNaN(v.numberValue()) || math.IsInf(v.numberValue(), 0) {
+			return nil, lderrors.ErrValueNumberNotFinite{}
+		}
+		buf = appendCanonicalJSONNumber(buf, v.numberValue())
+	case StringType:
+		buf = appendCanonicalJSONString(buf, v.stringValue)
+	case ArrayType:
+		a := v.arrayValue()
+		buf = append(buf, '[')
+		for i := 0; i < a.Count(); i++ {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = a.Get(i).appendCanonicalJSON(buf); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, ']')
+	case ObjectType:
+		m := v.objectValue()
+		keys := m.Keys(nil)
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+		buf = append(buf, '{')
+		for i, key := range keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendCanonicalJSONString(buf, key)
+			buf = append(buf, ':')
+			if buf, err = m.Get(key).appendCanonicalJSON(buf); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, '}')
+	case RawType:
+		var parsed Value
+		if !json.Valid(v.rawValue) || parsed.UnmarshalJSON(v.rawValue) != nil {
+			return nil, lderrors.ErrValueRawJSONInvalid{}
+		}
+		return parsed.appendCanonicalJSON(buf)
+	}
+	return buf, nil
+}
+
+// appendCanonicalJSONNumber formats a finite number as described in RFC 8785 section 3.2.2.3, which
+// refers to the ECMAScript specification of Number.prototype.toString().
+func appendCanonicalJSONNumber(buf []byte, f float64) []byte {
+	if f == 0 {
+		return append(buf, '0') // this includes negative zero
+	}
+	if f < 0 {
+		buf = append(buf, '-')
+		f = -f
+	}
+	// The 'e' format gives us the shortest digits that represent this value exactly, in the form
+	// "d.ddde+nn"; we just need to rearrange them. In the ECMAScript algorithm, the value is
+	// 0.digits * 10^n.
+	var scratch [32]byte
+	formatted := strconv.AppendFloat(scratch[:0], f, 'e', -1, 64)
+	ePos := len(formatted) - 1
+	for formatted[ePos] != 'e' {
+		ePos--
+	}
+	exp, _ := strconv.Atoi(string(formatted[ePos+1:]))
+	n := exp + 1
+	digits := make([]byte, 0, ePos)
+	digits = append(digits, formatted[0])
+	if ePos > 1 {
+		digits = append(digits, formatted[2:ePos]...)
+	}
+	k := len(digits)
+	switch {
+	case k <= n && n <= 21:
+		buf = append(buf, digits...)
+		for i := 0; i < n-k; i++ {
+			buf = append(buf, '0')
+		}
+	case 0 < n && n <= 21:
+		buf = append(buf, digits[:n]...)
+		buf = append(buf, '.')
+		buf = append(buf, digits[n:]...)
+	case -6 < n && n <= 0:
+		buf = append(buf, '0', '.')
+		for i := 0; i < -n; i++ {
+			buf = append(buf, '0')
+		}
+		buf = append(buf, digits...)
+	default:
+		buf = append(buf, digits[0])
+		if k > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, digits[1:]...)
+		}
+		buf = append(buf, 'e')
+		if n-1 >= 0 {
+			buf = append(buf, '+')
+		}
+		buf = strconv.AppendInt(buf, int64(n-1), 10)
+	}
+	return buf
+}
+
+// appendCanonicalJSONString formats a string as described in RFC 8785 section 3.2.2.2. Invalid UTF-8
+// sequences are replaced with U+FFFD, as they are by encoding/json.
+func appendCanonicalJSONString(buf []byte, s string) []byte {
+	const hexDigits = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, ch := range s {
+		switch {
+		case ch == '"' || ch == '\\':
+			buf = append(buf, '\\', byte(ch))
+		case ch == '\b':
+			buf = append(buf, '\\', 'b')
+		case ch == '\f':
+			buf = append(buf, '\\', 'f')
+		case ch == '\n':
+			buf = append(buf, '\\', 'n')
+		case ch == '\r':
+			buf = append(buf, '\\', 'r')
+		case ch == '\t':
+			buf = append(buf, '\\', 't')
+		case ch < ' ':
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[ch>>4], hexDigits[ch&0xf])
+		default:
+			buf = utf8.AppendRune(buf, ch)
+		}
+	}
+	return append(buf, '"')
+}
+
+// lessUTF16 compares two strings as if they were encoded in UTF-16, as required by RFC 8785 section
+// 3.2.3. This is the same as comparing their Unicode code points, except that characters outside of
+// the Basic Multilingual Plane (which are represented as surrogate pairs in UTF-16) are sorted before
+// the characters from U+E000 to U+FFFF.
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			unitA, unitB := firstUTF16Unit(ra), firstUTF16Unit(rb)
+			if unitA != unitB {
+				return unitA < unitB
+			}
+			return ra < rb // both are surrogate pairs with the same first unit
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	return a == "" && b != ""
+}
+
+func firstUTF16Unit(r rune) rune {
+	if r < 0x10000 {
+		return r
+	}
+	return 0xd800 + (r-0x10000)>>10
+}
@@ -0,0 +1,180 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJSONExampleFromRFC8785(t *testing.T) {
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`
+
+	for name, v := range map[string]Value{
+		"parsed":   Parse([]byte(input)),
+		"lossless": ParseLossless([]byte(input)),
+		"raw":      Raw(json.RawMessage(input)),
+		"lazy":     LazyRaw(json.RawMessage(input)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			output, err := v.CanonicalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(output))
+		})
+	}
+}
+
+func TestCanonicalJSONPropertySorting(t *testing.T) {
+	// This is the example from RFC 8785 section 3.2.3, plus a couple of extra properties.
+	v := Parse([]byte(`{
+		"\u20ac": "Euro Sign",
+		"\r": "Carriage Return",
+		"\ufb33": "Hebrew Letter Dalet With Dagesh",
+		"1": "One",
+		"` + "\U0001f600" + `": "Emoji: Grinning Face",
+		"\u0080": "Control",
+		"\u00f6": "Latin Small Letter O With Diaeresis",
+		"": "Empty",
+		"11": "Eleven"
+	}`))
+	output, err := v.CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"":"Empty","\r":"Carriage Return","1":"One","11":"Eleven","`+"\u0080"+`":"Control",`+
+		`"`+"\u00f6"+`":"Latin Small Letter O With Diaeresis","`+"\u20ac"+`":"Euro Sign",`+
+		`"`+"\U0001f600"+`":"Emoji: Grinning Face","`+"\ufb33"+`":"Hebrew Letter Dalet With Dagesh"}`, string(output))
+}
+
+func TestCanonicalJSONNumbers(t *testing.T) {
+	// These test cases are from RFC 8785 appendix B.
+	for _, p := range []struct {
+		bits     uint64
+		expected string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	} {
+		t.Run(p.expected, func(t *testing.T) {
+			output, err := Float64(math.Float64frombits(p.bits)).CanonicalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, string(output))
+		})
+	}
+
+	output, err := Int64(bigInt64).CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "9007199254740992", string(output))
+	output, err = Number("1.50").CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", string(output))
+}
+
+func TestCanonicalJSONStrings(t *testing.T) {
+	output, err := String("a\"\\/\b\f\n\r\t\x00\x1f\u007f <>&\xff").CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `"a\"\\/\b\f\n\r\t\u0000\u001f`+"\u007f <>&�"+`"`, string(output))
+}
+
+func TestCanonicalJSONCollections(t *testing.T) {
+	m := ValueMapBuild().Set("b", ArrayOf(Int(1), ObjectBuild().Set("y", Null()).Set("x", Bool(true)).Build())).
+		Set("a", String("")).Build()
+	expected := `{"a":"","b":[1,{"x":true,"y":null}]}`
+	for name, v := range map[string]Value{
+		"plain":      m.AsValue(),
+		"persistent": m.With("c", Int(0)).Without("c").AsValue(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			output, err := v.CanonicalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(output))
+		})
+	}
+
+	output, err := m.CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+	output, err = ValueMap{}.CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(output))
+	output, err = ValueArrayOf(Int(1), Int(2)).CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "[1,2]", string(output))
+	output, err = ValueArray{}.CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(output))
+	output, err = ObjectBuild().Build().CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(output))
+}
+
+func TestCanonicalJSONErrors(t *testing.T) {
+	for _, v := range []Value{
+		Float64(math.NaN()),
+		Float64(math.Inf(1)),
+		ArrayOf(Int(1), Float64(math.Inf(-1))),
+		ObjectBuild().Set("a", Float64(math.NaN())).Build(),
+	} {
+		output, err := v.CanonicalJSON()
+		assert.Equal(t, lderrors.ErrValueNumberNotFinite{}, err)
+		assert.Nil(t, output)
+	}
+
+	for _, s := range []string{"{", `{"a":1}x`, "[1,]"} {
+		output, err := Raw(json.RawMessage(s)).CanonicalJSON()
+		assert.Equal(t, lderrors.ErrValueRawJSONInvalid{}, err, "input: %s", s)
+		assert.Nil(t, output)
+	}
+}
+
+func TestValueMapSortedKeysAndRangeSorted(t *testing.T) {
+	m := ValueMapBuild().Set("c", Int(3)).Set("a", Int(1)).Set("b", Int(2)).Set("B", Int(0)).Build()
+	assert.Equal(t, []string{"B", "a", "b", "c"}, m.SortedKeys(nil))
+	assert.Equal(t, []string{"B", "a", "b", "c"}, m.With("d", Int(4)).Without("d").SortedKeys(nil))
+	assert.Nil(t, ValueMap{}.SortedKeys(nil))
+
+	var keys []string
+	var values []Value
+	m.RangeSorted(func(k string, v Value) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		return k != "b"
+	})
+	assert.Equal(t, []string{"B", "a", "b"}, keys)
+	assert.Equal(t, []Value{Int(0), Int(1), Int(2)}, values)
+
+	ValueMap{}.RangeSorted(func(string, Value) bool {
+		assert.Fail(t, "should not be called")
+		return true
+	})
+}
@@ -109,3 +109,44 @@ func generateRandomString() string {
 
 	return string(b)
 }
+
+func BenchmarkCollectionUpdateMapLargeWithBuilder(b *testing.B) {
+	m := CopyArbitraryValueMap(generateRandomArbitraryMap(10_000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m = ValueMapBuildFromMap(m).Set("key", Int(i)).Build()
+	}
+}
+
+func BenchmarkCollectionUpdateMapLargeWithWith(b *testing.B) {
+	m := CopyArbitraryValueMap(generateRandomArbitraryMap(10_000)).With("key", Int(0))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m = m.With("key", Int(i))
+	}
+}
+
+func BenchmarkCollectionAppendArrayLargeWithBuilder(b *testing.B) {
+	a := CopyArbitraryValueArray(make([]any, 10_000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// we start from the same array each time so that this doesn't get progressively slower
+		_ = ValueArrayBuildFromArray(a).Add(Int(i)).Build()
+	}
+}
+
+func BenchmarkCollectionAppendArrayLargeWithAppend(b *testing.B) {
+	a := CopyArbitraryValueArray(make([]any, 10_000)).Append(Null())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a = a.Append(Int(i))
+	}
+}
+
+func generateRandomArbitraryMap(i int) map[string]any {
+	m := make(map[string]any, i)
+	for k, v := range generateRandomMap(i) {
+		m[k] = v
+	}
+	return m
+}
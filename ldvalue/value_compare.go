@@ -0,0 +1,213 @@
+package ldvalue
+
+import (
+	"math"
+	"strings"
+)
+
+// Compare defines a total ordering of Values. It returns a negative number if a is less than b, a
+// positive number if a is greater than b, or zero if they are equal.
+//
+// Values of different types are ordered as follows: null < boolean < number < string < array < object.
+// Values of the same type are ordered as follows:
+//
+//   - false < true.
+//   - Numbers are ordered by numeric value. NaN is less than any other number. Integers within the
+//     range of int64 or uint64 are compared exactly. A number that was created by [Number] or
+//     [ParseLossless] is compared by its closest float64 value, unless that is the same as another
+//     number, in which case the ordering is consistent but not necessarily numerical.
+//   - Strings are compared byte by byte, in the same way as the < operator.
+//   - Arrays are compared element by element, and an array that is a prefix of another is less
+//     than it.
+//   - Objects are compared as if each were an array of key-value pairs sorted by key: first the
+//     first keys are compared, then their values, then the second keys, and so on.
+//
+// Compare(a, b) returns zero if and only if a.Equal(b) is true, except that NaN is considered equal
+// to itself. An unparsed JSON value created with [Raw] is parsed before comparing it.
+func Compare(a, b Value) int {
+	a, b = a.parseIfRaw(), b.parseIfRaw()
+	if a.Type() != b.Type() {
+		return compareOrdered(typeOrder(a.Type()), typeOrder(b.Type()))
+	}
+	switch a.Type() {
+	case BoolType:
+		return compareOrdered(boolOrder(a.boolValue), boolOrder(b.boolValue))
+	case NumberType:
+		return a.numberCompare(b)
+	case StringType:
+		return strings.Compare(a.stringValue, b.stringValue)
+	case ArrayType:
+		return compareArrays(a.arrayValue(), b.arrayValue())
+	case ObjectType:
+		return compareMaps(a.objectValue(), b.objectValue())
+	default:
+		return 0
+	}
+}
+
+// Hash returns a hash code for the Value, which is consistent with [Value.Equal]: if two Values are
+// equal, their hash codes are the same. This allows Values to be used as keys in a hash table.
+//
+// The result does not depend on the order in which the properties of an object were added. It is
+// always the same for the same Value within a single version of this package, but may change
+// between versions, so it should not be persisted. An unparsed JSON value created with [Raw] is
+// parsed in order to compute the hash.
+func (v Value) Hash() uint64 {
+	return mixHash(v.hash(fnvOffset64))
+}
+
+func typeOrder(t ValueType) int {
+	switch t {
+	case NullType:
+		return 0
+	case BoolType:
+		return 1
+	case NumberType:
+		return 2
+	case StringType:
+		return 3
+	case ArrayType:
+		return 4
+	default:
+		return 5
+	}
+}
+
+func boolOrder(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type ordered interface {
+	~int | ~int64 | ~uint64 | ~float64
+}
+
+func compareOrdered[T ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// numberCompare orders numbers first by their float64 value, which is never less precise than the
+// exact order of two integers; then by whether they are stored as exact integers, since an integer
+// can never be equal to a number that is not; and finally by their exact integer values. This is
+// consistent with numberEqual.
+func (v Value) numberCompare(other Value) int {
+	vNaN, otherNaN := math.IsNaN(v.numberValue()), math.IsNaN(other.numberValue())
+	if vNaN || otherNaN {
+		return compareOrdered(boolOrder(!vNaN), boolOrder(!otherNaN))
+	}
+	if c := compareOrdered(v.numberValue(), other.numberValue()); c != 0 {
+		return c
+	}
+	if v.numberIsInt() != other.numberIsInt() {
+		return compareOrdered(boolOrder(!v.numberIsInt()), boolOrder(!other.numberIsInt()))
+	}
+	switch {
+	case v.numberKind != other.numberKind: // one is intNumber and the other is uintNumber
+		return compareOrdered(int(v.numberKind), int(other.numberKind))
+	case v.numberKind == intNumber:
+		return compareOrdered(int64(v.numberBits), int64(other.numberBits))
+	default:
+		return compareOrdered(v.numberBits, other.numberBits)
+	}
+}
+
+func compareArrays(a, b ValueArray) int {
+	for i := 0; i < a.Count() && i < b.Count(); i++ {
+		if c := Compare(a.Get(i), b.Get(i)); c != 0 {
+			return c
+		}
+	}
+	return compareOrdered(a.Count(), b.Count())
+}
+
+func compareMaps(a, b ValueMap) int {
+	aKeys, bKeys := a.SortedKeys(nil), b.SortedKeys(nil)
+	for i := 0; i < len(aKeys) && i < len(bKeys); i++ {
+		if c := strings.Compare(aKeys[i], bKeys[i]); c != 0 {
+			return c
+		}
+		if c := Compare(a.Get(aKeys[i]), b.Get(bKeys[i])); c != 0 {
+			return c
+		}
+	}
+	return compareOrdered(len(aKeys), len(bKeys))
+}
+
+// The hash is computed with 64-bit FNV-1a, with a final mixing step to improve the distribution of
+// the hashes of objects, which are combined by addition so that they do not depend on ordering.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+func (v Value) hash(h uint64) uint64 {
+	if v.Type() == RawType {
+		return v.parseIfRaw().hash(h)
+	}
+	h = hashUint64(h, uint64(v.Type()))
+	switch v.Type() {
+	case BoolType:
+		h = hashUint64(h, uint64(boolOrder(v.boolValue)))
+	case NumberType:
+		// This must be consistent with numberEqual: numbers are equal if their kinds and values are the
+		// same. Since zero is always stored as an integer, two float64 values are only equal if they
+		// have the same bits.
+		h = hashUint64(h, uint64(v.numberKind))
+		h = hashUint64(h, v.numberBits)
+	case StringType:
+		h = hashString(h, v.stringValue)
+	case ArrayType:
+		a := v.arrayValue()
+		h = hashUint64(h, uint64(a.Count()))
+		for i := 0; i < a.Count(); i++ {
+			h = a.Get(i).hash(h)
+		}
+	case ObjectType:
+		m := v.objectValue()
+		h = hashUint64(h, uint64(m.Count()))
+		var sum uint64
+		m.Range(func(key string, value Value) bool {
+			sum += mixHash(value.hash(hashString(fnvOffset64, key)))
+			return true
+		})
+		h = hashUint64(h, sum)
+	}
+	return h
+}
+
+func hashUint64(h, n uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= n & 0xff
+		h *= fnvPrime64
+		n >>= 8
+	}
+	return h
+}
+
+func hashString(h uint64, s string) uint64 {
+	h = hashUint64(h, uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// mixHash is the finalizer from the SplitMix64 generator.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
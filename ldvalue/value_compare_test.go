@@ -0,0 +1,176 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// valuesInOrder is a list of distinct values in ascending order according to Compare.
+var valuesInOrder = []Value{ //nolint:gochecknoglobals
+	Null(),
+	Bool(false),
+	Bool(true),
+	Float64(math.NaN()),
+	Float64(math.Inf(-1)),
+	Int64(math.MinInt64),
+	Int64(-bigInt64),
+	Int(-1),
+	Float64(-0.5),
+	Int(0),
+	Float64(0.25),
+	Float64(0.5),
+	Int(1),
+	Int64(maxExactFloat64Int),
+	Int64(bigInt64),
+	Number("9007199254740992.5"), // same float64 value as the previous two, but not an integer
+	Int64(math.MaxInt64),
+	Uint64(math.MaxInt64 + 1),
+	Uint64(math.MaxUint64),
+	Float64(math.Inf(1)),
+	String(""),
+	String("A"),
+	String("a"),
+	String("ab"),
+	String("b"),
+	ArrayOf(),
+	ArrayOf(Null()),
+	ArrayOf(Int(1)),
+	ArrayOf(Int(1), Null()),
+	ArrayOf(Int(2)),
+	ArrayOf(String("")),
+	ObjectBuild().Build(),
+	ObjectBuild().Set("a", Int(1)).Build(),
+	ObjectBuild().Set("a", Int(1)).Set("b", Null()).Build(),
+	ObjectBuild().Set("a", Int(2)).Build(),
+	ObjectBuild().Set("b", Int(0)).Build(),
+}
+
+func TestCompareOrdersValues(t *testing.T) {
+	for i, a := range valuesInOrder {
+		for j, b := range valuesInOrder {
+			t.Run(fmt.Sprintf("%s vs %s", a.JSONString(), b.JSONString()), func(t *testing.T) {
+				expected := 0
+				if i < j {
+					expected = -1
+				} else if i > j {
+					expected = 1
+				}
+				assert.Equal(t, expected, sign(Compare(a, b)))
+				if !math.IsNaN(a.Float64Value()) { // NaN is never equal to itself
+					assert.Equal(t, expected == 0, a.Equal(b))
+				}
+			})
+		}
+	}
+}
+
+func TestCompareEqualValuesWithDifferentRepresentations(t *testing.T) {
+	for _, p := range [][2]Value{
+		{Float64(2), Int(2)},
+		{Float64(math.Copysign(0, -1)), Int(0)},
+		{Number("0.50"), Float64(0.5)},
+		{Number("0.2500000000000000001"), Float64(0.25)},
+		{Uint64(1), Int64(1)},
+		{
+			Parse([]byte(`[1,{"a":"b","c":null}]`)),
+			ArrayOf(Int(1), ObjectBuild().Set("c", Null()).Set("a", String("b")).Build()),
+		},
+		{Raw(json.RawMessage(`{"a":[true]}`)), ObjectBuild().Set("a", ArrayOf(Bool(true))).Build()},
+		{LazyRaw(json.RawMessage(`{"a":[true]}`)), ObjectBuild().Set("a", ArrayOf(Bool(true))).Build()},
+		{
+			ValueMapBuild().Set("a", Int(1)).Build().With("b", Int(2)).AsValue(),
+			ValueMapBuild().Set("b", Int(2)).Set("a", Int(1)).Build().AsValue(),
+		},
+		{ValueArrayOf(Int(1)).Append(Int(2)).AsValue(), ArrayOf(Int(1), Int(2))},
+	} {
+		t.Run(fmt.Sprintf("%s vs %s", p[0].JSONString(), p[1].JSONString()), func(t *testing.T) {
+			assert.True(t, p[0].Equal(p[1]))
+			assert.Equal(t, 0, Compare(p[0], p[1]))
+			assert.Equal(t, 0, Compare(p[1], p[0]))
+			assert.Equal(t, p[0].Hash(), p[1].Hash())
+		})
+	}
+}
+
+func TestCompareRawValues(t *testing.T) {
+	assert.Equal(t, -1, sign(Compare(Raw(json.RawMessage(`1`)), Raw(json.RawMessage(`"a"`)))))
+	assert.Equal(t, 1, sign(Compare(Raw(json.RawMessage(`[2]`)), ArrayOf(Int(1), Int(2)))))
+	assert.Equal(t, 0, Compare(Raw(json.RawMessage(`{"a":1}`)), Raw(json.RawMessage(` { "a" : 1 } `))))
+}
+
+func TestHashIsConsistentForEqualValues(t *testing.T) {
+	for _, v := range valuesInOrder {
+		assert.Equal(t, v.Hash(), v.Hash())
+		if math.IsNaN(v.Float64Value()) || math.IsInf(v.Float64Value(), 0) {
+			continue // these can't be represented in JSON
+		}
+		assert.Equal(t, v.Hash(), ParseLossless([]byte(v.JSONString())).Hash(), "value: %s", v.JSONString())
+	}
+}
+
+func TestHashIsDifferentForDistinctValues(t *testing.T) {
+	// Hash collisions are possible in general, but not for any of these simple values.
+	hashes := make(map[uint64]Value)
+	for _, v := range append(valuesInOrder,
+		ArrayOf(String("a"), String("b")),
+		ArrayOf(String("ab")),
+		ArrayOf(String("a"), String("")),
+		ObjectBuild().Set("a", String("b")).Build(),
+		ObjectBuild().Set("ab", String("")).Build(),
+		ObjectBuild().Set("a", String("a")).Set("b", String("b")).Build(),
+		ObjectBuild().Set("a", String("b")).Set("b", String("a")).Build(),
+	) {
+		if existing, ok := hashes[v.Hash()]; ok {
+			assert.Fail(t, "hash collision", "%s and %s", existing.JSONString(), v.JSONString())
+		}
+		hashes[v.Hash()] = v
+	}
+}
+
+func TestHashCanBeUsedForValueKeyedMap(t *testing.T) {
+	type entry struct {
+		key   Value
+		value string
+	}
+	table := make(map[uint64][]entry)
+	put := func(key Value, value string) {
+		h := key.Hash()
+		for i, e := range table[h] {
+			if e.key.Equal(key) {
+				table[h][i].value = value
+				return
+			}
+		}
+		table[h] = append(table[h], entry{key, value})
+	}
+	get := func(key Value) string {
+		for _, e := range table[key.Hash()] {
+			if e.key.Equal(key) {
+				return e.value
+			}
+		}
+		return ""
+	}
+
+	put(ObjectBuild().Set("a", Int(1)).Set("b", Int(2)).Build(), "x")
+	put(Int(2), "y")
+	put(Float64(2), "z")
+	assert.Equal(t, "x", get(Parse([]byte(`{"b":2,"a":1}`))))
+	assert.Equal(t, "z", get(Int(2)))
+	assert.Equal(t, "", get(String("2")))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
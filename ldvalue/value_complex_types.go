@@ -44,7 +44,7 @@ func (b *ArrayBuilder) Build() Value {
 	if b == nil {
 		return Null()
 	}
-	return Value{valueType: ArrayType, arrayValue: b.builder.Build()}
+	return newArrayValue(b.builder.Build())
 }
 
 // ArrayOf creates an array Value from a list of Values.
@@ -53,7 +53,7 @@ func (b *ArrayBuilder) Build() Value {
 // using the spread operator, and then modified. However, since Value is itself immutable, it does
 // not need to deep-copy each item.
 func ArrayOf(items ...Value) Value {
-	return Value{valueType: ArrayType, arrayValue: ValueArrayOf(items...)}
+	return newArrayValue(ValueArrayOf(items...))
 }
 
 // ArrayBuild creates a builder for constructing an immutable array [Value].
@@ -75,7 +75,7 @@ func ArrayBuildWithCapacity(capacity int) *ArrayBuilder {
 //
 // If you want to copy a map[string]interface{} instead, use [CopyArbitraryValue].
 func CopyObject(m map[string]Value) Value {
-	return Value{valueType: ObjectType, objectValue: CopyValueMap(m)}
+	return newObjectValue(CopyValueMap(m))
 }
 
 // ObjectBuild creates a builder for constructing an immutable JSON object [Value].
@@ -129,6 +129,31 @@ func (b *ObjectBuilder) SetString(key string, value string) *ObjectBuilder {
 	return b.Set(key, String(value))
 }
 
+// SetPath sets a value within nested JSON objects in the object builder, creating any intermediate
+// objects that do not exist yet. For instance, this sets the "city" property of the "address"
+// property to "Oakland":
+//
+//	ldvalue.ObjectBuild().SetPath([]string{"address", "city"}, ldvalue.String("Oakland"))
+//
+// If the path cannot be followed because an intermediate property has a value that is not a JSON
+// object, or if the path is empty, SetPath does nothing. To find out whether this happened, use
+// [ObjectBuilder.TrySetPath].
+func (b *ObjectBuilder) SetPath(path []string, value Value) *ObjectBuilder {
+	_ = b.TrySetPath(path, value)
+	return b
+}
+
+// TrySetPath is the same as [ObjectBuilder.SetPath], but returns an error if the value could not be
+// set: [lderrors.ErrValuePathNotObject] if an intermediate property has a value that is not a JSON
+// object, or [lderrors.ErrValuePathEmpty] if the path is empty. In either case the builder is left
+// unchanged.
+func (b *ObjectBuilder) TrySetPath(path []string, value Value) error {
+	if b == nil {
+		return nil
+	}
+	return b.builder.TrySetPath(path, value)
+}
+
 // Remove removes a key from the builder if it exists.
 func (b *ObjectBuilder) Remove(key string) *ObjectBuilder {
 	if b != nil {
@@ -143,7 +168,7 @@ func (b *ObjectBuilder) Build() Value {
 	if b == nil {
 		return Null()
 	}
-	return Value{valueType: ObjectType, objectValue: b.builder.Build()}
+	return newObjectValue(b.builder.Build())
 }
 
 // Count returns the number of elements in an array or JSON object.
@@ -151,14 +176,18 @@ func (b *ObjectBuilder) Build() Value {
 // For values of any other type, it returns zero.
 //
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
-// first parses the JSON, which can be inefficient.
+// first parses the JSON, which can be inefficient. If it was created with [LazyRaw], only the
+// requested part of the JSON is parsed.
 func (v Value) Count() int {
-	switch v.valueType {
+	switch v.Type() {
 	case ArrayType:
-		return v.arrayValue.Count()
+		return v.arrayValue().Count()
 	case ObjectType:
-		return v.objectValue.Count()
+		return v.objectValue().Count()
 	case RawType:
+		if node := v.lazyNode(); node != nil {
+			return int(node.entryCount)
+		}
 		return v.parseIfRaw().Count()
 	}
 	return 0
@@ -169,7 +198,8 @@ func (v Value) Count() int {
 // If the value is not an array, or if the index is out of range, it returns [Null]().
 //
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
-// first parses the JSON, which can be inefficient.
+// first parses the JSON, which can be inefficient. If it was created with [LazyRaw], only the
+// requested part of the JSON is parsed.
 func (v Value) GetByIndex(index int) Value {
 	ret, _ := v.TryGetByIndex(index)
 	return ret
@@ -181,12 +211,16 @@ func (v Value) GetByIndex(index int) Value {
 // If the value is not an array, or if the index is out of range, it returns ([Null](), false).
 //
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
-// first parses the JSON, which can be inefficient.
+// first parses the JSON, which can be inefficient. If it was created with [LazyRaw], only the
+// requested part of the JSON is parsed.
 func (v Value) TryGetByIndex(index int) (Value, bool) {
-	if v.valueType == RawType {
+	if v.Type() == RawType {
+		if node := v.lazyNode(); node != nil {
+			return node.tryGetByIndex(index)
+		}
 		return v.parseIfRaw().TryGetByIndex(index)
 	}
-	return v.arrayValue.TryGet(index)
+	return v.arrayValue().TryGet(index)
 	// This is always safe because if v isn't an array, arrayValue is an empty ValueArray{}
 	// and TryGet will always return Null(), false.
 }
@@ -199,13 +233,17 @@ func (v Value) TryGetByIndex(index int) (Value, bool) {
 // The ordering of the keys is undefined.
 //
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
-// first parses the JSON, which can be inefficient.
+// first parses the JSON, which can be inefficient. If it was created with [LazyRaw], only the
+// requested part of the JSON is parsed.
 func (v Value) Keys(sliceIn []string) []string {
 	if v.Type() == RawType {
+		if node := v.lazyNode(); node != nil {
+			return node.keys(sliceIn)
+		}
 		return Parse(v.rawValue).Keys(sliceIn)
 	}
-	if v.valueType == ObjectType {
-		return v.objectValue.Keys(sliceIn)
+	if v.Type() == ObjectType {
+		return v.objectValue().Keys(sliceIn)
 	}
 	return nil
 }
@@ -215,7 +253,8 @@ func (v Value) Keys(sliceIn []string) []string {
 // If the value is not an object, or if the key is not found, it returns [Null]().
 //
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
-// first parses the JSON, which can be inefficient.
+// first parses the JSON, which can be inefficient. If it was created with [LazyRaw], only the
+// requested part of the JSON is parsed.
 func (v Value) GetByKey(name string) Value {
 	ret, _ := v.TryGetByKey(name)
 	return ret
@@ -229,12 +268,16 @@ func (v Value) GetByKey(name string) Value {
 // If the value is not an object, or if the key is not found, it returns ([Null](), false).
 //
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
-// first parses the JSON, which can be inefficient.
+// first parses the JSON, which can be inefficient. If it was created with [LazyRaw], only the
+// requested part of the JSON is parsed.
 func (v Value) TryGetByKey(name string) (Value, bool) {
 	if v.Type() == RawType {
+		if node := v.lazyNode(); node != nil {
+			return node.tryGetByKey(name)
+		}
 		return Parse(v.rawValue).TryGetByKey(name)
 	}
-	return v.objectValue.TryGet(name)
+	return v.objectValue().TryGet(name)
 }
 
 // Transform applies a transformation function to a Value, returning a new Value.
@@ -283,22 +326,22 @@ func (v Value) TryGetByKey(name string) (Value, bool) {
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
 // first parses the JSON, which can be inefficient.
 func (v Value) Transform(fn func(index int, key string, value Value) (Value, bool)) Value {
-	switch v.valueType {
+	switch v.Type() {
 	case NullType:
 		return v
 	case ArrayType:
-		return Value{valueType: ArrayType, arrayValue: v.arrayValue.Transform(
+		return newArrayValue(v.arrayValue().Transform(
 			func(index int, value Value) (Value, bool) {
 				return fn(index, "", value)
 			},
-		)}
+		))
 	case ObjectType:
-		return Value{valueType: ObjectType, objectValue: v.objectValue.Transform(
+		return newObjectValue(v.objectValue().Transform(
 			func(key string, value Value) (string, Value, bool) {
 				resultValue, ok := fn(0, key, value)
 				return key, resultValue, ok
 			},
-		)}
+		))
 	case RawType:
 		return v.parseIfRaw().Transform(fn)
 	default:
@@ -316,7 +359,7 @@ func (v Value) Transform(fn func(index int, key string, value Value) (Value, boo
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
 // first parses the JSON, which can be inefficient.
 func (v Value) AsValueArray() ValueArray {
-	return v.parseIfRaw().arrayValue
+	return v.parseIfRaw().arrayValue()
 }
 
 // AsValueMap converts the Value to the immutable ValueMap type if it is a JSON object. Otherwise
@@ -326,5 +369,5 @@ func (v Value) AsValueArray() ValueArray {
 // If the value is a JSON array or object created from unparsed JSON with [Raw], this method
 // first parses the JSON, which can be inefficient.
 func (v Value) AsValueMap() ValueMap {
-	return v.parseIfRaw().objectValue
+	return v.parseIfRaw().objectValue()
 }
@@ -1,6 +1,9 @@
 package ldvalue
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func BenchmarkArrayBuild(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -19,3 +22,28 @@ func BenchmarkObjectBuild(b *testing.B) {
 		benchmarkValueResult = ObjectBuild().Set("a", Int(1)).Set("b", Int(2)).Set("c", Int(3)).Build()
 	}
 }
+
+func makeLargeJSONObject() []byte {
+	m := ValueMapBuild()
+	for i := 0; i < 1000; i++ {
+		m.Set(fmt.Sprintf("key%d", i), ObjectBuild().Set("a", ArrayOf(Int(i), String("x"))).SetBool("b", true).Build())
+	}
+	m.Set("target", String("value"))
+	return []byte(m.Build().JSONString())
+}
+
+func BenchmarkGetByKeyFromRaw(b *testing.B) {
+	v := Raw(makeLargeJSONObject())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkValueResult = v.GetByKey("target")
+	}
+}
+
+func BenchmarkGetByKeyFromLazyRaw(b *testing.B) {
+	data := makeLargeJSONObject()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkValueResult = LazyRaw(data).GetByKey("target")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -138,9 +140,62 @@ func TestObjectBuild(t *testing.T) {
 	assert.Equal(t, ObjectBuild().SetString("a", "b").Build(), ObjectBuild().Set("a", String("b")).Build())
 }
 
+func TestObjectBuilderSetPath(t *testing.T) {
+	t.Run("creates intermediate objects", func(t *testing.T) {
+		value := ObjectBuild().SetPath([]string{"address", "street", "line1"}, String("x")).Build()
+		assert.Equal(t, `{"address":{"street":{"line1":"x"}}}`, value.JSONString())
+	})
+
+	t.Run("adds to existing objects", func(t *testing.T) {
+		value := ObjectBuild().
+			Set("address", Parse([]byte(`{"city":"y","street":{"line1":"x"}}`))).
+			SetPath([]string{"address", "street", "line2"}, String("z")).
+			Build()
+		assert.Equal(t, Parse([]byte(`{"address":{"city":"y","street":{"line1":"x","line2":"z"}}}`)), value)
+	})
+
+	t.Run("replaces existing value at end of path", func(t *testing.T) {
+		value := ObjectBuild().Set("a", Parse([]byte(`{"b":[1]}`))).SetPath([]string{"a", "b"}, Int(2)).Build()
+		assert.Equal(t, `{"a":{"b":2}}`, value.JSONString())
+	})
+
+	t.Run("single path component is same as Set", func(t *testing.T) {
+		assert.Equal(t, ObjectBuild().Set("a", Int(1)).Build(), ObjectBuild().SetPath([]string{"a"}, Int(1)).Build())
+	})
+
+	t.Run("traverses raw value", func(t *testing.T) {
+		value := ObjectBuild().Set("a", Raw([]byte(`{"b":1}`))).SetPath([]string{"a", "c"}, Int(2)).Build()
+		assert.Equal(t, Parse([]byte(`{"a":{"b":1,"c":2}}`)), value)
+	})
+
+	t.Run("error for non-object in path", func(t *testing.T) {
+		b := ObjectBuild().Set("a", Parse([]byte(`{"b":true}`)))
+		assert.Equal(t, lderrors.ErrValuePathNotObject{Property: "b"}, b.TrySetPath([]string{"a", "b", "c"}, Int(1)))
+		assert.Equal(t, lderrors.ErrValuePathNotObject{Property: "a"},
+			ObjectBuild().SetInt("a", 1).TrySetPath([]string{"a", "b"}, Int(1)))
+		assert.Equal(t, `{"a":{"b":true}}`, b.Build().JSONString())
+	})
+
+	t.Run("error for empty path", func(t *testing.T) {
+		b := ObjectBuild()
+		assert.Equal(t, lderrors.ErrValuePathEmpty{}, b.TrySetPath(nil, Int(1)))
+		assert.Equal(t, `{}`, b.Build().JSONString())
+	})
+
+	t.Run("does not modify previously built object", func(t *testing.T) {
+		b := ObjectBuild().SetPath([]string{"a", "b"}, Int(1))
+		value1 := b.Build()
+		value2 := b.SetPath([]string{"a", "c"}, Int(2)).Build()
+		assert.Equal(t, `{"a":{"b":1}}`, value1.JSONString())
+		assert.Equal(t, Parse([]byte(`{"a":{"b":1,"c":2}}`)), value2)
+	})
+}
+
 func TestObjectBuilderNilSafety(t *testing.T) {
 	var b *ObjectBuilder
 	assert.Nil(t, b.Set("a", Int(1)))
+	assert.Nil(t, b.SetPath([]string{"a", "b"}, Int(1)))
+	assert.NoError(t, b.TrySetPath([]string{"a", "b"}, Int(1)))
 	assert.Nil(t, b.Remove("a"))
 	assert.Equal(t, Null(), b.Build())
 }
@@ -329,7 +384,7 @@ func TestAsValueArray(t *testing.T) {
 	a := value.AsValueArray()
 	expected := ValueArrayOf(String("a"))
 	assert.Equal(t, expected, a)
-	shouldBeSameSlice(t, a.data, value.arrayValue.data)
+	shouldBeSameSlice(t, a.data, value.arrayValue().data)
 
 	aRaw := Raw(json.RawMessage(a.JSONString()))
 	assert.Equal(t, expected, aRaw.AsValueArray())
@@ -346,7 +401,7 @@ func TestAsValueMap(t *testing.T) {
 	m := value.AsValueMap()
 	expected := ValueMapBuild().Set("a", Int(1)).Build()
 	assert.Equal(t, expected, m)
-	shouldBeSameMap(t, m.data, value.objectValue.data)
+	shouldBeSameMap(t, m.data, value.objectValue().data)
 
 	oRaw := Raw(json.RawMessage(m.JSONString()))
 	assert.Equal(t, expected, oRaw.AsValueMap())
@@ -0,0 +1,77 @@
+package ldvalue
+
+import (
+	"io"
+
+	"github.com/launchdarkly/go-sdk-common/v3/internal/jsonseq"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+)
+
+// Decoder reads JSON values from a stream one at a time, so that a large number of them can be
+// processed without holding all of them in memory.
+//
+// Each value is unmarshaled in the same way as [Value.UnmarshalJSON]. A Decoder is used like this:
+//
+//	decoder := ldvalue.NewDecoder(file)
+//	for decoder.Next() {
+//		v := decoder.Value()
+//		// ...
+//	}
+//	if err := decoder.Err(); err != nil {
+//		// ...
+//	}
+//
+// A Decoder is not safe for concurrent use by multiple goroutines.
+type Decoder struct {
+	scanner *jsonseq.Scanner
+	value   Value
+	index   int
+	err     error
+}
+
+// NewDecoder creates a Decoder for a stream containing a JSON array, where each array element is
+// a separate value.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{scanner: jsonseq.NewArrayScanner(r)}
+}
+
+// NewNDJSONDecoder creates a Decoder for a stream containing newline-delimited JSON, where each line
+// is a separate value. Any whitespace between values is allowed, so a JSON object or array that
+// spans multiple lines is also accepted.
+func NewNDJSONDecoder(r io.Reader) *Decoder {
+	return &Decoder{scanner: jsonseq.NewSequenceScanner(r)}
+}
+
+// Next reads the next value from the stream. It returns true if successful, or false if the end of
+// the stream has been reached or there was an error; in that case, [Decoder.Err] returns the error.
+//
+// If the stream is not well-formed JSON, the error is a jreader.SyntaxError. If an individual value
+// could not be unmarshaled, the error is [lderrors.ErrStreamElementInvalid], which contains the
+// underlying unmarshaling error. The Decoder does not continue after an error.
+func (d *Decoder) Next() bool {
+	d.value = Null()
+	if d.err != nil || !d.scanner.Next() {
+		if d.err == nil {
+			d.err = d.scanner.Err()
+		}
+		return false
+	}
+	if err := d.value.UnmarshalJSON(d.scanner.Bytes()); err != nil {
+		d.value = Null()
+		d.err = lderrors.ErrStreamElementInvalid{Index: d.index, Offset: d.scanner.Offset(), Err: err}
+		return false
+	}
+	d.index++
+	return true
+}
+
+// Value returns the value that was read by the last successful call to [Decoder.Next].
+func (d *Decoder) Value() Value {
+	return d.value
+}
+
+// Err returns the error, if any, that caused [Decoder.Next] to return false. If the end of the
+// stream was reached normally, it returns nil.
+func (d *Decoder) Err() error {
+	return d.err
+}
@@ -0,0 +1,76 @@
+package ldvalue
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAllValues(d *Decoder) []Value {
+	var ret []Value
+	for d.Next() {
+		ret = append(ret, d.Value())
+	}
+	return ret
+}
+
+func TestDecoderReadsArray(t *testing.T) {
+	input := `[null, true, 1.5, 9007199254740993, "a\"]", [1, [2]], {"a": {"b": []}}]`
+	expected := []Value{
+		Null(),
+		Bool(true),
+		Float64(1.5),
+		Int64(9007199254740993),
+		String(`a"]`),
+		ArrayOf(Int(1), ArrayOf(Int(2))),
+		ObjectBuild().Set("a", ObjectBuild().Set("b", ArrayOf()).Build()).Build(),
+	}
+	d := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+	assert.Equal(t, expected, decodeAllValues(d))
+	assert.NoError(t, d.Err())
+	assert.False(t, d.Next())
+	assert.Equal(t, Null(), d.Value())
+}
+
+func TestDecoderReadsNDJSON(t *testing.T) {
+	d := NewNDJSONDecoder(strings.NewReader("{\"a\":1}\n[2]\n\"x\"\n3\n"))
+	assert.Equal(t, []Value{ObjectBuild().Set("a", Int(1)).Build(), ArrayOf(Int(2)), String("x"), Int(3)},
+		decodeAllValues(d))
+	assert.NoError(t, d.Err())
+}
+
+func TestDecoderEmptyStreams(t *testing.T) {
+	d := NewDecoder(strings.NewReader("[]"))
+	assert.False(t, d.Next())
+	assert.NoError(t, d.Err())
+
+	d = NewNDJSONDecoder(strings.NewReader("\n"))
+	assert.False(t, d.Next())
+	assert.NoError(t, d.Err())
+}
+
+func TestDecoderInvalidElement(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`[1, {"a" 2}, 3]`))
+	assert.Equal(t, []Value{Int(1)}, decodeAllValues(d))
+	require.IsType(t, lderrors.ErrStreamElementInvalid{}, d.Err())
+	e := d.Err().(lderrors.ErrStreamElementInvalid)
+	assert.Equal(t, 1, e.Index)
+	assert.Equal(t, 4, e.Offset)
+	assert.Equal(t, jreader.SyntaxError{Message: "unexpected character", Value: "2", Offset: 5}, e.Err)
+	assert.Equal(t, `element 1 at position 4 in JSON stream is invalid: unexpected character at position 5 ("2")`,
+		e.Error())
+	assert.Equal(t, Null(), d.Value())
+}
+
+func TestDecoderMalformedStream(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a": 1}`))
+	assert.False(t, d.Next())
+	assert.IsType(t, jreader.SyntaxError{}, d.Err())
+}
@@ -3,7 +3,6 @@ package ldvalue
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 
 	"github.com/launchdarkly/go-jsonstream/v3/jreader"
 	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
@@ -23,6 +22,20 @@ func Parse(jsonData []byte) Value {
 	return v
 }
 
+// ParseLossless is the same as [Parse], except that every number that is not an integer within the
+// range of int64 or uint64 retains its original JSON text, as if it had been created with [Number].
+//
+// This allows numbers such as 0.1000000000000000000001 or 1e400 to be passed through without any
+// change, even though they cannot be represented exactly as float64 values. It is less efficient
+// than Parse.
+func ParseLossless(jsonData []byte) Value {
+	v, err := parseJSONValue(jsonData, true)
+	if err != nil {
+		return Null()
+	}
+	return v
+}
+
 // JSONString returns the JSON representation of the value.
 //
 // This is equivalent to calling [Value.MarshalJSON] and converting the result to a string.
@@ -31,7 +44,7 @@ func Parse(jsonData []byte) Value {
 func (v Value) JSONString() string {
 	// The following is somewhat redundant with json.Marshal, but it avoids the overhead of
 	// converting between byte arrays and strings.
-	switch v.valueType {
+	switch v.Type() {
 	case NullType:
 		return nullAsJSON
 	case BoolType:
@@ -40,10 +53,7 @@ func (v Value) JSONString() string {
 		}
 		return falseString
 	case NumberType:
-		if v.IsInt() {
-			return strconv.Itoa(int(v.numberValue))
-		}
-		return strconv.FormatFloat(v.numberValue, 'f', -1, 64)
+		return v.numberJSONString()
 	}
 	// For all other types, we rely on our custom marshaller.
 	bytes, _ := json.Marshal(v)
@@ -59,7 +69,7 @@ func (v Value) JSONString() string {
 // omitted; it will be output as null. If you want to completely omit a JSON property when there
 // is no value, it must be a pointer; use AsPointer().
 func (v Value) MarshalJSON() ([]byte, error) {
-	switch v.valueType {
+	switch v.Type() {
 	case NullType:
 		return nullAsJSONBytes, nil
 	case BoolType:
@@ -68,16 +78,13 @@ func (v Value) MarshalJSON() ([]byte, error) {
 		}
 		return falseBytes, nil
 	case NumberType:
-		if v.IsInt() {
-			return []byte(strconv.Itoa(int(v.numberValue))), nil
-		}
-		return []byte(strconv.FormatFloat(v.numberValue, 'f', -1, 64)), nil
+		return []byte(v.numberJSONString()), nil
 	case StringType:
 		return json.Marshal(v.stringValue)
 	case ArrayType:
-		return v.arrayValue.MarshalJSON()
+		return v.arrayValue().MarshalJSON()
 	case ObjectType:
-		return v.objectValue.MarshalJSON()
+		return v.objectValue().MarshalJSON()
 	case RawType:
 		return v.rawValue, nil
 	}
@@ -85,14 +92,26 @@ func (v Value) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON parses a Value from JSON.
+//
+// Integers within the range of int64 or uint64 are parsed exactly, as described for [Int64] and
+// [Uint64]; all other numbers are parsed as float64 values. To retain the original text of all such
+// numbers, use [ParseLossless].
 func (v *Value) UnmarshalJSON(data []byte) error {
-	return jreader.UnmarshalJSONWithReader(data, v)
+	parsed, err := parseJSONValue(data, false)
+	if err == nil {
+		*v = parsed
+	}
+	return err
 }
 
 // ReadFromJSONReader provides JSON deserialization for use with the jsonstream API.
 //
 // This implementation is used by the SDK in cases where it is more efficient than [json.Unmarshal].
 // See [github.com/launchdarkly/go-jsonstream/v3] for more details.
+//
+// Since the jsonstream API provides every number as a float64, integers that cannot be exactly
+// represented as a float64 lose precision when they are read this way. [Value.UnmarshalJSON] and
+// [ParseLossless] do not have this limitation.
 func (v *Value) ReadFromJSONReader(r *jreader.Reader) {
 	a := r.Any()
 	if r.Error() != nil {
@@ -108,12 +127,12 @@ func (v *Value) ReadFromJSONReader(r *jreader.Reader) {
 	case jreader.ArrayValue:
 		var va ValueArray
 		if va.readFromJSONArray(r, &a.Array); r.Error() == nil {
-			*v = Value{valueType: ArrayType, arrayValue: va}
+			*v = newArrayValue(va)
 		}
 	case jreader.ObjectValue:
 		var vm ValueMap
 		if vm.readFromJSONObject(r, &a.Object); r.Error() == nil {
-			*v = Value{valueType: ObjectType, objectValue: vm}
+			*v = newObjectValue(vm)
 		}
 	default:
 		*v = Null()
@@ -125,19 +144,28 @@ func (v *Value) ReadFromJSONReader(r *jreader.Reader) {
 // This implementation is used by the SDK in cases where it is more efficient than [json.Marshal].
 // See [github.com/launchdarkly/go-jsonstream/v3] for more details.
 func (v Value) WriteToJSONWriter(w *jwriter.Writer) {
-	switch v.valueType {
+	switch v.Type() {
 	case NullType:
 		w.Null()
 	case BoolType:
 		w.Bool(v.boolValue)
 	case NumberType:
-		w.Float64(v.numberValue)
+		switch {
+		case v.stringValue != "":
+			w.Raw(json.RawMessage(v.stringValue))
+		case v.numberKind == intNumber && int64(int(v.numberBits)) == int64(v.numberBits):
+			w.Int(int(v.numberBits))
+		case v.numberKind != floatNumber:
+			w.Raw(json.RawMessage(v.numberJSONString()))
+		default:
+			w.Float64(v.numberValue())
+		}
 	case StringType:
 		w.String(v.stringValue)
 	case ArrayType:
-		v.arrayValue.WriteToJSONWriter(w)
+		v.arrayValue().WriteToJSONWriter(w)
 	case ObjectType:
-		v.objectValue.WriteToJSONWriter(w)
+		v.objectValue().WriteToJSONWriter(w)
 	case RawType:
 		w.Raw(v.rawValue)
 	}
@@ -156,15 +184,27 @@ func (a ValueArray) JSONString() string {
 //
 // Like a Go slice, a ValueArray in an uninitialized/nil state produces a JSON null rather than an empty [].
 func (a ValueArray) MarshalJSON() ([]byte, error) {
-	if a.data == nil {
+	if !a.IsDefined() {
 		return nullAsJSONBytes, nil
 	}
-	return json.Marshal(a.data)
+	return json.Marshal(a.plainData())
 }
 
-// UnmarshalJSON parses a ValueArray from JSON.
+// UnmarshalJSON parses a ValueArray from JSON. Numbers are parsed in the same way as for
+// [Value.UnmarshalJSON].
 func (a *ValueArray) UnmarshalJSON(data []byte) error {
-	return jreader.UnmarshalJSONWithReader(data, a)
+	v, err := parseJSONValue(data, false)
+	switch {
+	case err != nil:
+		return err
+	case v.Type() == NullType:
+		*a = ValueArray{}
+	case v.Type() == ArrayType:
+		*a = v.arrayValue()
+	default:
+		return jreader.ToJSONError(jreader.TypeError{Expected: jreader.ArrayValue, Nullable: true}, a)
+	}
+	return nil
 }
 
 // ReadFromJSONReader provides JSON deserialization for use with the jsonstream API.
@@ -183,13 +223,13 @@ func (a *ValueArray) ReadFromJSONReader(r *jreader.Reader) {
 //
 // Like a Go slice, a ValueArray in an uninitialized/nil state produces a JSON null rather than an empty [].
 func (a ValueArray) WriteToJSONWriter(w *jwriter.Writer) {
-	if a.data == nil {
+	if !a.IsDefined() {
 		w.Null()
 		return
 	}
 	arr := w.Array()
-	for _, v := range a.data {
-		v.WriteToJSONWriter(w)
+	for i := 0; i < a.Count(); i++ {
+		a.Get(i).WriteToJSONWriter(w)
 	}
 	arr.End()
 }
@@ -235,12 +275,29 @@ func (m ValueMap) JSONString() string {
 //
 // Like a Go map, a ValueMap in an uninitialized/nil state produces a JSON null rather than an empty {}.
 func (m ValueMap) MarshalJSON() ([]byte, error) {
+	if m.trie == nil {
+		// Unlike a ValueMap, a plainValueMap is a single pointer, so it can be converted to a
+		// jwriter.Writable without an allocation.
+		return jwriter.MarshalJSONWithWriter(plainValueMap(m.data))
+	}
 	return jwriter.MarshalJSONWithWriter(m)
 }
 
-// UnmarshalJSON parses a ValueMap from JSON.
+// UnmarshalJSON parses a ValueMap from JSON. Numbers are parsed in the same way as for
+// [Value.UnmarshalJSON].
 func (m *ValueMap) UnmarshalJSON(data []byte) error {
-	return jreader.UnmarshalJSONWithReader(data, m)
+	v, err := parseJSONValue(data, false)
+	switch {
+	case err != nil:
+		return err
+	case v.Type() == NullType:
+		*m = ValueMap{}
+	case v.Type() == ObjectType:
+		*m = v.objectValue()
+	default:
+		return jreader.ToJSONError(jreader.TypeError{Expected: jreader.ObjectValue, Nullable: true}, m)
+	}
+	return nil
 }
 
 // ReadFromJSONReader provides JSON deserialization for use with the jsonstream API.
@@ -259,12 +316,28 @@ func (m *ValueMap) ReadFromJSONReader(r *jreader.Reader) {
 //
 // Like a Go map, a ValueMap in an uninitialized/nil state produces a JSON null rather than an empty {}.
 func (m ValueMap) WriteToJSONWriter(w *jwriter.Writer) {
-	if m.data == nil {
+	if m.trie == nil {
+		plainValueMap(m.data).WriteToJSONWriter(w)
+		return
+	}
+	obj := w.Object()
+	m.trie.root.forEach(func(k string, vv Value) bool {
+		vv.WriteToJSONWriter(obj.Name(k))
+		return true
+	})
+	obj.End()
+}
+
+// plainValueMap is the representation of a ValueMap that was not created by With or Without.
+type plainValueMap map[string]Value
+
+func (m plainValueMap) WriteToJSONWriter(w *jwriter.Writer) {
+	if m == nil {
 		w.Null()
 		return
 	}
 	obj := w.Object()
-	for k, vv := range m.data {
+	for k, vv := range m {
 		vv.WriteToJSONWriter(obj.Name(k))
 	}
 	obj.End()
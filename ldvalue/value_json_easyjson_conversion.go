@@ -3,6 +3,8 @@
 package ldvalue
 
 import (
+	"encoding/json"
+
 	"github.com/mailru/easyjson/jlexer"
 	ej_jwriter "github.com/mailru/easyjson/jwriter"
 )
@@ -21,19 +23,28 @@ import (
 // For more information, see: https://github.com/launchdarkly/go-jsonstream/v3
 
 func (v Value) MarshalEasyJSON(writer *ej_jwriter.Writer) {
-	switch v.valueType {
+	switch v.Type() {
 	case NullType:
 		writer.Raw(nullAsJSONBytes, nil)
 	case BoolType:
 		writer.Bool(v.boolValue)
 	case NumberType:
-		writer.Float64(v.numberValue)
+		switch {
+		case v.stringValue != "":
+			writer.RawString(v.stringValue)
+		case v.numberKind == intNumber:
+			writer.Int64(int64(v.numberBits))
+		case v.numberKind == uintNumber:
+			writer.Uint64(v.numberBits)
+		default:
+			writer.Float64(v.numberValue())
+		}
 	case StringType:
 		writer.String(v.stringValue)
 	case ArrayType:
-		v.arrayValue.MarshalEasyJSON(writer)
+		v.arrayValue().MarshalEasyJSON(writer)
 	case ObjectType:
-		v.objectValue.MarshalEasyJSON(writer)
+		v.objectValue().MarshalEasyJSON(writer)
 	case RawType:
 		writer.Raw(v.rawValue, nil)
 	}
@@ -43,27 +54,37 @@ func (v *Value) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
 	if lexer.IsDelim('[') {
 		var va ValueArray
 		va.UnmarshalEasyJSON(lexer)
-		*v = Value{valueType: ArrayType, arrayValue: va}
+		*v = newArrayValue(va)
 	} else if lexer.IsDelim('{') {
 		var vm ValueMap
 		vm.UnmarshalEasyJSON(lexer)
-		*v = Value{valueType: ObjectType, objectValue: vm}
+		*v = newObjectValue(vm)
 	} else {
-		*v = CopyArbitraryValue(lexer.Interface())
+		// We read the raw token so that a number can be parsed without first converting it to float64.
+		raw := lexer.Raw()
+		if len(raw) != 0 && (raw[0] == '-' || (raw[0] >= '0' && raw[0] <= '9')) {
+			*v = Number(json.Number(raw))
+			if v.stringValue != "" {
+				*v = Float64(v.numberValue())
+			}
+			return
+		}
+		scalarLexer := jlexer.Lexer{Data: raw}
+		*v = CopyArbitraryValue(scalarLexer.Interface())
 	}
 }
 
 func (a ValueArray) MarshalEasyJSON(writer *ej_jwriter.Writer) {
-	if a.data == nil {
+	if !a.IsDefined() {
 		writer.Raw(nullAsJSONBytes, nil)
 		return
 	}
 	writer.RawByte('[')
-	for i, value := range a.data {
+	for i := 0; i < a.Count(); i++ {
 		if i != 0 {
 			writer.RawByte(',')
 		}
-		value.MarshalEasyJSON(writer)
+		a.Get(i).MarshalEasyJSON(writer)
 	}
 	writer.RawByte(']')
 }
@@ -75,24 +96,25 @@ func (a *ValueArray) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
 		return
 	}
 	lexer.Delim('[')
-	a.data = make([]Value, 0, 4)
+	data := make([]Value, 0, 4)
 	for !lexer.IsDelim(']') {
 		var value Value
 		value.UnmarshalEasyJSON(lexer)
-		a.data = append(a.data, value)
+		data = append(data, value)
 		lexer.WantComma()
 	}
 	lexer.Delim(']')
+	*a = ValueArray{data: data}
 }
 
 func (m ValueMap) MarshalEasyJSON(writer *ej_jwriter.Writer) {
-	if m.data == nil {
+	if !m.IsDefined() {
 		writer.Raw(nullAsJSONBytes, nil) //COVERAGE: EasyJSON optimizations may prevent us from reaching this line
 		return
 	}
 	writer.RawByte('{')
 	first := true
-	for key, value := range m.data {
+	writeProperty := func(key string, value Value) bool {
 		if !first {
 			writer.RawByte(',')
 		}
@@ -100,6 +122,14 @@ func (m ValueMap) MarshalEasyJSON(writer *ej_jwriter.Writer) {
 		writer.String(key)
 		writer.RawByte(':')
 		value.MarshalEasyJSON(writer)
+		return true
+	}
+	if m.trie != nil {
+		m.trie.root.forEach(writeProperty)
+	} else {
+		for key, value := range m.data {
+			writeProperty(key, value)
+		}
 	}
 	writer.RawByte('}')
 }
@@ -110,15 +140,16 @@ func (m *ValueMap) UnmarshalEasyJSON(lexer *jlexer.Lexer) {
 		*m = ValueMap{}
 		return
 	}
-	m.data = make(map[string]Value)
+	data := make(map[string]Value)
 	lexer.Delim('{')
 	for !lexer.IsDelim('}') {
 		key := string(lexer.String())
 		lexer.WantColon()
 		var value Value
 		value.UnmarshalEasyJSON(lexer)
-		m.data[key] = value
+		data[key] = value
 		lexer.WantComma()
 	}
 	lexer.Delim('}')
+	*m = ValueMap{data: data}
 }
@@ -22,11 +22,14 @@ func TestValueEasyJsonMarshalUnmarshal(t *testing.T) {
 		{Int(1), "1"},
 		{Float64(1), "1"},
 		{Float64(2.5), "2.5"},
+		{Int64(-9007199254740993), "-9007199254740993"},
+		{Uint64(18446744073709551615), "18446744073709551615"},
 		{String("x"), `"x"`},
 		{ArrayOf(), `[]`},
 		{ArrayBuild().Add(Bool(true)).Add(String("x")).Build(), `[true,"x"]`},
 		{ObjectBuild().Build(), `{}`},
 		{ObjectBuild().Set("a", Bool(true)).Build(), `{"a":true}`},
+		{ArrayOf(Null(), String("\u00e9"), Int64(9007199254740993)), `[null,"é",9007199254740993]`},
 	}
 	for _, item := range items {
 		t.Run(fmt.Sprintf("type %s, json %v", item.value.Type(), item.json), func(t *testing.T) {
@@ -0,0 +1,325 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+)
+
+// This file contains the JSON parser that is used by Value.UnmarshalJSON and ParseLossless. Unlike
+// the jsonstream Reader, which provides every number as a float64, it has access to the text of each
+// number, so it can parse integers exactly in a single pass.
+
+// maxJSONDepth is the maximum nesting depth of arrays and objects that we will parse or index. This
+// is the same as the limit that encoding/json uses.
+const maxJSONDepth = 10000
+
+// These are the same error messages that jreader.Reader uses, except for msgUnexpectedEnd, which is
+// the same as in encoding/json.
+const (
+	msgDataAfterEnd   = "unexpected data after end of JSON value"
+	msgUnexpectedChar = "unexpected character"
+	msgUnexpectedEnd  = "unexpected end of JSON input"
+)
+
+// parseJSONValue parses a complete JSON value. If lossless is true, numbers that are not integers
+// within the range of int64 or uint64 retain their original text, as described for ParseLossless;
+// otherwise they are converted to float64.
+//
+// If the data is not valid JSON, or if there is anything other than whitespace after the value, the
+// error is a jreader.SyntaxError.
+func parseJSONValue(data []byte, lossless bool) (Value, error) {
+	p := jsonParser{jsonScanner: jsonScanner{data: data}, lossless: lossless}
+	p.skipWhitespace()
+	v, ok := p.parseValue(0)
+	if !ok {
+		if p.pos >= len(data) {
+			return Null(), jreader.SyntaxError{Message: msgUnexpectedEnd, Offset: p.pos}
+		}
+		ch, _ := utf8.DecodeRune(data[p.pos:])
+		return Null(), jreader.SyntaxError{Message: msgUnexpectedChar, Value: string(ch), Offset: p.pos}
+	}
+	if p.skipWhitespace(); p.pos != len(data) {
+		return Null(), jreader.SyntaxError{Message: msgDataAfterEnd, Offset: p.pos}
+	}
+	return v, nil
+}
+
+// jsonParser builds a Value from JSON data. Its methods return false if the data is not valid JSON,
+// leaving pos at the place where the problem was found.
+type jsonParser struct {
+	jsonScanner
+	lossless bool
+}
+
+func (p *jsonParser) parseValue(depth int) (Value, bool) {
+	if p.pos >= len(p.data) {
+		return Null(), false
+	}
+	switch ch := p.data[p.pos]; {
+	case ch == '[':
+		return p.parseArray(depth)
+	case ch == '{':
+		return p.parseObject(depth)
+	case ch == '"':
+		s, ok := p.parseString()
+		return String(s), ok
+	case ch == '-' || isDigit(ch):
+		start := p.pos
+		if !p.scanNumber() {
+			return Null(), false
+		}
+		return numberFromJSON(p.data[start:p.pos], p.lossless), true
+	case ch == 't':
+		return Bool(true), p.scanLiteral(trueString)
+	case ch == 'f':
+		return Bool(false), p.scanLiteral(falseString)
+	case ch == 'n':
+		return Null(), p.scanLiteral(nullAsJSON)
+	default:
+		return Null(), false
+	}
+}
+
+func (p *jsonParser) parseArray(depth int) (Value, bool) {
+	if depth >= maxJSONDepth {
+		return Null(), false
+	}
+	var ab ValueArrayBuilder
+	p.pos++
+	p.skipWhitespace()
+	if p.pos < len(p.data) && p.data[p.pos] == ']' {
+		p.pos++
+		return ab.Build().AsValue(), true
+	}
+	for {
+		element, ok := p.parseValue(depth + 1)
+		if !ok {
+			return Null(), false
+		}
+		ab.Add(element)
+		if done, ok := p.endOfElement(']'); !ok || done {
+			return ab.Build().AsValue(), ok
+		}
+	}
+}
+
+func (p *jsonParser) parseObject(depth int) (Value, bool) {
+	if depth >= maxJSONDepth {
+		return Null(), false
+	}
+	var mb ValueMapBuilder
+	p.pos++
+	p.skipWhitespace()
+	if p.pos < len(p.data) && p.data[p.pos] == '}' {
+		p.pos++
+		return mb.Build().AsValue(), true
+	}
+	for {
+		if p.pos >= len(p.data) || p.data[p.pos] != '"' {
+			return Null(), false
+		}
+		key, ok := p.parseString()
+		if !ok {
+			return Null(), false
+		}
+		p.skipWhitespace()
+		if p.pos >= len(p.data) || p.data[p.pos] != ':' {
+			return Null(), false
+		}
+		p.pos++
+		p.skipWhitespace()
+		value, ok := p.parseValue(depth + 1)
+		if !ok {
+			return Null(), false
+		}
+		mb.Set(key, value)
+		if done, ok := p.endOfElement('}'); !ok || done {
+			return mb.Build().AsValue(), ok
+		}
+	}
+}
+
+// endOfElement skips the delimiter after an array element or object property. The first return
+// value is true if it was the end of the array or object.
+func (p *jsonParser) endOfElement(closing byte) (bool, bool) {
+	p.skipWhitespace()
+	if p.pos >= len(p.data) {
+		return false, false
+	}
+	switch p.data[p.pos] {
+	case closing:
+		p.pos++
+		return true, true
+	case ',':
+		p.pos++
+		p.skipWhitespace()
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func (p *jsonParser) parseString() (string, bool) {
+	start := p.pos
+	escaped, ok := p.scanString()
+	if !ok {
+		return "", false
+	}
+	raw := p.data[start:p.pos]
+	if !escaped && utf8.Valid(raw) {
+		return string(raw[1 : len(raw)-1]), true
+	}
+	// This is uncommon enough that it's not worth having our own implementation of escape sequences.
+	// We already know that it is a valid JSON string, so encoding/json will not fail.
+	var s string
+	_ = json.Unmarshal(raw, &s)
+	return s, true
+}
+
+// numberFromJSON converts the text of a valid JSON number to a Value. If lossless is true, this is
+// the same as Number; otherwise, a number that Number would not store as an integer is converted to
+// the closest float64.
+func numberFromJSON(text []byte, lossless bool) Value {
+	// Most numbers are small integers, which can be handled without allocating a string.
+	const maxInt64Digits = 18 // any integer with this many digits is within the range of int64
+	digits := text
+	if text[0] == '-' {
+		digits = text[1:]
+	}
+	if len(digits) <= maxInt64Digits {
+		n := int64(0)
+		isInt := true
+		for _, ch := range digits {
+			if !isDigit(ch) {
+				isInt = false
+				break
+			}
+			n = n*10 + int64(ch-'0')
+		}
+		if isInt {
+			if text[0] == '-' {
+				n = -n
+			}
+			return Int64(n)
+		}
+	}
+	s := string(text)
+	if lossless {
+		return Number(json.Number(s))
+	}
+	negative, mantissa, exponent, _ := parseJSONNumberParts(s)
+	if v, ok := exactIntegerValue(negative, mantissa, exponent); ok {
+		return v
+	}
+	f, _ := strconv.ParseFloat(s, 64) // an out-of-range value becomes +/-Inf or 0, which is the best we can do
+	return Float64(f)
+}
+
+// jsonScanner provides the low-level syntax checking that is shared by jsonParser and lazyScanner.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+// scanString scans a string starting at the opening quote. The first return value is true if the
+// string contains any escape sequences.
+func (s *jsonScanner) scanString() (bool, bool) {
+	escaped := false
+	for s.pos++; s.pos < len(s.data); {
+		ch := s.data[s.pos]
+		switch {
+		case ch == '"':
+			s.pos++
+			return escaped, true
+		case ch == '\\':
+			escaped = true
+			if s.pos+1 >= len(s.data) {
+				return false, false
+			}
+			switch s.data[s.pos+1] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				s.pos += 2
+			case 'u':
+				if s.pos+6 > len(s.data) {
+					return false, false
+				}
+				for _, h := range s.data[s.pos+2 : s.pos+6] {
+					if !isDigit(h) && !(h >= 'a' && h <= 'f') && !(h >= 'A' && h <= 'F') {
+						return false, false
+					}
+				}
+				s.pos += 6
+			default:
+				return false, false
+			}
+		case ch < ' ':
+			return false, false
+		case ch < utf8.RuneSelf:
+			s.pos++
+		default:
+			// encoding/json replaces invalid UTF-8 with U+FFFD rather than rejecting it, and so do we
+			_, size := utf8.DecodeRune(s.data[s.pos:])
+			s.pos += size
+		}
+	}
+	return false, false
+}
+
+func (s *jsonScanner) scanNumber() bool {
+	if s.data[s.pos] == '-' {
+		s.pos++
+	}
+	switch {
+	case s.pos < len(s.data) && s.data[s.pos] == '0':
+		s.pos++
+	case !s.scanDigits():
+		return false
+	}
+	if s.pos < len(s.data) && s.data[s.pos] == '.' {
+		s.pos++
+		if !s.scanDigits() {
+			return false
+		}
+	}
+	if s.pos < len(s.data) && (s.data[s.pos] == 'e' || s.data[s.pos] == 'E') {
+		s.pos++
+		if s.pos < len(s.data) && (s.data[s.pos] == '+' || s.data[s.pos] == '-') {
+			s.pos++
+		}
+		if !s.scanDigits() {
+			return false
+		}
+	}
+	return true
+}
+
+// scanDigits skips one or more digits, returning false if there are none.
+func (s *jsonScanner) scanDigits() bool {
+	start := s.pos
+	for s.pos < len(s.data) && isDigit(s.data[s.pos]) {
+		s.pos++
+	}
+	return s.pos > start
+}
+
+func (s *jsonScanner) scanLiteral(literal string) bool {
+	if len(s.data)-s.pos < len(literal) || string(s.data[s.pos:s.pos+len(literal)]) != literal {
+		return false
+	}
+	s.pos += len(literal)
+	return true
+}
+
+func (s *jsonScanner) skipWhitespace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jreader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONValueIsEquivalentToEncodingJSON(t *testing.T) {
+	for _, input := range []string{
+		`null`,
+		`true`,
+		` false `,
+		`0`,
+		`-1`,
+		`1.5`,
+		`-2.5e-3`,
+		`1E+2`,
+		`""`,
+		`"abc"`,
+		`"a\"b\\c\/d\b\f\n\r\t"`,
+		`"é😀"`,
+		`"é"`,
+		"\"a\xffb\"", // invalid UTF-8 is replaced with U+FFFD
+		`[]`,
+		`[ 1 , [ 2 ] , { } ]`,
+		`{}`,
+		`{ "a" : 1 , "b" : { "c" : [ true ] } }`,
+		`{"ab": 1}`,
+		"\t\r\n[1]\n",
+	} {
+		t.Run(input, func(t *testing.T) {
+			var expected any
+			require.NoError(t, json.Unmarshal([]byte(input), &expected))
+			v, err := parseJSONValue([]byte(input), false)
+			require.NoError(t, err)
+			assert.Equal(t, expected, v.AsArbitraryValue())
+			assert.Equal(t, CopyArbitraryValue(expected), v)
+		})
+	}
+}
+
+func TestParseJSONValueNumbers(t *testing.T) {
+	for _, p := range []struct {
+		input             string
+		expected, exactly Value
+	}{
+		{`0`, Int(0), Int(0)},
+		{`-0`, Int(0), Int(0)},
+		{`123456789012345678`, Int64(123456789012345678), Int64(123456789012345678)},
+		{`9007199254740993`, Int64(9007199254740993), Int64(9007199254740993)},
+		{`-9223372036854775808`, Int64(math.MinInt64), Int64(math.MinInt64)},
+		{`18446744073709551615`, Uint64(math.MaxUint64), Uint64(math.MaxUint64)},
+		{`1e3`, Int(1000), Int(1000)},
+		{`1.5`, Float64(1.5), Float64(1.5)},
+		{`1.50`, Float64(1.5), Number("1.50")},
+		{`18446744073709551616`, Float64(18446744073709551616), Number("18446744073709551616")},
+		{`1e400`, Float64(math.Inf(1)), Number("1e400")},
+	} {
+		t.Run(p.input, func(t *testing.T) {
+			v, err := parseJSONValue([]byte(p.input), false)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, v)
+
+			v, err = parseJSONValue([]byte(p.input), true)
+			require.NoError(t, err)
+			assert.Equal(t, p.exactly, v)
+		})
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		v, err := parseJSONValue([]byte(`{"a": [9007199254740993, {"b": -9007199254740993}]}`), false)
+		require.NoError(t, err)
+		assert.Equal(t, Int64(9007199254740993), v.GetByKey("a").GetByIndex(0))
+		assert.Equal(t, Int64(-9007199254740993), v.GetByKey("a").GetByIndex(1).GetByKey("b"))
+	})
+
+	t.Run("digits in a string are not a number", func(t *testing.T) {
+		v, err := parseJSONValue([]byte(`"12345678901234567"`), false)
+		require.NoError(t, err)
+		assert.Equal(t, String("12345678901234567"), v)
+	})
+}
+
+func TestParseJSONValueErrors(t *testing.T) {
+	for _, p := range []struct {
+		input  string
+		offset int
+	}{
+		{``, 0},
+		{` `, 1},
+		{`what`, 0},
+		{`[`, 1},
+		{`[1,]`, 3},
+		{`[1 2]`, 3},
+		{`{"a" 1}`, 5},
+		{`{"a": 1,}`, 8},
+		{`{a: 1}`, 1},
+		{`-`, 1},
+		{`1.`, 2},
+		{`1e`, 2},
+		{`"abc`, 4},
+		{`"\x"`, 1},
+		{`"\u12"`, 1},
+		{"\"a\nb\"", 2},
+		{`tru`, 0},
+	} {
+		t.Run(p.input, func(t *testing.T) {
+			v, err := parseJSONValue([]byte(p.input), false)
+			assert.Equal(t, Null(), v)
+			require.IsType(t, jreader.SyntaxError{}, err)
+			assert.Equal(t, p.offset, err.(jreader.SyntaxError).Offset)
+			if p.offset == len(p.input) {
+				assert.Equal(t, jreader.SyntaxError{Message: msgUnexpectedEnd, Offset: p.offset}, err)
+			} else {
+				assert.Equal(t, jreader.SyntaxError{Message: msgUnexpectedChar, Offset: p.offset,
+					Value: p.input[p.offset : p.offset+1]}, err)
+			}
+			assert.NotEqual(t, "", err.Error())
+		})
+	}
+
+	t.Run("data after end of value", func(t *testing.T) {
+		v, err := parseJSONValue([]byte(`[1] 2`), false)
+		assert.Equal(t, Null(), v)
+		assert.Equal(t, jreader.SyntaxError{Message: msgDataAfterEnd, Offset: 4}, err)
+
+		_, err = parseJSONValue([]byte(`01`), false)
+		assert.Equal(t, jreader.SyntaxError{Message: msgDataAfterEnd, Offset: 1}, err)
+	})
+
+	t.Run("maximum depth", func(t *testing.T) {
+		ok := strings.Repeat("[", maxJSONDepth) + strings.Repeat("]", maxJSONDepth)
+		_, err := parseJSONValue([]byte(ok), false)
+		assert.NoError(t, err)
+
+		tooDeep := strings.Repeat(`{"a":`, maxJSONDepth+1) + "1" + strings.Repeat("}", maxJSONDepth+1)
+		_, err = parseJSONValue([]byte(tooDeep), false)
+		assert.IsType(t, jreader.SyntaxError{}, err)
+	})
+}
@@ -0,0 +1,1235 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+)
+
+// JSONPath is a compiled JSONPath query, as defined in RFC 9535, which selects any number of values
+// from within a Value. It is created by [ParseJSONPath]. A JSONPath is immutable and can be safely
+// used by multiple goroutines.
+//
+// Unlike an attribute reference (ldattr.Ref), which refers to at most one value, a JSONPath query
+// can use wildcards, array slices, recursive descent, and filter expressions:
+//
+//	$.tiers[0].name
+//	$.tiers[*].name
+//	$.tiers[?@.price < 10]
+//	$..id
+//	$.items[-3:]
+//	$['key with spaces'][?@.enabled == true && length(@.tags) > 1]
+//	$.users[?match(@.email, ".*@example\\.com")]
+//
+// The full syntax of RFC 9535 is supported, including the standard function extensions length(),
+// count(), match(), search(), and value(). There are two implementation-specific details:
+//
+//   - The members of a JSON object are always visited in the order of their keys, as described for
+//     [ValueMap.SortedKeys], so the results of a query are deterministic.
+//   - The regular expressions used by match() and search() are interpreted with Go's [regexp]
+//     package, after adjusting the pattern so that "." does not match a line break, as in the
+//     I-Regexp format of RFC 9485. Go's syntax is a superset of I-Regexp, so some patterns that
+//     RFC 9485 does not allow are accepted.
+//
+// The query is evaluated directly over the Value without converting it to or from JSON. An unparsed
+// JSON value created with [Raw] is parsed as necessary; if it was created with [LazyRaw], only the
+// parts of it that the query visits are parsed.
+type JSONPath struct {
+	source   string
+	segments []jsonPathSegment
+}
+
+// ParseJSONPath compiles a JSONPath expression. See [JSONPath] for the syntax.
+//
+// If the expression is invalid, it returns an error of type [lderrors.ErrValueJSONPathSyntax]
+// indicating where the problem was found.
+func ParseJSONPath(s string) (JSONPath, error) {
+	p := jsonPathParser{s: s}
+	if !p.consume("$") {
+		return JSONPath{}, p.unexpected(`"$"`)
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return JSONPath{}, err
+	}
+	if p.pos < len(s) {
+		return JSONPath{}, p.unexpected(`"." or "["`)
+	}
+	return JSONPath{source: s, segments: segments}, nil
+}
+
+// Query returns the values of all of the nodes that the query selects from the specified Value, in
+// the order defined by RFC 9535. If there are none, it returns an empty array.
+//
+// For an uninitialized JSONPath{}, it always returns an empty array.
+func (p JSONPath) Query(v Value) ValueArray {
+	values, _ := p.query(v, false)
+	return values
+}
+
+// QueryWithPaths is the same as [JSONPath.Query], but also returns the normalized path of each
+// selected node, as defined in RFC 9535 section 2.7, such as "$['tiers'][0]". The paths are in the
+// same order as the values.
+func (p JSONPath) QueryWithPaths(v Value) (ValueArray, []string) {
+	return p.query(v, true)
+}
+
+// String returns the original expression that the query was parsed from.
+func (p JSONPath) String() string {
+	return p.source
+}
+
+func (p JSONPath) query(v Value, withPaths bool) (ValueArray, []string) {
+	if p.source == "" {
+		return ValueArray{data: []Value{}}, nil
+	}
+	root := jsonPathValue(v)
+	e := jsonPathEvaluator{root: root, withPaths: withPaths}
+	nodes := e.evaluate(p.segments, jsonPathNode{value: root})
+	values := make([]Value, len(nodes))
+	var paths []string
+	if withPaths {
+		paths = make([]string, len(nodes))
+	}
+	for i, n := range nodes {
+		values[i] = n.value
+		if withPaths {
+			paths[i] = n.location.normalizedPath()
+		}
+	}
+	return ValueArray{data: values}, paths
+}
+
+// jsonPathValue parses a Raw value so that its type is known, unless it was created with LazyRaw, in
+// which case it is always an array or an object that can be accessed without parsing all of it.
+func jsonPathValue(v Value) Value {
+	if v.Type() == RawType && v.lazyNode() == nil {
+		return v.parseIfRaw()
+	}
+	return v
+}
+
+func jsonPathIsObject(v Value) bool {
+	node := v.lazyNode()
+	return v.Type() == ObjectType || (node != nil && node.isObject)
+}
+
+func jsonPathIsArray(v Value) bool {
+	node := v.lazyNode()
+	return v.Type() == ArrayType || (node != nil && !node.isObject)
+}
+
+type jsonPathNode struct {
+	value    Value
+	location *jsonPathLocation // nil for the root node, or if we are not computing paths
+}
+
+type jsonPathLocation struct {
+	parent  *jsonPathLocation
+	name    string
+	index   int
+	isIndex bool
+}
+
+func (l *jsonPathLocation) normalizedPath() string {
+	var steps []*jsonPathLocation
+	for ; l != nil; l = l.parent {
+		steps = append(steps, l)
+	}
+	buf := []byte{'$'}
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].isIndex {
+			buf = append(buf, '[')
+			buf = strconv.AppendInt(buf, int64(steps[i].index), 10)
+			buf = append(buf, ']')
+			continue
+		}
+		buf = append(buf, '[', '\'')
+		for _, ch := range steps[i].name {
+			switch ch {
+			case '\'', '\\':
+				buf = append(buf, '\\', byte(ch))
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				if ch < ' ' {
+					buf = append(buf, fmt.Sprintf(`\u%04x`, ch)...)
+				} else {
+					buf = utf8.AppendRune(buf, ch)
+				}
+			}
+		}
+		buf = append(buf, '\'', ']')
+	}
+	return string(buf)
+}
+
+type jsonPathEvaluator struct {
+	root      Value
+	withPaths bool
+}
+
+func (e *jsonPathEvaluator) evaluate(segments []jsonPathSegment, start jsonPathNode) []jsonPathNode {
+	nodes := []jsonPathNode{start}
+	for _, segment := range segments {
+		var next []jsonPathNode
+		for _, n := range nodes {
+			if segment.descendant {
+				next = e.selectDescendants(segment.selectors, n, next)
+			} else {
+				next = e.selectChildren(segment.selectors, n, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (e *jsonPathEvaluator) selectChildren(selectors []jsonPathSelector, n jsonPathNode,
+	out []jsonPathNode) []jsonPathNode {
+	for _, s := range selectors {
+		out = s.selectFrom(e, n, out)
+	}
+	return out
+}
+
+// selectDescendants applies the selectors to a node and to all of its descendants, visiting each
+// node before its descendants and visiting array elements in order.
+func (e *jsonPathEvaluator) selectDescendants(selectors []jsonPathSelector, n jsonPathNode,
+	out []jsonPathNode) []jsonPathNode {
+	out = e.selectChildren(selectors, n, out)
+	e.forEachChild(n, func(child jsonPathNode) {
+		out = e.selectDescendants(selectors, child, out)
+	})
+	return out
+}
+
+func (e *jsonPathEvaluator) forEachChild(n jsonPathNode, fn func(jsonPathNode)) {
+	switch {
+	case jsonPathIsArray(n.value):
+		for i := 0; i < n.value.Count(); i++ {
+			fn(e.element(n, i, n.value.GetByIndex(i)))
+		}
+	case jsonPathIsObject(n.value):
+		keys := n.value.Keys(nil)
+		sort.Strings(keys)
+		for _, key := range keys {
+			fn(e.member(n, key, n.value.GetByKey(key)))
+		}
+	}
+}
+
+func (e *jsonPathEvaluator) member(parent jsonPathNode, name string, value Value) jsonPathNode {
+	n := jsonPathNode{value: jsonPathValue(value)}
+	if e.withPaths {
+		n.location = &jsonPathLocation{parent: parent.location, name: name}
+	}
+	return n
+}
+
+func (e *jsonPathEvaluator) element(parent jsonPathNode, index int, value Value) jsonPathNode {
+	n := jsonPathNode{value: jsonPathValue(value)}
+	if e.withPaths {
+		n.location = &jsonPathLocation{parent: parent.location, index: index, isIndex: true}
+	}
+	return n
+}
+
+type jsonPathSegment struct {
+	selectors  []jsonPathSelector
+	descendant bool
+}
+
+type jsonPathSelector interface {
+	selectFrom(e *jsonPathEvaluator, n jsonPathNode, out []jsonPathNode) []jsonPathNode
+}
+
+type jsonPathNameSelector struct{ name string }
+
+type jsonPathWildcardSelector struct{}
+
+type jsonPathIndexSelector struct{ index int64 }
+
+type jsonPathSliceSelector struct {
+	start, end, step int64
+	hasStart, hasEnd bool
+}
+
+type jsonPathFilterSelector struct{ expr jsonPathLogical }
+
+func (s jsonPathNameSelector) selectFrom(e *jsonPathEvaluator, n jsonPathNode, out []jsonPathNode) []jsonPathNode {
+	if jsonPathIsObject(n.value) {
+		if value, ok := n.value.TryGetByKey(s.name); ok {
+			out = append(out, e.member(n, s.name, value))
+		}
+	}
+	return out
+}
+
+func (s jsonPathWildcardSelector) selectFrom(e *jsonPathEvaluator, n jsonPathNode,
+	out []jsonPathNode) []jsonPathNode {
+	e.forEachChild(n, func(child jsonPathNode) { out = append(out, child) })
+	return out
+}
+
+func (s jsonPathIndexSelector) selectFrom(e *jsonPathEvaluator, n jsonPathNode, out []jsonPathNode) []jsonPathNode {
+	if jsonPathIsArray(n.value) {
+		if index, ok := s.normalize(n.value.Count()); ok {
+			out = append(out, e.element(n, index, n.value.GetByIndex(index)))
+		}
+	}
+	return out
+}
+
+func (s jsonPathIndexSelector) normalize(length int) (int, bool) {
+	index := s.index
+	if index < 0 {
+		index += int64(length)
+	}
+	if index < 0 || index >= int64(length) {
+		return 0, false
+	}
+	return int(index), true
+}
+
+// selectFrom implements the slice semantics defined in RFC 9535 section 2.3.4.2.2.
+func (s jsonPathSliceSelector) selectFrom(e *jsonPathEvaluator, n jsonPathNode, out []jsonPathNode) []jsonPathNode {
+	if !jsonPathIsArray(n.value) || s.step == 0 {
+		return out
+	}
+	length := int64(n.value.Count())
+	start, end := int64(0), length
+	if s.step < 0 {
+		start, end = length-1, -length-1
+	}
+	if s.hasStart {
+		start = s.start
+	}
+	if s.hasEnd {
+		end = s.end
+	}
+	if start < 0 {
+		start += length
+	}
+	if end < 0 {
+		end += length
+	}
+	if s.step > 0 {
+		lower, upper := clampInt64(start, 0, length), clampInt64(end, 0, length)
+		for i := lower; i < upper; i += s.step {
+			out = append(out, e.element(n, int(i), n.value.GetByIndex(int(i))))
+		}
+	} else {
+		upper, lower := clampInt64(start, -1, length-1), clampInt64(end, -1, length-1)
+		for i := upper; lower < i; i += s.step {
+			out = append(out, e.element(n, int(i), n.value.GetByIndex(int(i))))
+		}
+	}
+	return out
+}
+
+func clampInt64(n, lower, upper int64) int64 {
+	if n < lower {
+		return lower
+	}
+	if n > upper {
+		return upper
+	}
+	return n
+}
+
+func (s jsonPathFilterSelector) selectFrom(e *jsonPathEvaluator, n jsonPathNode,
+	out []jsonPathNode) []jsonPathNode {
+	e.forEachChild(n, func(child jsonPathNode) {
+		if s.expr.test(e.root, child.value) {
+			out = append(out, child)
+		}
+	})
+	return out
+}
+
+// jsonPathLogical is an expression that produces a LogicalType result in a filter.
+type jsonPathLogical interface {
+	test(root, current Value) bool
+}
+
+// jsonPathComparable is an expression that produces a ValueType result in a filter; the second
+// return value is false if the result is Nothing.
+type jsonPathComparable interface {
+	valueOf(root, current Value) (Value, bool)
+}
+
+type jsonPathOr struct{ left, right jsonPathLogical }
+
+type jsonPathAnd struct{ left, right jsonPathLogical }
+
+type jsonPathNot struct{ operand jsonPathLogical }
+
+type jsonPathComparison struct {
+	left, right jsonPathComparable
+	operator    string
+}
+
+type jsonPathLiteral struct{ value Value }
+
+type jsonPathQuery struct {
+	segments []jsonPathSegment
+	absolute bool
+}
+
+func (x jsonPathOr) test(root, current Value) bool {
+	return x.left.test(root, current) || x.right.test(root, current)
+}
+
+func (x jsonPathAnd) test(root, current Value) bool {
+	return x.left.test(root, current) && x.right.test(root, current)
+}
+
+func (x jsonPathNot) test(root, current Value) bool { return !x.operand.test(root, current) }
+
+func (x jsonPathComparison) test(root, current Value) bool {
+	left, leftOK := x.left.valueOf(root, current)
+	right, rightOK := x.right.valueOf(root, current)
+	equal := func() bool {
+		if !leftOK || !rightOK {
+			return leftOK == rightOK // Nothing is equal only to Nothing
+		}
+		return left.Equal(right)
+	}
+	switch x.operator {
+	case "==":
+		return equal()
+	case "!=":
+		return !equal()
+	case "<":
+		return leftOK && rightOK && jsonPathLess(left, right)
+	case "<=":
+		return (leftOK && rightOK && jsonPathLess(left, right)) || equal()
+	case ">":
+		return leftOK && rightOK && jsonPathLess(right, left)
+	default: // ">="
+		return (leftOK && rightOK && jsonPathLess(right, left)) || equal()
+	}
+}
+
+// jsonPathLess implements the "<" operator, which is only true for two numbers or two strings.
+func jsonPathLess(a, b Value) bool {
+	switch {
+	case a.Type() == NumberType && b.Type() == NumberType:
+		if a.numberValue() != b.numberValue() {
+			return a.numberValue() < b.numberValue()
+		}
+		return a.numberIsInt() && b.numberIsInt() && a.numberCompare(b) < 0
+	case a.Type() == StringType && b.Type() == StringType:
+		return a.stringValue < b.stringValue // for valid UTF-8, this is the same as comparing code points
+	default:
+		return false
+	}
+}
+
+func (x jsonPathLiteral) valueOf(Value, Value) (Value, bool) { return x.value, true }
+
+func (q jsonPathQuery) nodes(root, current Value) []jsonPathNode {
+	start := current
+	if q.absolute {
+		start = root
+	}
+	e := jsonPathEvaluator{root: root}
+	return e.evaluate(q.segments, jsonPathNode{value: start})
+}
+
+// test implements an existence test for a query.
+func (q jsonPathQuery) test(root, current Value) bool {
+	return len(q.nodes(root, current)) != 0
+}
+
+// valueOf is only used for singular queries, so it can do the lookup directly.
+func (q jsonPathQuery) valueOf(root, current Value) (Value, bool) {
+	v := current
+	if q.absolute {
+		v = root
+	}
+	for _, segment := range q.segments {
+		ok := false
+		switch s := segment.selectors[0].(type) {
+		case jsonPathNameSelector:
+			if jsonPathIsObject(v) {
+				v, ok = v.TryGetByKey(s.name)
+			}
+		case jsonPathIndexSelector:
+			if jsonPathIsArray(v) {
+				var index int
+				if index, ok = s.normalize(v.Count()); ok {
+					v = v.GetByIndex(index)
+				}
+			}
+		}
+		if !ok {
+			return Null(), false
+		}
+		v = jsonPathValue(v)
+	}
+	return v, true
+}
+
+func (q jsonPathQuery) isSingular() bool {
+	for _, segment := range q.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		switch segment.selectors[0].(type) {
+		case jsonPathNameSelector, jsonPathIndexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// jsonPathType is one of the types defined in RFC 9535 section 2.4.1 for function parameters and
+// results.
+type jsonPathType int
+
+const (
+	jsonPathValueType jsonPathType = iota
+	jsonPathLogicalType
+	jsonPathNodesType
+)
+
+type jsonPathFunctionDef struct {
+	params []jsonPathType
+	result jsonPathType
+}
+
+var jsonPathFunctions = map[string]jsonPathFunctionDef{ //nolint:gochecknoglobals
+	"length": {[]jsonPathType{jsonPathValueType}, jsonPathValueType},
+	"count":  {[]jsonPathType{jsonPathNodesType}, jsonPathValueType},
+	"match":  {[]jsonPathType{jsonPathValueType, jsonPathValueType}, jsonPathLogicalType},
+	"search": {[]jsonPathType{jsonPathValueType, jsonPathValueType}, jsonPathLogicalType},
+	"value":  {[]jsonPathType{jsonPathNodesType}, jsonPathValueType},
+}
+
+type jsonPathFunction struct {
+	name   string
+	result jsonPathType
+	args   []jsonPathArgument
+	// For match and search, if the pattern is a literal, it is compiled in advance; pattern is nil
+	// if that literal was not a valid regular expression.
+	pattern        *regexp.Regexp
+	literalPattern bool
+}
+
+type jsonPathArgument struct {
+	value jsonPathComparable // for a ValueType parameter
+	nodes jsonPathQuery      // for a NodesType parameter
+}
+
+// valueOf implements the functions whose result is ValueType.
+func (f *jsonPathFunction) valueOf(root, current Value) (Value, bool) {
+	switch f.name {
+	case "length":
+		v, ok := f.args[0].value.valueOf(root, current)
+		switch {
+		case !ok:
+			return Null(), false
+		case v.Type() == StringType:
+			return Int(utf8.RuneCountInString(v.stringValue)), true
+		case jsonPathIsArray(v), jsonPathIsObject(v):
+			return Int(v.Count()), true
+		default:
+			return Null(), false
+		}
+	case "count":
+		return Int(len(f.args[0].nodes.nodes(root, current))), true
+	default: // "value"
+		if nodes := f.args[0].nodes.nodes(root, current); len(nodes) == 1 {
+			return nodes[0].value, true
+		}
+		return Null(), false
+	}
+}
+
+// test implements the functions whose result is LogicalType, which are match and search.
+func (f *jsonPathFunction) test(root, current Value) bool {
+	s, ok := f.args[0].value.valueOf(root, current)
+	if !ok || s.Type() != StringType {
+		return false
+	}
+	pattern := f.pattern
+	if !f.literalPattern {
+		p, ok := f.args[1].value.valueOf(root, current)
+		if !ok || p.Type() != StringType {
+			return false
+		}
+		pattern = compileJSONPathRegexp(p.stringValue, f.name == "match")
+	}
+	return pattern != nil && pattern.MatchString(s.stringValue)
+}
+
+// compileJSONPathRegexp converts an I-Regexp pattern (RFC 9485) to Go's regexp syntax. In I-Regexp,
+// "." matches any character except a line break, and "^" and "$" are not anchors. The match function
+// requires the entire string to match, while search looks for a matching substring. It returns nil
+// if the pattern is invalid.
+func compileJSONPathRegexp(pattern string, entireString bool) *regexp.Regexp {
+	var b strings.Builder
+	if entireString {
+		b.WriteString(`\A(?:`)
+	}
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			b.WriteByte(ch)
+			i++
+			b.WriteByte(pattern[i])
+		case inClass:
+			inClass = ch != ']'
+			b.WriteByte(ch)
+		case ch == '[':
+			inClass = true
+			b.WriteByte(ch)
+		case ch == '.':
+			b.WriteString(`[^\n\r]`)
+		case ch == '^', ch == '$':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	if entireString {
+		b.WriteString(`)\z`)
+	}
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+const (
+	maxJSONPathInt   = 1<<53 - 1 // RFC 9535 restricts indexes to the range of integers that are exact in I-JSON
+	maxJSONPathDepth = 1000
+)
+
+type jsonPathParser struct {
+	s     string
+	pos   int
+	depth int
+}
+
+// jsonPathOperand is a parsed filter expression whose type is not yet known, because it depends on
+// where it is used.
+type jsonPathOperand struct {
+	kind     jsonPathOperandKind
+	pos      int
+	literal  Value
+	query    jsonPathQuery
+	function *jsonPathFunction
+	logical  jsonPathLogical
+}
+
+type jsonPathOperandKind int
+
+const (
+	jsonPathLiteralOperand jsonPathOperandKind = iota
+	jsonPathQueryOperand
+	jsonPathFunctionOperand
+	jsonPathLogicalOperand
+)
+
+func (p *jsonPathParser) syntaxError(pos int, format string, args ...any) error {
+	return lderrors.ErrValueJSONPathSyntax{Position: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *jsonPathParser) unexpected(expected string) error {
+	if p.pos >= len(p.s) {
+		return p.syntaxError(p.pos, "expected %s, found end of expression", expected)
+	}
+	r, _ := utf8.DecodeRuneInString(p.s[p.pos:])
+	return p.syntaxError(p.pos, "expected %s, found %q", expected, string(r))
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipWhitespace() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSegments() ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	for {
+		start := p.pos
+		p.skipWhitespace()
+		if ch := p.peek(); ch != '.' && ch != '[' {
+			p.pos = start // whitespace is only allowed between segments, not after them
+			return segments, nil
+		}
+		segment, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+}
+
+func (p *jsonPathParser) parseSegment() (jsonPathSegment, error) {
+	var segment jsonPathSegment
+	var err error
+	if p.consume("..") {
+		segment.descendant = true
+		if p.peek() == '[' {
+			segment.selectors, err = p.parseBracketedSelection()
+			return segment, err
+		}
+	} else if !p.consume(".") {
+		segment.selectors, err = p.parseBracketedSelection()
+		return segment, err
+	}
+	switch {
+	case p.consume("*"):
+		segment.selectors = []jsonPathSelector{jsonPathWildcardSelector{}}
+	case p.isMemberNameChar(false):
+		start := p.pos
+		for p.isMemberNameChar(true) {
+			_, size := utf8.DecodeRuneInString(p.s[p.pos:])
+			p.pos += size
+		}
+		segment.selectors = []jsonPathSelector{jsonPathNameSelector{name: p.s[start:p.pos]}}
+	default:
+		return segment, p.unexpected(`a member name or "*"`)
+	}
+	return segment, nil
+}
+
+func (p *jsonPathParser) isMemberNameChar(allowDigits bool) bool {
+	ch := p.peek()
+	switch {
+	case (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_':
+		return true
+	case ch >= '0' && ch <= '9':
+		return allowDigits
+	case ch >= utf8.RuneSelf:
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		return r != utf8.RuneError || size > 1
+	default:
+		return false
+	}
+}
+
+func (p *jsonPathParser) parseBracketedSelection() ([]jsonPathSelector, error) {
+	p.pos++ // skip '['
+	var selectors []jsonPathSelector
+	for {
+		p.skipWhitespace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipWhitespace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.unexpected(`"," or "]"`)
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch ch := p.peek(); {
+	case ch == '\'' || ch == '"':
+		name, err := p.parseStringLiteral()
+		return jsonPathNameSelector{name: name}, err
+	case ch == '*':
+		p.pos++
+		return jsonPathWildcardSelector{}, nil
+	case ch == '?':
+		p.pos++
+		p.skipWhitespace()
+		expr, err := p.parseLogicalExpr()
+		return jsonPathFilterSelector{expr: expr}, err
+	case ch == ':' || ch == '-' || (ch >= '0' && ch <= '9'):
+		return p.parseIndexOrSlice()
+	default:
+		return nil, p.unexpected("a selector")
+	}
+}
+
+func (p *jsonPathParser) parseIndexOrSlice() (jsonPathSelector, error) {
+	slice := jsonPathSliceSelector{step: 1}
+	var err error
+	if p.peek() != ':' {
+		if slice.start, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if p.peek() != ':' {
+			return jsonPathIndexSelector{index: slice.start}, nil
+		}
+		slice.hasStart = true
+	}
+	p.pos++ // skip ':'
+	p.skipWhitespace()
+	if ch := p.peek(); ch == '-' || (ch >= '0' && ch <= '9') {
+		if slice.end, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+		slice.hasEnd = true
+		p.skipWhitespace()
+	}
+	if p.consume(":") {
+		p.skipWhitespace()
+		if ch := p.peek(); ch == '-' || (ch >= '0' && ch <= '9') {
+			if slice.step, err = p.parseInt(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return slice, nil
+}
+
+func (p *jsonPathParser) parseInt() (int64, error) {
+	start := p.pos
+	p.consume("-")
+	digitsStart := p.pos
+	for ch := p.peek(); ch >= '0' && ch <= '9'; ch = p.peek() {
+		p.pos++
+	}
+	if p.pos == digitsStart {
+		return 0, p.unexpected("a digit")
+	}
+	text := p.s[start:p.pos]
+	if p.s[digitsStart] == '0' && text != "0" {
+		return 0, p.syntaxError(start, "invalid integer %q", text)
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || n > maxJSONPathInt || n < -maxJSONPathInt {
+		return 0, p.syntaxError(start, "integer %s is out of range", text)
+	}
+	return n, nil
+}
+
+func (p *jsonPathParser) parseStringLiteral() (string, error) {
+	start := p.pos
+	quote := p.s[p.pos]
+	p.pos++
+	var buf []byte
+	for {
+		if p.pos >= len(p.s) {
+			return "", p.syntaxError(start, "unterminated string")
+		}
+		ch := p.s[p.pos]
+		switch {
+		case ch == quote:
+			p.pos++
+			return string(buf), nil
+		case ch == '\\':
+			r, err := p.parseEscapeSequence(quote)
+			if err != nil {
+				return "", err
+			}
+			buf = utf8.AppendRune(buf, r)
+		case ch < ' ':
+			return "", p.syntaxError(p.pos, "control characters in a string must be escaped")
+		default:
+			r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+			if r == utf8.RuneError && size <= 1 {
+				return "", p.syntaxError(p.pos, "invalid UTF-8 in string")
+			}
+			buf = append(buf, p.s[p.pos:p.pos+size]...)
+			p.pos += size
+		}
+	}
+}
+
+func (p *jsonPathParser) parseEscapeSequence(quote byte) (rune, error) {
+	start := p.pos
+	p.pos++ // skip '\'
+	ch := p.peek()
+	p.pos++
+	switch ch {
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case '/', '\\', quote:
+		return rune(ch), nil
+	case 'u':
+		r, ok := p.parseHex4()
+		if ok && utf16.IsSurrogate(r) {
+			if r >= 0xdc00 || !p.consume(`\u`) {
+				ok = false
+			} else if low, lowOK := p.parseHex4(); lowOK && low >= 0xdc00 && low <= 0xdfff {
+				r = utf16.DecodeRune(r, low)
+			} else {
+				ok = false
+			}
+		}
+		if ok {
+			return r, nil
+		}
+	}
+	return 0, p.syntaxError(start, "invalid escape sequence in string")
+}
+
+func (p *jsonPathParser) parseHex4() (rune, bool) {
+	if p.pos+4 > len(p.s) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	p.pos += 4
+	return rune(n), true
+}
+
+func (p *jsonPathParser) parseLogicalExpr() (jsonPathLogical, error) {
+	operand, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return p.asLogical(operand)
+}
+
+func (p *jsonPathParser) parseOr() (jsonPathOperand, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxJSONPathDepth {
+		return jsonPathOperand{}, p.syntaxError(p.pos, "expression is nested too deeply")
+	}
+	left, err := p.parseAnd()
+	if err != nil {
+		return left, err
+	}
+	for {
+		p.skipWhitespace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		p.skipWhitespace()
+		right, err := p.parseAnd()
+		if err != nil {
+			return right, err
+		}
+		l, err := p.asLogical(left)
+		if err != nil {
+			return left, err
+		}
+		r, err := p.asLogical(right)
+		if err != nil {
+			return right, err
+		}
+		left = jsonPathOperand{kind: jsonPathLogicalOperand, pos: left.pos, logical: jsonPathOr{l, r}}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (jsonPathOperand, error) {
+	left, err := p.parseBasic()
+	if err != nil {
+		return left, err
+	}
+	for {
+		p.skipWhitespace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		p.skipWhitespace()
+		right, err := p.parseBasic()
+		if err != nil {
+			return right, err
+		}
+		l, err := p.asLogical(left)
+		if err != nil {
+			return left, err
+		}
+		r, err := p.asLogical(right)
+		if err != nil {
+			return right, err
+		}
+		left = jsonPathOperand{kind: jsonPathLogicalOperand, pos: left.pos, logical: jsonPathAnd{l, r}}
+	}
+}
+
+func (p *jsonPathParser) parseBasic() (jsonPathOperand, error) {
+	start := p.pos
+	if p.consume("!") {
+		p.skipWhitespace()
+		var operand jsonPathOperand
+		var err error
+		if p.peek() == '(' {
+			operand, err = p.parseParenExpr()
+		} else {
+			operand, err = p.parsePrimary()
+		}
+		if err != nil {
+			return operand, err
+		}
+		logical, err := p.asLogical(operand)
+		if err != nil {
+			return operand, err
+		}
+		return jsonPathOperand{kind: jsonPathLogicalOperand, pos: start, logical: jsonPathNot{logical}}, nil
+	}
+	if p.peek() == '(' {
+		return p.parseParenExpr()
+	}
+	left, err := p.parsePrimary()
+	if err != nil {
+		return left, err
+	}
+	afterLeft := p.pos
+	p.skipWhitespace()
+	var operator string
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" {
+		p.pos = afterLeft
+		return left, nil
+	}
+	p.skipWhitespace()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return right, err
+	}
+	l, err := p.asComparable(left)
+	if err != nil {
+		return left, err
+	}
+	r, err := p.asComparable(right)
+	if err != nil {
+		return right, err
+	}
+	return jsonPathOperand{kind: jsonPathLogicalOperand, pos: start,
+		logical: jsonPathComparison{left: l, right: r, operator: operator}}, nil
+}
+
+func (p *jsonPathParser) parseParenExpr() (jsonPathOperand, error) {
+	start := p.pos
+	p.pos++ // skip '('
+	p.skipWhitespace()
+	expr, err := p.parseLogicalExpr()
+	if err != nil {
+		return jsonPathOperand{}, err
+	}
+	p.skipWhitespace()
+	if !p.consume(")") {
+		return jsonPathOperand{}, p.unexpected(`")"`)
+	}
+	return jsonPathOperand{kind: jsonPathLogicalOperand, pos: start, logical: expr}, nil
+}
+
+func (p *jsonPathParser) parsePrimary() (jsonPathOperand, error) {
+	start := p.pos
+	switch ch := p.peek(); {
+	case ch == '@' || ch == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		return jsonPathOperand{kind: jsonPathQueryOperand, pos: start,
+			query: jsonPathQuery{segments: segments, absolute: ch == '$'}}, err
+	case ch == '\'' || ch == '"':
+		s, err := p.parseStringLiteral()
+		return jsonPathOperand{kind: jsonPathLiteralOperand, pos: start, literal: String(s)}, err
+	case ch == '-' || (ch >= '0' && ch <= '9'):
+		return p.parseNumber()
+	case ch >= 'a' && ch <= 'z':
+		for ch := p.peek(); (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '_'; ch = p.peek() {
+			p.pos++
+		}
+		name := p.s[start:p.pos]
+		if p.peek() == '(' {
+			return p.parseFunction(name, start)
+		}
+		literal := jsonPathOperand{kind: jsonPathLiteralOperand, pos: start}
+		switch name {
+		case "true":
+			literal.literal = Bool(true)
+		case "false":
+			literal.literal = Bool(false)
+		case "null":
+		default:
+			return literal, p.syntaxError(start, "unexpected %q", name)
+		}
+		return literal, nil
+	default:
+		return jsonPathOperand{}, p.unexpected("a query, a literal value, or a function")
+	}
+}
+
+func (p *jsonPathParser) parseNumber() (jsonPathOperand, error) {
+	start := p.pos
+	isDigit := func() bool { ch := p.peek(); return ch >= '0' && ch <= '9' }
+	skipDigits := func() bool {
+		digitsStart := p.pos
+		for isDigit() {
+			p.pos++
+		}
+		return p.pos > digitsStart
+	}
+	p.consume("-")
+	if p.consume("0") {
+		if isDigit() {
+			return jsonPathOperand{}, p.syntaxError(start, "invalid number")
+		}
+	} else if !skipDigits() {
+		return jsonPathOperand{}, p.unexpected("a digit")
+	}
+	if p.consume(".") && !skipDigits() {
+		return jsonPathOperand{}, p.unexpected("a digit")
+	}
+	if ch := p.peek(); ch == 'e' || ch == 'E' {
+		p.pos++
+		if !p.consume("-") {
+			p.consume("+")
+		}
+		if !skipDigits() {
+			return jsonPathOperand{}, p.unexpected("a digit")
+		}
+	}
+	return jsonPathOperand{kind: jsonPathLiteralOperand, pos: start,
+		literal: Number(json.Number(p.s[start:p.pos]))}, nil
+}
+
+func (p *jsonPathParser) parseFunction(name string, start int) (jsonPathOperand, error) {
+	def, ok := jsonPathFunctions[name]
+	if !ok {
+		return jsonPathOperand{}, p.syntaxError(start, "unknown function %q", name)
+	}
+	f := &jsonPathFunction{name: name, result: def.result}
+	p.pos++ // skip '('
+	p.skipWhitespace()
+	if !p.consume(")") {
+		for {
+			operand, err := p.parseOr()
+			if err != nil {
+				return operand, err
+			}
+			if len(f.args) == len(def.params) {
+				return operand, p.syntaxError(operand.pos, "too many arguments for function %s()", name)
+			}
+			arg, err := p.asArgument(operand, def.params[len(f.args)], name)
+			if err != nil {
+				return operand, err
+			}
+			f.args = append(f.args, arg)
+			p.skipWhitespace()
+			if p.consume(")") {
+				break
+			}
+			if !p.consume(",") {
+				return operand, p.unexpected(`"," or ")"`)
+			}
+			p.skipWhitespace()
+		}
+	}
+	if len(f.args) < len(def.params) {
+		return jsonPathOperand{}, p.syntaxError(start, "function %s() requires %d argument(s)", name, len(def.params))
+	}
+	if name == "match" || name == "search" {
+		if literal, ok := f.args[1].value.(jsonPathLiteral); ok {
+			f.literalPattern = true
+			if literal.value.Type() == StringType {
+				f.pattern = compileJSONPathRegexp(literal.value.stringValue, name == "match")
+			}
+		}
+	}
+	return jsonPathOperand{kind: jsonPathFunctionOperand, pos: start, function: f}, nil
+}
+
+func (p *jsonPathParser) asArgument(o jsonPathOperand, paramType jsonPathType, name string) (jsonPathArgument, error) {
+	if paramType == jsonPathNodesType {
+		if o.kind != jsonPathQueryOperand {
+			return jsonPathArgument{}, p.syntaxError(o.pos, "argument of function %s() must be a query", name)
+		}
+		return jsonPathArgument{nodes: o.query}, nil
+	}
+	value, err := p.asComparable(o)
+	return jsonPathArgument{value: value}, err
+}
+
+// asComparable checks that an operand can be used where a ValueType is required, as defined in
+// RFC 9535 section 2.4.3.
+func (p *jsonPathParser) asComparable(o jsonPathOperand) (jsonPathComparable, error) {
+	switch o.kind {
+	case jsonPathLiteralOperand:
+		return jsonPathLiteral{o.literal}, nil
+	case jsonPathQueryOperand:
+		if !o.query.isSingular() {
+			return nil, p.syntaxError(o.pos, "a query used as a value must be a singular query")
+		}
+		return o.query, nil
+	case jsonPathFunctionOperand:
+		if o.function.result == jsonPathValueType {
+			return o.function, nil
+		}
+		return nil, p.syntaxError(o.pos, "the result of function %s() cannot be used as a value", o.function.name)
+	default:
+		return nil, p.syntaxError(o.pos, "a logical expression cannot be used as a value")
+	}
+}
+
+// asLogical checks that an operand can be used where a LogicalType is required, as defined in
+// RFC 9535 section 2.4.3. A query is converted to an existence test.
+func (p *jsonPathParser) asLogical(o jsonPathOperand) (jsonPathLogical, error) {
+	switch o.kind {
+	case jsonPathLiteralOperand:
+		return nil, p.syntaxError(o.pos, "a literal value must be compared with something")
+	case jsonPathQueryOperand:
+		return o.query, nil
+	case jsonPathFunctionOperand:
+		if o.function.result == jsonPathLogicalType {
+			return o.function, nil
+		}
+		return nil, p.syntaxError(o.pos, "the result of function %s() must be compared with something", o.function.name)
+	default:
+		return o.logical, nil
+	}
+}
@@ -0,0 +1,389 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This is the example document from RFC 9535 section 1.5.
+const jsonPathBookstore = `{ "store": {
+    "book": [
+      { "category": "reference",
+        "author": "Nigel Rees",
+        "title": "Sayings of the Century",
+        "price": 8.95
+      },
+      { "category": "fiction",
+        "author": "Evelyn Waugh",
+        "title": "Sword of Honour",
+        "price": 12.99
+      },
+      { "category": "fiction",
+        "author": "Herman Melville",
+        "title": "Moby Dick",
+        "isbn": "0-553-21311-3",
+        "price": 8.99
+      },
+      { "category": "fiction",
+        "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings",
+        "isbn": "0-395-19395-8",
+        "price": 22.99
+      }
+    ],
+    "bicycle": {
+      "color": "red",
+      "price": 399
+    }
+  }
+}`
+
+func jsonPathQueryJSON(t *testing.T, path string, v Value) string {
+	p, err := ParseJSONPath(path)
+	require.NoError(t, err)
+	return p.Query(v).JSONString()
+}
+
+func TestJSONPathBookstoreExamples(t *testing.T) {
+	for _, p := range []struct {
+		path     string
+		expected string
+	}{
+		{`$.store.book[*].author`, `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{`$..author`, `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{`$.store.*.price`, `[399]`}, // store.book is an array, so it has no "price" member
+		{`$.store..price`, `[399,8.95,12.99,8.99,22.99]`},
+		{`$..book[2]`, `[{"category":"fiction","author":"Herman Melville","title":"Moby Dick",` +
+			`"isbn":"0-553-21311-3","price":8.99}]`},
+		{`$..book[2].author`, `["Herman Melville"]`},
+		{`$..book[2].publisher`, `[]`},
+		{`$..book[-1].title`, `["The Lord of the Rings"]`},
+		{`$..book[0,1].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$..book[:2].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$..book[?@.isbn].title`, `["Moby Dick","The Lord of the Rings"]`},
+		{`$..book[?@.price<10].title`, `["Sayings of the Century","Moby Dick"]`},
+		{`$..book[?@.price > $.store.bicycle.price].title`, `[]`},
+		{`$..book[?@.category == "fiction" && @.price < 20].title`, `["Sword of Honour","Moby Dick"]`},
+		{`$.store.bicycle[?@ == 'red']`, `["red"]`},
+	} {
+		t.Run(p.path, func(t *testing.T) {
+			for name, v := range map[string]Value{
+				"parsed": Parse([]byte(jsonPathBookstore)),
+				"raw":    Raw(json.RawMessage(jsonPathBookstore)),
+				"lazy":   LazyRaw(json.RawMessage(jsonPathBookstore)),
+			} {
+				t.Run(name, func(t *testing.T) {
+					jp, err := ParseJSONPath(p.path)
+					require.NoError(t, err)
+					expected := Parse([]byte(p.expected))
+					assert.True(t, expected.Equal(jp.Query(v).AsValue()), "got %s", jp.Query(v).JSONString())
+				})
+			}
+		})
+	}
+}
+
+func TestJSONPathRecursiveDescentVisitsAllNodes(t *testing.T) {
+	v := Parse([]byte(jsonPathBookstore))
+	p, err := ParseJSONPath(`$..*`)
+	require.NoError(t, err)
+	assert.Equal(t, 27, p.Query(v).Count())
+}
+
+func TestJSONPathNormalizedPaths(t *testing.T) {
+	v := Parse([]byte(`{"a":[{"b":1},{"b":2,"c'd":3}],"e\\f\n\u0001":4}`))
+	for _, p := range []struct {
+		path          string
+		expectedPaths []string
+	}{
+		{`$`, []string{`$`}},
+		{`$.a[*].b`, []string{`$['a'][0]['b']`, `$['a'][1]['b']`}},
+		{`$.a[-1]`, []string{`$['a'][1]`}},
+		{`$.a[1]["c'd"]`, []string{`$['a'][1]['c\'d']`}},
+		{`$.*`, []string{`$['a']`, `$['e\\f\n\u0001']`}},
+		{`$..[?@ > 1]`, []string{`$['e\\f\n\u0001']`, `$['a'][1]['b']`, `$['a'][1]['c\'d']`}},
+		{`$.x`, []string{}},
+	} {
+		t.Run(p.path, func(t *testing.T) {
+			jp, err := ParseJSONPath(p.path)
+			require.NoError(t, err)
+			values, paths := jp.QueryWithPaths(v)
+			assert.Equal(t, p.expectedPaths, paths)
+			require.Equal(t, len(paths), values.Count())
+			for i, path := range paths {
+				// each normalized path is itself a valid query that selects the same value
+				np, err := ParseJSONPath(path)
+				require.NoError(t, err)
+				assert.Equal(t, ValueArrayOf(values.Get(i)), np.Query(v))
+			}
+		})
+	}
+}
+
+func TestJSONPathSelectors(t *testing.T) {
+	v := Parse([]byte(`{"o":{"j":1,"k":2},"a":[5,3,[{"j":4},{"k":6}]],"s":"x","n":null,"":0,"\u00e9":7}`))
+	arr := Parse([]byte(`["a","b","c","d","e","f","g"]`))
+	for _, p := range []struct {
+		path     string
+		value    Value
+		expected string
+	}{
+		{`$.o['j']`, v, `[1]`},
+		{`$.o["j"]`, v, `[1]`},
+		{`$.o[ 'j' , "k" ]`, v, `[1,2]`},
+		{`$.o['j', 'j']`, v, `[1,1]`},
+		{`$['o'] ['k']`, v, `[2]`},
+		{`$['']`, v, `[0]`},
+		{`$.` + "\u00e9", v, `[7]`},
+		{`$['` + "\u00e9" + `']`, v, `[7]`},
+		{`$.n`, v, `[null]`},
+		{`$.s.x`, v, `[]`},
+		{`$.s[0]`, v, `[]`},
+		{`$.a.x`, v, `[]`},
+		{`$.o[*]`, v, `[1,2]`},
+		{`$.o.*`, v, `[1,2]`},
+		{`$.s.*`, v, `[]`},
+		{`$.a[0]`, v, `[5]`},
+		{`$.a[-3]`, v, `[5]`},
+		{`$.a[3]`, v, `[]`},
+		{`$.a[-4]`, v, `[]`},
+		{`$.o[0]`, v, `[]`},
+		{`$..j`, v, `[4,1]`},
+		{`$..[0]`, v, `[5,{"j":4}]`},
+		{`$.a..*`, v, `[5,3,[{"j":4},{"k":6}],{"j":4},{"k":6},4,6]`},
+		{`$[1:3]`, arr, `["b","c"]`},
+		{`$[5:]`, arr, `["f","g"]`},
+		{`$[1:5:2]`, arr, `["b","d"]`},
+		{`$[5:1:-2]`, arr, `["f","d"]`},
+		{`$[::-1]`, arr, `["g","f","e","d","c","b","a"]`},
+		{`$[:]`, arr, `["a","b","c","d","e","f","g"]`},
+		{`$[-2:]`, arr, `["f","g"]`},
+		{`$[:-5]`, arr, `["a","b"]`},
+		{`$[1:3:0]`, arr, `[]`},
+		{`$[-100:100:3]`, arr, `["a","d","g"]`},
+		{`$[100:-100:-3]`, arr, `["g","d","a"]`},
+		{`$[ 1 : 3 : 1 ]`, arr, `["b","c"]`},
+		{`$[0, 0, 1:2]`, arr, `["a","a","b"]`},
+		{`$[1:3]`, v, `[]`},
+	} {
+		t.Run(p.path, func(t *testing.T) {
+			assert.Equal(t, p.expected, jsonPathQueryJSON(t, p.path, p.value))
+		})
+	}
+}
+
+func TestJSONPathFilters(t *testing.T) {
+	v := ParseLossless([]byte(`{
+		"a": [3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}],
+		"o": {"p": 1, "q": 2, "r": 3, "s": 5, "t": {"u": 6}},
+		"e": [{"k": null}, {"k": false}, {}, {"k": [1]}, {"k": {"x": 1}}],
+		"n": [1, 1.0, 9007199254740993, 9007199254740992, "1", true],
+		"t": [{"tags": ["a", "b"]}, {"tags": ["a"]}, {"tags": "ab"}],
+		"r": 2
+	}`))
+	for _, p := range []struct {
+		path     string
+		expected string
+	}{
+		{`$.a[?@.b == 'kilo']`, `[{"b":"kilo"}]`},
+		{`$.a[?(@.b == 'kilo')]`, `[{"b":"kilo"}]`},
+		{`$.a[?@>3.5]`, `[5,4,6]`},
+		{`$.a[?@.b]`, `[{"b":"j"},{"b":"k"},{"b":{}},{"b":"kilo"}]`},
+		{`$[?@.*]`, `[[3,5,1,2,4,6,{"b":"j"},{"b":"k"},{"b":{}},{"b":"kilo"}],` +
+			`[{"k":null},{"k":false},{},{"k":[1]},{"k":{"x":1}}],[1,1.0,9007199254740993,9007199254740992,"1",true],` +
+			`{"p":1,"q":2,"r":3,"s":5,"t":{"u":6}},[{"tags":["a","b"]},{"tags":["a"]},{"tags":"ab"}]]`},
+		{`$[?@[?@.b]]`, `[[3,5,1,2,4,6,{"b":"j"},{"b":"k"},{"b":{}},{"b":"kilo"}]]`},
+		{`$.o[?@<3, ?@<3]`, `[1,2,1,2]`},
+		{`$.a[?@<2 || @.b == "k"]`, `[1,{"b":"k"}]`},
+		{`$.a[?match(@.b, "[jk]")]`, `[{"b":"j"},{"b":"k"}]`},
+		{`$.a[?search(@.b, "[jk]")]`, `[{"b":"j"},{"b":"k"},{"b":"kilo"}]`},
+		{`$.o[?@>1 && @<4]`, `[2,3]`},
+		{`$.o[?@.u || @.x]`, `[{"u":6}]`},
+		{`$.a[?@.b == $.x]`, `[3,5,1,2,4,6]`},
+		{`$.a[?@ == @]`, `[3,5,1,2,4,6,{"b":"j"},{"b":"k"},{"b":{}},{"b":"kilo"}]`},
+		{`$.a[?@ == $.r]`, `[2]`},
+		{`$.a[?!@.b]`, `[3,5,1,2,4,6]`},
+		{`$.a[?!(@ >= 2)]`, `[1,{"b":"j"},{"b":"k"},{"b":{}},{"b":"kilo"}]`},
+		{`$.a[?@.b > "j"]`, `[{"b":"k"},{"b":"kilo"}]`},
+		{`$.a[?@.b <= "k"]`, `[{"b":"j"},{"b":"k"}]`},
+		{`$.a[?@.b != "k"]`, `[3,5,1,2,4,6,{"b":"j"},{"b":{}},{"b":"kilo"}]`},
+		{`$.e[?@.k == null]`, `[{"k":null}]`},
+		{`$.e[?@.k == false]`, `[{"k":false}]`},
+		{`$.e[?@.k]`, `[{"k":null},{"k":false},{"k":[1]},{"k":{"x":1}}]`},
+		{`$.e[?@.k == $.e[3].k]`, `[{"k":[1]}]`},
+		{`$.e[?@.k == $.e[4].k]`, `[{"k":{"x":1}}]`},
+		{`$.e[?@.k < $.e[3].k]`, `[]`},
+		{`$.e[?@.k <= $.e[3].k]`, `[{"k":[1]}]`},
+		{`$.n[?@ == 1]`, `[1,1.0]`},
+		{`$.n[?@ == 1.0e0]`, `[1,1.0]`},
+		{`$.n[?@ == 9007199254740993]`, `[9007199254740993]`},
+		{`$.n[?@ < 9007199254740993]`, `[1,1.0,9007199254740992]`},
+		{`$.n[?@ > -1e400]`, `[1,1.0,9007199254740993,9007199254740992]`},
+		{`$.n[?@ == -0]`, `[]`},
+		{`$.t[?length(@.tags) == 2]`, `[{"tags":["a","b"]},{"tags":"ab"}]`},
+		{`$.t[?count(@.tags[*]) == 1]`, `[{"tags":["a"]}]`},
+		{`$.t[?count(@..*) > 2]`, `[{"tags":["a","b"]}]`},
+		{`$.t[?value(@.tags[0]) == "a"]`, `[{"tags":["a","b"]},{"tags":["a"]}]`},
+		{`$.t[?value(@..*) == "ab"]`, `[{"tags":"ab"}]`},
+		{`$.o[?length(@) == 1]`, `[{"u":6}]`},
+		{`$.o[?length(@) > 0]`, `[{"u":6}]`},
+		{`$.t[?length(@.missing) == length(@.other)]`, `[{"tags":["a","b"]},{"tags":["a"]},{"tags":"ab"}]`},
+		{`$.t[?match(@.tags, 'ab')]`, `[{"tags":"ab"}]`},
+		{`$.t[?match(@.tags, @.tags)]`, `[{"tags":"ab"}]`},
+		{`$.t[?match(@.tags, 1)]`, `[]`},
+		{`$.t[?match(@.tags, '(')]`, `[]`},
+		{`$.t[?search(@.tags, '^a')]`, `[]`},
+		{`$.t[?search(@.tags, 'a|x')]`, `[{"tags":"ab"}]`},
+		{`$.t[?match(@.tags, "a.")]`, `[{"tags":"ab"}]`},
+		{`$.t[? search( @.tags , "a" ) ]`, `[{"tags":"ab"}]`},
+	} {
+		t.Run(p.path, func(t *testing.T) {
+			jp, err := ParseJSONPath(p.path)
+			require.NoError(t, err)
+			assert.True(t, ParseLossless([]byte(p.expected)).Equal(jp.Query(v).AsValue()), "got %s", jp.Query(v).JSONString())
+		})
+	}
+}
+
+func TestJSONPathRegularExpressionsUseIRegexpDot(t *testing.T) {
+	v := ArrayOf(String("a\nb"), String("a\rb"), String("a.b"), String("axb"), String("a^b"))
+	assert.Equal(t, `["a.b","axb","a^b"]`, jsonPathQueryJSON(t, `$[?match(@, "a.b")]`, v))
+	assert.Equal(t, `["a.b"]`, jsonPathQueryJSON(t, `$[?match(@, "a\\.b")]`, v))
+	assert.Equal(t, `["a.b"]`, jsonPathQueryJSON(t, `$[?match(@, "a[.]b")]`, v))
+	assert.Equal(t, `["a^b"]`, jsonPathQueryJSON(t, `$[?search(@, "a^")]`, v))
+	assert.Equal(t, `["a\nb","a\rb","a.b","axb"]`, jsonPathQueryJSON(t, `$[?match(@, "a[^^]b")]`, v))
+}
+
+func TestJSONPathStringLiterals(t *testing.T) {
+	v := Parse([]byte(`{"a\"b":1,"a'b":2,"\u263a":3,"` + "\U0001f600" + `":4,"\t/\\":5}`))
+	for _, p := range []struct {
+		path     string
+		expected string
+	}{
+		{`$["a\"b"]`, `[1]`},
+		{`$['a"b']`, `[1]`},
+		{`$['a\'b']`, `[2]`},
+		{`$["a'b"]`, `[2]`},
+		{`$["\u263a"]`, `[3]`},
+		{`$["` + "\U0001f600" + `"]`, `[4]`},
+		{`$["\t\/\\"]`, `[5]`},
+		{`$[?@ == "` + "\u263a" + `"]`, `[]`},
+	} {
+		t.Run(p.path, func(t *testing.T) {
+			assert.Equal(t, p.expected, jsonPathQueryJSON(t, p.path, v))
+		})
+	}
+}
+
+func TestJSONPathSyntaxErrors(t *testing.T) {
+	for _, p := range []struct {
+		path     string
+		position int
+	}{
+		{``, 0},
+		{`x`, 0},
+		{` $`, 0},
+		{`$ `, 1},
+		{`$.`, 2},
+		{`$..`, 3},
+		{`$. a`, 2},
+		{`$.1`, 2},
+		{`$[`, 2},
+		{`$[]`, 2},
+		{`$[1`, 3},
+		{`$[1,]`, 4},
+		{`$['a`, 2},
+		{`$['\x']`, 3},
+		{`$['\"']`, 3},
+		{`$["\ud83d"]`, 3},
+		{`$["\ude00"]`, 3},
+		{"$['\x01']", 3},
+		{"$['\xff']", 3},
+		{`$[01]`, 2},
+		{`$[-0]`, 2},
+		{`$[9007199254740992]`, 2},
+		{`$[-9007199254740992]`, 2},
+		{`$[1:2:3:4]`, 7},
+		{`$[a]`, 2},
+		{`$[?]`, 3},
+		{`$[?1]`, 3},
+		{`$[?true]`, 3},
+		{`$[?'a']`, 3},
+		{`$[?@.a == 1 == 2]`, 12},
+		{`$[?@ = 1]`, 5},
+		{`$[?@.* == 1]`, 3},
+		{`$[?@..a == 1]`, 3},
+		{`$[?@[0,1] == 1]`, 3},
+		{`$[?1 == @[*]]`, 8},
+		{`$[?(@.a == 1]`, 12},
+		{`$[?!1]`, 4},
+		{`$[?!@.a == 1]`, 8},
+		{`$[?@ == 01]`, 8},
+		{`$[?@ == 1.]`, 10},
+		{`$[?@ == 1e]`, 10},
+		{`$[?@ == {}]`, 8},
+		{`$[?@ == True]`, 8},
+		{`$[?@ == nulls]`, 8},
+		{`$[?foo(@)]`, 3},
+		{`$[?length(@)]`, 3},
+		{`$[?length(@.*) > 1]`, 10},
+		{`$[?length(@) == length]`, 16},
+		{`$[?length(@, @) > 1]`, 13},
+		{`$[?length() > 1]`, 3},
+		{`$[?count(1) > 1]`, 9},
+		{`$[?count(@) == count(@.a) == 1]`, 26},
+		{`$[?match(@, "a") == true]`, 3},
+		{`$[?length(@ == 1) > 0]`, 10},
+		{`$[?value(@) && true]`, 3},
+		{`$[?length (@) > 0]`, 3},
+	} {
+		t.Run(p.path, func(t *testing.T) {
+			_, err := ParseJSONPath(p.path)
+			require.Error(t, err)
+			require.IsType(t, lderrors.ErrValueJSONPathSyntax{}, err)
+			assert.Equal(t, p.position, err.(lderrors.ErrValueJSONPathSyntax).Position, "message: %s", err)
+		})
+	}
+}
+
+func TestJSONPathDeeplyNestedExpression(t *testing.T) {
+	path := `$[?`
+	for i := 0; i < 2000; i++ {
+		path += "("
+	}
+	_, err := ParseJSONPath(path + "@")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nested too deeply")
+}
+
+func TestJSONPathString(t *testing.T) {
+	p, err := ParseJSONPath(`$.a[?@ > 1]`)
+	require.NoError(t, err)
+	assert.Equal(t, `$.a[?@ > 1]`, p.String())
+	assert.Equal(t, "", JSONPath{}.String())
+}
+
+func TestUninitializedJSONPath(t *testing.T) {
+	values, paths := JSONPath{}.QueryWithPaths(Int(1))
+	assert.Equal(t, "[]", values.JSONString())
+	assert.Nil(t, paths)
+	assert.Equal(t, "[]", JSONPath{}.Query(Int(1)).JSONString())
+}
+
+func TestJSONPathOverCollectionsWithSharedStructure(t *testing.T) {
+	m := ValueMapBuild().Set("a", Int(1)).Build().With("b", ArrayOf(Int(2)).AsValueArray().Append(Int(3)).AsValue())
+	assert.Equal(t, `[1,2,3]`, jsonPathQueryJSON(t, `$..[?@ > 0]`, m.AsValue()))
+	assert.Equal(t, `[3]`, jsonPathQueryJSON(t, `$.b[-1]`, m.AsValue()))
+}
+
+func TestJSONPathOverNestedRawValue(t *testing.T) {
+	v := ObjectBuild().Set("a", Raw(json.RawMessage(`{"b":[1,2]}`))).Set("c", Raw(json.RawMessage(`3`))).Build()
+	assert.Equal(t, `[2]`, jsonPathQueryJSON(t, `$.a.b[1]`, v))
+	assert.Equal(t, `[{"b":[1,2]}]`, jsonPathQueryJSON(t, `$[?@.b[0] == 1]`, v))
+	assert.Equal(t, `[3]`, jsonPathQueryJSON(t, `$[?@ == 3]`, v))
+}
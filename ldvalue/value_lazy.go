@@ -0,0 +1,288 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/slices"
+)
+
+// LazyRaw creates an unparsed JSON Value that can be examined efficiently without parsing all of it.
+//
+// Like [Raw], this stores a copy of the JSON data, and the Value has the type [RawType]; it is
+// written to JSON output exactly as is. The difference is that LazyRaw scans the data once to
+// build an index of where each array element and object property is. Then, [Value.Count],
+// [Value.GetByIndex], [Value.TryGetByIndex], [Value.GetByKey], [Value.TryGetByKey], and
+// [Value.Keys] use the index instead of parsing the whole value, so only the parts of the data that
+// are actually accessed are parsed. This is useful for a large JSON document of which only a few
+// properties are typically needed.
+//
+// An element or property that is an array or object is returned as another Value of this kind,
+// which shares the same index; any other element or property is parsed and returned as a regular
+// Value. Any other operation that needs to inspect a Value of this kind, such as [Value.Equal] or
+// [Value.AsValueMap], parses it in the same way as for [Raw]. If an object has duplicate keys, the
+// last one wins, just as it does for [Parse].
+//
+// Unlike Raw, LazyRaw validates the JSON syntax. If the data is not valid JSON, the result is the
+// same as calling Raw. If the data is valid but is not an array or object, the result is the same
+// as calling [Parse].
+func LazyRaw(value json.RawMessage) Value {
+	if len(value) == 0 || len(value) > math.MaxInt32 {
+		return Raw(value)
+	}
+	data := slices.Clone(value)
+	index := &lazyIndex{data: data}
+	s := lazyScanner{jsonScanner: jsonScanner{data: data}, index: index}
+	s.skipWhitespace()
+	start := s.pos
+	node, ok := s.scanValue(0)
+	end := s.pos
+	if s.skipWhitespace(); !ok || s.pos != len(data) {
+		return Value{valueType: uint8(RawType), rawValue: data}
+	}
+	if node < 0 {
+		return Parse(data)
+	}
+	return index.containerValue(node, int32(start), int32(end))
+}
+
+// lazyIndex is the index that is shared by every Value created by one call to LazyRaw. It is built
+// by a single scan of the data, and is never modified afterward.
+type lazyIndex struct {
+	data     []byte
+	keyData  []byte // the unescaped form of every object key that contains escape sequences
+	nodes    []lazyNode
+	entries  []lazyEntry
+	keyOrder []int32 // for each object, the positions of its entries sorted by key
+}
+
+// lazyNode describes an array or object within the data. If it is an object, firstKey is where its
+// entries start in lazyIndex.keyOrder.
+type lazyNode struct {
+	index      *lazyIndex
+	isObject   bool
+	firstEntry int32
+	entryCount int32
+	firstKey   int32
+}
+
+// lazyEntry describes an array element or an object property. The key offsets, which are only used
+// for an object property, do not include the quotes; if keyEscaped is true, they are offsets into
+// lazyIndex.keyData rather than the data. If the value is an array or an object, node is its index
+// in lazyIndex.nodes; otherwise it is -1.
+type lazyEntry struct {
+	keyStart, keyEnd     int32
+	valueStart, valueEnd int32
+	node                 int32
+	keyEscaped           bool
+}
+
+// lazyDuplicate is a value of lazyEntry.node that marks an object property that is being removed
+// because a later property has the same key.
+const lazyDuplicate = -2
+
+func (x *lazyIndex) containerValue(node, start, end int32) Value {
+	return Value{valueType: uint8(RawType), rawValue: x.data[start:end:end], ref: &x.nodes[node]}
+}
+
+func (x *lazyIndex) key(e *lazyEntry) []byte {
+	if e.keyEscaped {
+		return x.keyData[e.keyStart:e.keyEnd]
+	}
+	return x.data[e.keyStart:e.keyEnd]
+}
+
+func (n *lazyNode) entry(i int) *lazyEntry {
+	return &n.index.entries[int(n.firstEntry)+i]
+}
+
+func (n *lazyNode) entryValue(e *lazyEntry) Value {
+	if e.node >= 0 {
+		return n.index.containerValue(e.node, e.valueStart, e.valueEnd)
+	}
+	return Parse(n.index.data[e.valueStart:e.valueEnd])
+}
+
+func (n *lazyNode) tryGetByIndex(index int) (Value, bool) {
+	if n.isObject || index < 0 || index >= int(n.entryCount) {
+		return Null(), false
+	}
+	return n.entryValue(n.entry(index)), true
+}
+
+func (n *lazyNode) tryGetByKey(name string) (Value, bool) {
+	if !n.isObject {
+		return Null(), false
+	}
+	order := n.index.keyOrder[n.firstKey : n.firstKey+n.entryCount]
+	i := sort.Search(len(order), func(i int) bool {
+		return string(n.index.key(n.entry(int(order[i])))) >= name
+	})
+	if i < len(order) {
+		if e := n.entry(int(order[i])); string(n.index.key(e)) == name {
+			return n.entryValue(e), true
+		}
+	}
+	return Null(), false
+}
+
+func (n *lazyNode) keys(sliceIn []string) []string {
+	if !n.isObject || n.entryCount == 0 {
+		return nil
+	}
+	ret := sliceIn[0:0]
+	if cap(ret) < int(n.entryCount) {
+		ret = make([]string, 0, n.entryCount)
+	}
+	for i := 0; i < int(n.entryCount); i++ {
+		ret = append(ret, string(n.index.key(n.entry(i))))
+	}
+	return ret
+}
+
+// lazyScanner builds a lazyIndex. It validates the JSON syntax as it goes, but the only data it
+// collects is the offsets of array elements and object properties.
+type lazyScanner struct {
+	jsonScanner
+	index   *lazyIndex
+	pending []lazyEntry // entries of the containers that are currently being scanned
+}
+
+// scanValue scans the value at the current position. If it is an array or object, the return value
+// is its index in lazyIndex.nodes; otherwise it is -1. The second return value is false if the value
+// is not valid JSON.
+func (s *lazyScanner) scanValue(depth int) (int32, bool) {
+	if s.pos >= len(s.data) {
+		return -1, false
+	}
+	switch ch := s.data[s.pos]; {
+	case ch == '[' || ch == '{':
+		if depth >= maxJSONDepth {
+			return -1, false
+		}
+		return s.scanContainer(depth, ch == '{')
+	case ch == '"':
+		_, ok := s.scanString()
+		return -1, ok
+	case ch == '-' || isDigit(ch):
+		return -1, s.scanNumber()
+	case ch == 't':
+		return -1, s.scanLiteral(trueString)
+	case ch == 'f':
+		return -1, s.scanLiteral(falseString)
+	case ch == 'n':
+		return -1, s.scanLiteral(nullAsJSON)
+	default:
+		return -1, false
+	}
+}
+
+func (s *lazyScanner) scanContainer(depth int, isObject bool) (int32, bool) {
+	closing := byte(']')
+	if isObject {
+		closing = '}'
+	}
+	node := int32(len(s.index.nodes))
+	s.index.nodes = append(s.index.nodes, lazyNode{index: s.index, isObject: isObject})
+	mark := len(s.pending)
+	s.pos++
+	s.skipWhitespace()
+	if s.pos < len(s.data) && s.data[s.pos] == closing {
+		s.pos++
+		return node, true
+	}
+	for {
+		var e lazyEntry
+		if isObject {
+			if s.pos >= len(s.data) || s.data[s.pos] != '"' {
+				return -1, false
+			}
+			keyStart := s.pos
+			escaped, ok := s.scanString()
+			if !ok {
+				return -1, false
+			}
+			e.keyStart, e.keyEnd, e.keyEscaped = int32(keyStart+1), int32(s.pos-1), escaped
+			if escaped {
+				var key string
+				_ = json.Unmarshal(s.data[keyStart:s.pos], &key) // we already know it is a valid string
+				e.keyStart = int32(len(s.index.keyData))
+				s.index.keyData = append(s.index.keyData, key...)
+				e.keyEnd = int32(len(s.index.keyData))
+			}
+			s.skipWhitespace()
+			if s.pos >= len(s.data) || s.data[s.pos] != ':' {
+				return -1, false
+			}
+			s.pos++
+			s.skipWhitespace()
+		}
+		e.valueStart = int32(s.pos)
+		child, ok := s.scanValue(depth + 1)
+		if !ok {
+			return -1, false
+		}
+		e.valueEnd, e.node = int32(s.pos), child
+		s.pending = append(s.pending, e)
+		s.skipWhitespace()
+		if s.pos >= len(s.data) {
+			return -1, false
+		}
+		ch := s.data[s.pos]
+		s.pos++
+		if ch == closing {
+			break
+		}
+		if ch != ',' {
+			return -1, false
+		}
+		s.skipWhitespace()
+	}
+	// The entries of any nested containers have already been moved out of s.pending, so the entries
+	// of this container are contiguous.
+	entries := s.pending[mark:]
+	n := &s.index.nodes[node]
+	if isObject {
+		n.firstKey = int32(len(s.index.keyOrder))
+		entries = s.sortKeys(entries)
+	}
+	n.firstEntry = int32(len(s.index.entries))
+	n.entryCount = int32(len(entries))
+	s.index.entries = append(s.index.entries, entries...)
+	s.pending = s.pending[:mark]
+	return node, true
+}
+
+// sortKeys appends the positions of an object's entries, sorted by key, to lazyIndex.keyOrder. If
+// there are duplicate keys, it first removes all but the last of those entries, since the last one
+// wins when we parse an object; the entries that remain are returned.
+func (s *lazyScanner) sortKeys(entries []lazyEntry) []lazyEntry {
+	x := s.index
+	start := len(x.keyOrder)
+	for i := range entries {
+		x.keyOrder = append(x.keyOrder, int32(i))
+	}
+	order := x.keyOrder[start:]
+	slices.SortStableFunc(order, func(a, b int32) bool {
+		return string(x.key(&entries[a])) < string(x.key(&entries[b]))
+	})
+	hasDuplicates := false
+	for i := 1; i < len(order); i++ {
+		if string(x.key(&entries[order[i-1]])) == string(x.key(&entries[order[i]])) {
+			entries[order[i-1]].node = lazyDuplicate // the sort is stable, so this is the earlier one
+			hasDuplicates = true
+		}
+	}
+	if !hasDuplicates {
+		return entries
+	}
+	remaining := entries[:0]
+	for _, e := range entries {
+		if e.node != lazyDuplicate {
+			remaining = append(remaining, e)
+		}
+	}
+	x.keyOrder = x.keyOrder[:start]
+	return s.sortKeys(remaining)
+}
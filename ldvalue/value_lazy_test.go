@@ -0,0 +1,187 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lazyTestJSON = `{
+	"name": "x",
+	"nested": {"a": [1, 2.5, {"b": true}], "\u00e9": null, "s": "q\"\\é"},
+	"dup": 1, "dup": 2,
+	"empty": [], "emptyObj": {}
+}`
+
+func TestLazyRawIsRawType(t *testing.T) {
+	v := LazyRaw(json.RawMessage(lazyTestJSON))
+	assert.Equal(t, RawType, v.Type())
+	assert.Equal(t, lazyTestJSON, string(v.AsRaw()))
+	assert.Equal(t, Raw(json.RawMessage(lazyTestJSON)).JSONString(), v.JSONString())
+	bytes, err := json.Marshal(v)
+	require.NoError(t, err)
+	expected, _ := json.Marshal(Raw(json.RawMessage(lazyTestJSON)))
+	assert.Equal(t, string(expected), string(bytes))
+
+	assert.False(t, v.IsNull())
+	assert.False(t, v.IsBool())
+	assert.False(t, v.IsNumber())
+	assert.False(t, v.IsInt())
+	assert.False(t, v.IsString())
+}
+
+func TestLazyRawCopiesData(t *testing.T) {
+	data := []byte(`{"a": "b"}`)
+	v := LazyRaw(data)
+	data[7] = 'x'
+	assert.Equal(t, String("b"), v.GetByKey("a"))
+	assert.Equal(t, `{"a": "b"}`, string(v.AsRaw()))
+}
+
+func TestLazyRawGetByKey(t *testing.T) {
+	v := LazyRaw(json.RawMessage(lazyTestJSON))
+	assert.Equal(t, String("x"), v.GetByKey("name"))
+	assert.Equal(t, Int(2), v.GetByKey("dup"))
+
+	nested, ok := v.TryGetByKey("nested")
+	require.True(t, ok)
+	assert.Equal(t, RawType, nested.Type())
+	assert.Equal(t, `{"a": [1, 2.5, {"b": true}], "\u00e9": null, "s": "q\"\\é"}`, string(nested.AsRaw()))
+
+	value, ok := nested.TryGetByKey("\u00e9")
+	assert.True(t, ok)
+	assert.Equal(t, Null(), value)
+	assert.Equal(t, String("q\"\\é"), nested.GetByKey("s"))
+	assert.Equal(t, Bool(true), nested.GetByKey("a").GetByIndex(2).GetByKey("b"))
+
+	value, ok = v.TryGetByKey("missing")
+	assert.False(t, ok)
+	assert.Equal(t, Null(), value)
+	value, ok = v.GetByKey("nested").GetByKey("a").TryGetByKey("name")
+	assert.False(t, ok)
+	assert.Equal(t, Null(), value)
+}
+
+func TestLazyRawGetByIndex(t *testing.T) {
+	a := LazyRaw(json.RawMessage(lazyTestJSON)).GetByKey("nested").GetByKey("a")
+	assert.Equal(t, 3, a.Count())
+	assert.Equal(t, Int(1), a.GetByIndex(0))
+	assert.Equal(t, Float64(2.5), a.GetByIndex(1))
+	assert.Equal(t, RawType, a.GetByIndex(2).Type())
+	for _, index := range []int{-1, 3} {
+		value, ok := a.TryGetByIndex(index)
+		assert.False(t, ok)
+		assert.Equal(t, Null(), value)
+	}
+
+	value, ok := LazyRaw(json.RawMessage(lazyTestJSON)).TryGetByIndex(0)
+	assert.False(t, ok)
+	assert.Equal(t, Null(), value)
+}
+
+func TestLazyRawCountAndKeys(t *testing.T) {
+	v := LazyRaw(json.RawMessage(lazyTestJSON))
+	assert.Equal(t, 5, v.Count())
+	assert.Equal(t, 0, v.GetByKey("empty").Count())
+	assert.Equal(t, 0, v.GetByKey("emptyObj").Count())
+
+	keys := v.Keys(nil)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"dup", "empty", "emptyObj", "name", "nested"}, keys)
+	assert.Equal(t, []string{"a", "\u00e9", "s"}, v.GetByKey("nested").Keys(nil))
+	assert.Nil(t, v.GetByKey("emptyObj").Keys(nil))
+	assert.Nil(t, v.GetByKey("empty").Keys(nil))
+
+	reused := make([]string, 0, 10)
+	assert.Equal(t, []string{"a", "\u00e9", "s"}, v.GetByKey("nested").Keys(reused))
+	assert.Equal(t, "a", reused[:1][0])
+}
+
+func TestLazyRawDuplicateKeys(t *testing.T) {
+	for _, input := range []string{
+		`{"a": 1, "a": 2}`,
+		`{"a": 1, "b": 3, "a": 2}`,
+		`{"\u0061": 1, "b": 3, "a": 2}`,
+		`{"a": [1], "b": 3, "a": 2, "b": 3}`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			v, parsed := LazyRaw(json.RawMessage(input)), Parse([]byte(input))
+			assert.Equal(t, parsed.Count(), v.Count())
+			keys, parsedKeys := v.Keys(nil), parsed.Keys(nil)
+			sort.Strings(keys)
+			sort.Strings(parsedKeys)
+			assert.Equal(t, parsedKeys, keys)
+			assert.Equal(t, Int(2), v.GetByKey("a"))
+			assert.True(t, v.Equal(parsed))
+		})
+	}
+}
+
+func TestLazyRawGetByKeyInLargeObject(t *testing.T) {
+	ob := ObjectBuild()
+	for i := 0; i < 100; i++ {
+		ob.SetInt(strconv.Itoa(i), i)
+	}
+	v := LazyRaw(json.RawMessage(ob.Build().JSONString()))
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, Int(i), v.GetByKey(strconv.Itoa(i)))
+	}
+	_, ok := v.TryGetByKey("100")
+	assert.False(t, ok)
+	_, ok = v.TryGetByKey("")
+	assert.False(t, ok)
+}
+
+func TestLazyRawIsEquivalentToParse(t *testing.T) {
+	v := LazyRaw(json.RawMessage(lazyTestJSON))
+	parsed := Parse([]byte(lazyTestJSON))
+	assert.True(t, v.Equal(parsed))
+	assert.True(t, parsed.Equal(v))
+	assert.Equal(t, parsed.AsValueMap(), v.AsValueMap())
+	assert.Equal(t, Raw(json.RawMessage(lazyTestJSON)).AsArbitraryValue(), v.AsArbitraryValue())
+	assert.Equal(t, parsed.GetByKey("nested").AsValueMap(), v.GetByKey("nested").AsValueMap())
+}
+
+func TestLazyRawScalarsAreParsed(t *testing.T) {
+	for _, p := range []struct {
+		json     string
+		expected Value
+	}{
+		{"null", Null()},
+		{" true ", Bool(true)},
+		{"false", Bool(false)},
+		{"-1.5e2", Int(-150)},
+		{"9007199254740993", Int64(bigInt64)},
+		{`"a\nb"`, String("a\nb")},
+	} {
+		t.Run(p.json, func(t *testing.T) {
+			assert.Equal(t, p.expected, LazyRaw(json.RawMessage(p.json)))
+		})
+	}
+	assert.Equal(t, Null(), LazyRaw(nil))
+	assert.Equal(t, Null(), LazyRaw(json.RawMessage{}))
+}
+
+func TestLazyRawWithMalformedData(t *testing.T) {
+	for _, s := range []string{
+		"{", "[1,]", "[1 2]", `{"a"}`, `{"a":}`, `{a:1}`, `{"a":1,}`, "[01]", "[1.]", "[tru]", "[nul]",
+		`["\x"]`, `["\u00g0"]`, "[\"\x01\"]", `["abc`, "[1] 2", "{}}",
+		strings.Repeat("[", maxJSONDepth+1) + strings.Repeat("]", maxJSONDepth+1),
+	} {
+		v := LazyRaw(json.RawMessage(s))
+		assert.Equal(t, Raw(json.RawMessage(s)), v, "input: %.20q", s)
+	}
+}
+
+func TestLazyRawSubValuesCannotModifyData(t *testing.T) {
+	v := LazyRaw(json.RawMessage(`{"a":[1],"b":2}`))
+	raw := v.GetByKey("a").AsRaw()
+	_ = append(raw, 'x')
+	assert.Equal(t, Int(2), v.GetByKey("b"))
+	assert.Equal(t, `{"a":[1],"b":2}`, string(v.AsRaw()))
+}
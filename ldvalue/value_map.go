@@ -1,6 +1,9 @@
 package ldvalue
 
 import (
+	"github.com/launchdarkly/go-sdk-common/v3/internal/valuereuse"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+
 	"golang.org/x/exp/maps"
 )
 
@@ -19,8 +22,14 @@ var emptyMap = map[string]Value{} //nolint:gochecknoglobals
 // Like a Go map, there is a distinction between a map in a nil state-- which is the zero value of
 // ValueMap{}-- and a non-nil map that is empty. The former is represented in JSON as a null; the
 // latter is an empty JSON object {}.
+//
+// To create a modified copy of a ValueMap, you can either use [ValueMapBuildFromMap], which copies
+// the whole map, or use [ValueMap.With] and [ValueMap.Without], which are more efficient if you will
+// be making many changes one at a time to a large map.
 type ValueMap struct {
 	data map[string]Value
+	// Used instead of data if the map was created by With or Without.
+	trie *persistentMap
 }
 
 // ValueMapBuilder is a builder created by ValueMapBuild(), for creating immutable JSON objects.
@@ -28,6 +37,7 @@ type ValueMap struct {
 // A ValueMapBuilder should not be accessed by multiple goroutines at once.
 type ValueMapBuilder struct {
 	copyOnWrite bool
+	borrowed    bool // true if output belongs to a ValueMap that was not created by this builder
 	output      map[string]Value
 }
 
@@ -38,7 +48,7 @@ func (b *ValueMapBuilder) Set(key string, value Value) *ValueMapBuilder {
 	}
 	if b.copyOnWrite {
 		b.output = maps.Clone(b.output)
-		b.copyOnWrite = false
+		b.copyOnWrite, b.borrowed = false, false
 	}
 	if b.output == nil {
 		b.output = make(map[string]Value, 1)
@@ -53,12 +63,13 @@ func (b *ValueMapBuilder) SetAllFromValueMap(m ValueMap) *ValueMapBuilder {
 		return b
 	}
 	if b.output == nil {
-		b.output = m.data
-		b.copyOnWrite = true
+		b.output = m.plainData()
+		b.copyOnWrite, b.borrowed = true, true
 	} else {
-		for k, v := range m.data {
+		m.Range(func(k string, v Value) bool {
 			b.Set(k, v)
-		}
+			return true
+		})
 	}
 	return b
 }
@@ -71,13 +82,71 @@ func (b *ValueMapBuilder) Remove(key string) *ValueMapBuilder {
 	if b.output != nil {
 		if b.copyOnWrite {
 			b.output = maps.Clone(b.output)
-			b.copyOnWrite = false
+			b.copyOnWrite, b.borrowed = false, false
 		}
 		delete(b.output, key)
 	}
 	return b
 }
 
+// Get returns the value that has been set for the specified key in the builder, or [Null]() if
+// the key has not been set.
+func (b *ValueMapBuilder) Get(key string) Value {
+	if b == nil {
+		return Null()
+	}
+	return b.output[key]
+}
+
+// TrySetPath sets a value within nested JSON objects in the map builder. The first element of path
+// is a key in the map builder, and each subsequent element is a property name within the object
+// that the previous element refers to.
+//
+// Any intermediate objects that do not exist yet are created. If an intermediate property already
+// exists but its value is not a JSON object, the builder is left unchanged and an error of type
+// [lderrors.ErrValuePathNotObject] is returned. An empty path returns [lderrors.ErrValuePathEmpty].
+func (b *ValueMapBuilder) TrySetPath(path []string, value Value) error {
+	if len(path) == 0 {
+		return lderrors.ErrValuePathEmpty{}
+	}
+	if b == nil {
+		return nil
+	}
+	if len(path) == 1 {
+		b.Set(path[0], value)
+		return nil
+	}
+	newChild, err := setValueAtPath(b.Get(path[0]), path[0], path[1:], value)
+	if err != nil {
+		return err
+	}
+	b.Set(path[0], newChild)
+	return nil
+}
+
+func setValueAtPath(container Value, containerName string, path []string, value Value) (Value, error) {
+	container = container.parseIfRaw()
+	var childBuilder *ValueMapBuilder
+	switch container.Type() {
+	case NullType:
+		childBuilder = ValueMapBuildWithCapacity(1)
+	case ObjectType:
+		childBuilder = ValueMapBuildFromMap(container.objectValue())
+	default:
+		return Null(), lderrors.ErrValuePathNotObject{Property: containerName}
+	}
+	if len(path) == 1 {
+		childBuilder.Set(path[0], value)
+	} else {
+		newChild, err := setValueAtPath(childBuilder.Get(path[0]), path[0], path[1:], value)
+		if err != nil {
+			return Null(), err
+		}
+		childBuilder.Set(path[0], newChild)
+	}
+	return newObjectValue(childBuilder.Build()), nil
+}
+
 // HasKey returns true if the specified key has been set in the builder.
 func (b *ValueMapBuilder) HasKey(key string) bool {
 	_, found := b.output[key]
@@ -91,10 +160,30 @@ func (b *ValueMapBuilder) Build() ValueMap {
 		return ValueMap{}
 	}
 	if b.output == nil {
-		return ValueMap{emptyMap}
+		return ValueMap{data: emptyMap}
 	}
 	b.copyOnWrite = true
-	return ValueMap{b.output}
+	return ValueMap{data: b.output}
+}
+
+// ResetForReuse removes all key-value pairs from the builder, keeping the storage that it allocated
+// so that it can be reused for building another ValueMap. It is only for use by
+// ldcontext.PooledUnmarshaler: its parameter type is internal to this module, so it cannot be called
+// from other modules.
+//
+// Because that storage is shared with the ValueMap that was returned by the last call to Build,
+// that ValueMap is cleared as well and must not be used after calling ResetForReuse. If the
+// builder's contents came from an existing ValueMap that it did not build, via SetAllFromValueMap or
+// ValueMapBuildFromMap, that ValueMap is not affected.
+func (b *ValueMapBuilder) ResetForReuse(valuereuse.Permission) {
+	if b.borrowed {
+		b.output = nil
+	} else {
+		for k := range b.output {
+			delete(b.output, k)
+		}
+	}
+	b.copyOnWrite, b.borrowed = false, false
 }
 
 // ValueMapBuild creates a builder for constructing an immutable ValueMap.
@@ -120,7 +209,7 @@ func ValueMapBuildWithCapacity(capacity int) *ValueMapBuilder {
 // The builder has copy-on-write behavior, so if you make no changes before calling Build(), the
 // original map is used as-is.
 func ValueMapBuildFromMap(m ValueMap) *ValueMapBuilder {
-	return &ValueMapBuilder{output: m.data, copyOnWrite: true}
+	return &ValueMapBuilder{output: m.plainData(), copyOnWrite: true, borrowed: true}
 }
 
 // CopyValueMap copies an existing ordinary map to a ValueMap.
@@ -131,9 +220,9 @@ func CopyValueMap(data map[string]Value) ValueMap {
 		return ValueMap{}
 	}
 	if len(data) == 0 {
-		return ValueMap{emptyMap}
+		return ValueMap{data: emptyMap}
 	}
-	return ValueMap{maps.Clone(data)}
+	return ValueMap{data: maps.Clone(data)}
 }
 
 // CopyArbitraryValueMap copies an existing ordinary map of values of any type to a ValueMap. The
@@ -153,38 +242,94 @@ func CopyArbitraryValueMap(data map[string]any) ValueMap {
 
 // IsDefined returns true if the map is non-nil.
 func (m ValueMap) IsDefined() bool {
-	return m.data != nil
+	return m.data != nil || m.trie != nil
 }
 
 // Count returns the number of keys in the map. For an uninitialized ValueMap{}, this is zero.
 func (m ValueMap) Count() int {
+	if m.trie != nil {
+		return m.trie.count
+	}
 	return len(m.data)
 }
 
 // AsValue converts the ValueMap to a Value which is either Null() or an object. This does not
 // cause any new allocations.
 func (m ValueMap) AsValue() Value {
-	if m.data == nil {
+	if !m.IsDefined() {
 		return Null()
 	}
-	return Value{valueType: ObjectType, objectValue: m}
+	return newObjectValue(m)
 }
 
 // Get gets a value from the map by key.
 //
 // If the key is not found, it returns Null().
 func (m ValueMap) Get(key string) Value {
-	return m.data[key]
+	ret, _ := m.TryGet(key)
+	return ret
 }
 
 // TryGet gets a value from the map by key, with a second return value of true if successful.
 //
 // If the key is not found, it returns (Null(), false).
 func (m ValueMap) TryGet(key string) (Value, bool) {
+	if m.trie != nil {
+		return m.trie.get(key)
+	}
 	ret, ok := m.data[key]
 	return ret, ok
 }
 
+// With returns a ValueMap that is the same as this one, except that the specified key has the
+// specified value. The original ValueMap is unchanged. If this ValueMap is uninitialized, the result
+// is a map containing only that key.
+//
+// Unlike modifying a copy of the map with [ValueMapBuildFromMap], this does not copy the whole map,
+// except the first time it is called on a map that was created in some other way: the result shares
+// most of its data with the original, so each call takes time proportional to the logarithm of the
+// size of the map.
+//
+//	m1 := ldvalue.ValueMapBuild().Set("a", ldvalue.Int(1)).Build()
+//	m2 := m1.With("b", ldvalue.Int(2)) // m1 is still {"a": 1}; m2 is {"a": 1, "b": 2}
+func (m ValueMap) With(key string, value Value) ValueMap {
+	return ValueMap{trie: m.persistent().with(key, value)}
+}
+
+// Without returns a ValueMap that is the same as this one, except that the specified key is not
+// present. The original ValueMap is unchanged. If the key was not present, it returns the same
+// ValueMap.
+//
+// This is efficient for repeated changes in the same way as [ValueMap.With].
+func (m ValueMap) Without(key string) ValueMap {
+	if _, ok := m.TryGet(key); !ok {
+		return m
+	}
+	trie, _ := m.persistent().without(key)
+	return ValueMap{trie: trie}
+}
+
+func (m ValueMap) persistent() *persistentMap {
+	if m.trie != nil {
+		return m.trie
+	}
+	return newPersistentMap(m.data)
+}
+
+// plainData returns the wrapped map if there is one, or else a new map with the same contents. The
+// caller must not modify the result.
+func (m ValueMap) plainData() map[string]Value {
+	if m.trie == nil {
+		return m.data
+	}
+	ret := make(map[string]Value, m.trie.count)
+	m.trie.root.forEach(func(key string, value Value) bool {
+		ret[key] = value
+		return true
+	})
+	return ret
+}
+
 // Keys returns the keys of a the map as a slice.
 //
 // If a non-nil slice is passed in, it will be reused to hold the return values if it has enough capacity.
@@ -192,20 +337,40 @@ func (m ValueMap) TryGet(key string) (Value, bool) {
 //
 // The ordering of the keys is undefined.
 func (m ValueMap) Keys(sliceIn []string) []string {
-	if len(m.data) == 0 {
+	if m.Count() == 0 {
 		return sliceIn
 	}
 	ret := sliceIn[0:0]
-	for key := range m.data {
+	m.Range(func(key string, _ Value) bool {
 		ret = append(ret, key)
-	}
+		return true
+	})
 	return ret
 }
 
+// Range calls fn for each key-value pair in the map, stopping early if fn returns false. The ordering
+// of the keys is undefined. Unlike [ValueMap.Keys], this does not allocate any memory.
+//
+// For an uninitialized ValueMap{}, or a zero-length map, fn is never called.
+func (m ValueMap) Range(fn func(key string, value Value) bool) {
+	if m.trie != nil {
+		m.trie.root.forEach(fn)
+		return
+	}
+	for k, v := range m.data {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
 // AsMap returns a copy of the wrapped data as a simple Go map whose values are of type Value.
 //
 // For an uninitialized ValueMap{}, this returns nil.
 func (m ValueMap) AsMap() map[string]Value {
+	if m.trie != nil {
+		return m.plainData()
+	}
 	return maps.Clone(m.data)
 }
 
@@ -214,13 +379,18 @@ func (m ValueMap) AsMap() map[string]Value {
 //
 // For an uninitialized ValueMap{}, this returns nil.
 func (m ValueMap) AsArbitraryValueMap() map[string]any {
-	if m.data == nil {
+	return m.asArbitraryValueMap(false)
+}
+
+func (m ValueMap) asArbitraryValueMap(exactNumbers bool) map[string]any {
+	if !m.IsDefined() {
 		return nil
 	}
-	ret := make(map[string]any, len(m.data))
-	for k, v := range m.data {
-		ret[k] = v.AsArbitraryValue()
-	}
+	ret := make(map[string]any, m.Count())
+	m.Range(func(k string, v Value) bool {
+		ret[k] = v.asArbitraryValue(exactNumbers)
+		return true
+	})
 	return ret
 }
 
@@ -230,7 +400,19 @@ func (m ValueMap) Equal(other ValueMap) bool {
 	if m.IsDefined() != other.IsDefined() {
 		return false
 	}
-	return maps.EqualFunc(m.data, other.data, Value.Equal)
+	if m.trie == nil && other.trie == nil {
+		return maps.EqualFunc(m.data, other.data, Value.Equal)
+	}
+	if m.Count() != other.Count() {
+		return false
+	}
+	equal := true
+	m.Range(func(k string, v Value) bool {
+		otherValue, ok := other.TryGet(k)
+		equal = ok && v.Equal(otherValue)
+		return equal
+	})
+	return equal
 }
 
 // Transform applies a transformation function to a ValueMap, returning a new ValueMap.
@@ -242,22 +424,22 @@ func (m ValueMap) Equal(other ValueMap) bool {
 // Otherwise, fn is called for each key-value pair. It should return a transformed key-value pair
 // and true, or else return false for the third return value if the property should be dropped.
 func (m ValueMap) Transform(fn func(key string, value Value) (string, Value, bool)) ValueMap {
-	if len(m.data) == 0 {
+	if m.Count() == 0 {
 		return m
 	}
-	ret := m.data
+	var ret map[string]Value
 	startedNewMap := false
-	seenKeys := make([]string, 0, len(m.data))
-	for k, v := range m.data {
+	seenKeys := make([]string, 0, m.Count())
+	m.Range(func(k string, v Value) bool {
 		resultKey, resultValue, ok := fn(k, v)
 		modified := !ok || resultKey != k || !resultValue.Equal(v)
 		if modified && !startedNewMap {
 			// This is the first change we've seen, so we should start building a new map and
 			// retroactively add any values to it that already passed the test without changes.
 			startedNewMap = true
-			ret = make(map[string]Value, len(m.data))
+			ret = make(map[string]Value, m.Count())
 			for _, seenKey := range seenKeys {
-				ret[seenKey] = m.data[seenKey]
+				ret[seenKey] = m.Get(seenKey)
 			}
 		} else {
 			seenKeys = append(seenKeys, k)
@@ -265,8 +447,12 @@ func (m ValueMap) Transform(fn func(key string, value Value) (string, Value, boo
 		if startedNewMap && ok {
 			ret[k] = resultValue
 		}
+		return true
+	})
+	if !startedNewMap {
+		return m
 	}
-	return ValueMap{ret}
+	return ValueMap{data: ret}
 }
 
 func copyArbitraryMapString(data map[string]string) Value {
@@ -275,7 +461,7 @@ func copyArbitraryMapString(data map[string]string) Value {
 		m[k] = String(v)
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapBool(data map[string]bool) Value {
@@ -284,16 +470,16 @@ func copyArbitraryMapBool(data map[string]bool) Value {
 		m[k] = Bool(v)
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapInt(data map[string]int) Value {
 	m := make(map[string]Value, len(data))
 	for k, v := range data {
-		m[k] = Float64(float64(v))
+		m[k] = Int(v)
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapInt8(data map[string]int8) Value {
@@ -302,7 +488,7 @@ func copyArbitraryMapInt8(data map[string]int8) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapInt16(data map[string]int16) Value {
@@ -311,7 +497,7 @@ func copyArbitraryMapInt16(data map[string]int16) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapInt32(data map[string]int32) Value {
@@ -320,25 +506,25 @@ func copyArbitraryMapInt32(data map[string]int32) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapInt64(data map[string]int64) Value {
 	m := make(map[string]Value, len(data))
 	for k, v := range data {
-		m[k] = Float64(float64(v))
+		m[k] = Int64(v)
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapUint(data map[string]uint) Value {
 	m := make(map[string]Value, len(data))
 	for k, v := range data {
-		m[k] = Float64(float64(v))
+		m[k] = Uint64(uint64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapUint8(data map[string]uint8) Value {
@@ -347,7 +533,7 @@ func copyArbitraryMapUint8(data map[string]uint8) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapUint16(data map[string]uint16) Value {
@@ -356,7 +542,7 @@ func copyArbitraryMapUint16(data map[string]uint16) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapUint32(data map[string]uint32) Value {
@@ -365,16 +551,16 @@ func copyArbitraryMapUint32(data map[string]uint32) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapUint64(data map[string]uint64) Value {
 	m := make(map[string]Value, len(data))
 	for k, v := range data {
-		m[k] = Float64(float64(v))
+		m[k] = Uint64(v)
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapFloat32(data map[string]float32) Value {
@@ -383,7 +569,7 @@ func copyArbitraryMapFloat32(data map[string]float32) Value {
 		m[k] = Float64(float64(v))
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
 
 func copyArbitraryMapFloat64(data map[string]float64) Value {
@@ -392,5 +578,5 @@ func copyArbitraryMapFloat64(data map[string]float64) Value {
 		m[k] = Float64(v)
 	}
 
-	return Value{valueType: ObjectType, objectValue: ValueMap{data: m}}
+	return newObjectValue(ValueMap{data: m})
 }
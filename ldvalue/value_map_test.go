@@ -5,6 +5,8 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/launchdarkly/go-sdk-common/v3/internal/valuereuse"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,6 +97,20 @@ func TestValueMapBuilderRemove(t *testing.T) {
 	shouldNotBeSameMap(t, m0.data, m1.data)
 }
 
+func TestValueMapBuilderGet(t *testing.T) {
+	var b ValueMapBuilder
+	assert.Equal(t, Null(), b.Get("key1"))
+
+	b.Set("key1", Int(1))
+	assert.Equal(t, Int(1), b.Get("key1"))
+	assert.Equal(t, Null(), b.Get("key2"))
+
+	b.Remove("key1")
+	assert.Equal(t, Null(), b.Get("key1"))
+
+	assert.Equal(t, Null(), (*ValueMapBuilder)(nil).Get("key1"))
+}
+
 func TestValueMapBuilderHasKey(t *testing.T) {
 	var b ValueMapBuilder
 	assert.False(t, b.HasKey("key1"))
@@ -127,6 +143,32 @@ func TestValueMapBuilderSafety(t *testing.T) {
 	assert.Equal(t, ValueMap{}, nilPtr.Build())
 }
 
+func TestValueMapBuilderReset(t *testing.T) {
+	t.Run("reuses storage of built map", func(t *testing.T) {
+		b := ValueMapBuild().Set("a", Int(1)).Set("b", Int(2))
+		m1 := b.Build()
+		b.ResetForReuse(valuereuse.Permission{})
+		b.Set("c", Int(3))
+		m2 := b.Build()
+		assert.Equal(t, ValueMapBuild().Set("c", Int(3)).Build(), m2)
+		assert.Equal(t, m2, m1) // m1 was invalidated by reset and now shares m2's storage
+	})
+
+	t.Run("does not modify a map that it did not build", func(t *testing.T) {
+		original := ValueMapBuild().Set("a", Int(1)).Build()
+		b := ValueMapBuildFromMap(original)
+		b.ResetForReuse(valuereuse.Permission{})
+		b.Set("b", Int(2))
+		assert.Equal(t, ValueMapBuild().Set("b", Int(2)).Build(), b.Build())
+		assert.Equal(t, ValueMapBuild().Set("a", Int(1)).Build(), original)
+
+		b = ValueMapBuild()
+		b.SetAllFromValueMap(original).ResetForReuse(valuereuse.Permission{})
+		assert.Equal(t, 0, b.Build().Count())
+		assert.Equal(t, 1, original.Count())
+	})
+}
+
 func TestValueMapGetByKey(t *testing.T) {
 	item0 := String("a")
 	item1 := Int(1)
@@ -202,13 +244,35 @@ func TestValueMapKeys(t *testing.T) {
 	assert.Equal(t, []string{"a", "b", "c"}, keys)
 }
 
+func TestValueMapRange(t *testing.T) {
+	ValueMap{}.Range(func(string, Value) bool {
+		assert.Fail(t, "should not have called function")
+		return true
+	})
+
+	m := ValueMapBuild().Set("a", Int(1)).Set("b", Int(2)).Set("c", Int(3)).Build()
+	seen := make(map[string]Value)
+	m.Range(func(key string, value Value) bool {
+		seen[key] = value
+		return true
+	})
+	assert.Equal(t, m.AsMap(), seen)
+
+	count := 0
+	m.Range(func(string, Value) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
 func TestValueMapAsValue(t *testing.T) {
 	assert.Equal(t, Null(), ValueMap{}.AsValue())
 
 	m := ValueMapBuild().Set("a", Int(1)).Set("b", Int(2)).Build()
 	v := m.AsValue()
 	assert.Equal(t, ObjectBuild().Set("a", Int(1)).Set("b", Int(2)).Build(), v)
-	shouldBeSameMap(t, m.data, v.objectValue.data)
+	shouldBeSameMap(t, m.data, v.objectValue().data)
 }
 
 func TestValueMapAsMap(t *testing.T) {
@@ -324,75 +388,75 @@ func TestCopyArbitraryMapOfType(t *testing.T) {
 	mEmpty := map[string]string{}
 	vm = CopyArbitraryValue(mEmpty)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{}, vm.objectValue().data)
 
 	mStr := map[string]string{"a": "1", "b": "2", "c": "3"}
 	vm = CopyArbitraryValue(mStr)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": String("1"), "b": String("2"), "c": String("3")}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": String("1"), "b": String("2"), "c": String("3")}, vm.objectValue().data)
 
 	mBool := map[string]bool{"a": true, "b": false, "c": true}
 	vm = CopyArbitraryValue(mBool)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Bool(true), "b": Bool(false), "c": Bool(true)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Bool(true), "b": Bool(false), "c": Bool(true)}, vm.objectValue().data)
 
 	mInt := map[string]int{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mInt)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mInt8 := map[string]int8{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mInt8)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mInt16 := map[string]int16{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mInt16)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mInt32 := map[string]int32{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mInt32)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mInt64 := map[string]int64{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mInt64)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mUint := map[string]uint{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mUint)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mUint8 := map[string]uint8{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mUint8)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mUint16 := map[string]uint16{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mUint16)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mUint32 := map[string]uint32{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mUint32)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mUint64 := map[string]uint64{"a": 1, "b": 2, "c": 3}
 	vm = CopyArbitraryValue(mUint64)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": Int(2), "c": Int(3)}, vm.objectValue().data)
 
 	mFloat32 := map[string]float32{"a": 1.0, "b": 2.0, "c": 3.0}
 	vm = CopyArbitraryValue(mFloat32)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Float64(1.0), "b": Float64(2.0), "c": Float64(3.0)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Float64(1.0), "b": Float64(2.0), "c": Float64(3.0)}, vm.objectValue().data)
 
 	mFloat64 := map[string]float64{"a": 1.1, "b": 2.2, "c": 3.3}
 	vm = CopyArbitraryValue(mFloat64)
 	assert.Equal(t, ObjectType, vm.Type())
-	assert.Equal(t, map[string]Value{"a": Float64(1.1), "b": Float64(2.2), "c": Float64(3.3)}, vm.objectValue.data)
+	assert.Equal(t, map[string]Value{"a": Float64(1.1), "b": Float64(2.2), "c": Float64(3.3)}, vm.objectValue().data)
 }
@@ -0,0 +1,335 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// This file contains the parts of Value that are specific to numbers.
+//
+// Integers within the range of int64 or uint64 are stored exactly in numberBits, so that large IDs do
+// not lose precision; any float64 that has an integral value within that range is stored the same
+// way, so Int(2) and Float64(2) are exactly the same Value. Any other number is stored as the bits of
+// a float64. A number that was created by Number or ParseLossless, and that is not such an integer,
+// also retains its original JSON representation in stringValue.
+
+// numberKind describes how a numeric Value is stored.
+type numberKind uint8
+
+const (
+	// floatNumber means that the value is math.Float64frombits(numberBits).
+	floatNumber numberKind = iota
+	// intNumber means that the value is int64(numberBits).
+	intNumber
+	// uintNumber means that the value is numberBits, which is greater than math.MaxInt64.
+	uintNumber
+)
+
+// Int64 creates a numeric Value from an int64.
+//
+// The value is stored exactly, even if it cannot be exactly represented as a float64.
+func Int64(value int64) Value {
+	return Value{valueType: uint8(NumberType), numberKind: intNumber, numberBits: uint64(value)}
+}
+
+// Uint64 creates a numeric Value from a uint64.
+//
+// The value is stored exactly, even if it cannot be exactly represented as a float64.
+func Uint64(value uint64) Value {
+	if value <= math.MaxInt64 {
+		return Int64(int64(value))
+	}
+	return Value{valueType: uint8(NumberType), numberKind: uintNumber, numberBits: value}
+}
+
+// Number creates a numeric Value from the text of a JSON number, without losing precision.
+//
+// If the number is an integer within the range of int64 or uint64, such as "123" or "1.5e3", the
+// result is the same as [Int64] or [Uint64]. Otherwise, unless the text is the same as the default
+// representation of the closest float64 (as in "0.5"), the Value retains the original text, which
+// will be used as its JSON representation; [Value.Float64Value] returns the closest float64 to it.
+// This is similar to the behavior of [json.Number] in encoding/json.
+//
+// If the string is not a valid JSON number, the result is [Null]().
+func Number(value json.Number) Value {
+	s := string(value)
+	negative, digits, exponent, ok := parseJSONNumberParts(s)
+	if !ok {
+		return Null()
+	}
+	if v, ok := exactIntegerValue(negative, digits, exponent); ok {
+		return v
+	}
+	f, _ := strconv.ParseFloat(s, 64) // an out-of-range value becomes +/-Inf or 0, which is the best we can do
+	if s == strconv.FormatFloat(f, 'f', -1, 64) {
+		return Float64(f) // the text is the same as the default representation, so there's no need to keep it
+	}
+	return Value{valueType: uint8(NumberType), numberBits: math.Float64bits(f), stringValue: s}
+}
+
+// Int64Value returns the value as an int64.
+//
+// If the value is a number but not an integer, it is rounded toward zero (truncated). The second
+// return value is true if the Value is a number within the range of int64. If it is outside of that
+// range, the result is math.MaxInt64 or math.MinInt64 and the second return value is false; if it
+// is not a number, the result is zero and the second return value is false.
+func (v Value) Int64Value() (int64, bool) {
+	switch v.Type() {
+	case NumberType:
+	case RawType:
+		return v.parseIfRaw().Int64Value()
+	default:
+		return 0, false
+	}
+	switch v.numberKind {
+	case intNumber:
+		return int64(v.numberBits), true
+	case uintNumber:
+		return math.MaxInt64, false
+	}
+	if v.stringValue != "" {
+		return truncateNumberText(v.stringValue).Int64Value()
+	}
+	f := v.numberValue()
+	switch {
+	case f != f: //nolint:gocritic // this is a NaN check
+		return 0, false
+	case f >= math.MaxInt64: // math.MaxInt64 is rounded up to 2^63 here
+		return math.MaxInt64, false
+	case f < math.MinInt64:
+		return math.MinInt64, false
+	}
+	return int64(f), true
+}
+
+// Uint64Value returns the value as a uint64.
+//
+// If the value is a number but not an integer, it is rounded toward zero (truncated). The second
+// return value is true if the Value is a number within the range of uint64. If it is outside of that
+// range, the result is math.MaxUint64 or zero and the second return value is false; if it is not a
+// number, the result is zero and the second return value is false.
+func (v Value) Uint64Value() (uint64, bool) {
+	switch v.Type() {
+	case NumberType:
+	case RawType:
+		return v.parseIfRaw().Uint64Value()
+	default:
+		return 0, false
+	}
+	switch v.numberKind {
+	case intNumber:
+		if int64(v.numberBits) < 0 {
+			return 0, false
+		}
+		return v.numberBits, true
+	case uintNumber:
+		return v.numberBits, true
+	}
+	if v.stringValue != "" {
+		return truncateNumberText(v.stringValue).Uint64Value()
+	}
+	f := v.numberValue()
+	switch {
+	case f != f: //nolint:gocritic // this is a NaN check
+		return 0, false
+	case f >= math.MaxUint64: // math.MaxUint64 is rounded up to 2^64 here
+		return math.MaxUint64, false
+	case f <= -1:
+		return 0, false
+	case f < 0:
+		return 0, true
+	}
+	return uint64(f), true
+}
+
+// JSONNumber returns the JSON representation of a numeric value as a [json.Number].
+//
+// This is the same as [Value.JSONString] for a number. For a value created with [Number] or
+// [ParseLossless], it is the original JSON text unless the value was an integer. If the value is not
+// a number, it returns an empty string.
+func (v Value) JSONNumber() json.Number {
+	switch v.Type() {
+	case NumberType:
+		return json.Number(v.numberJSONString())
+	case RawType:
+		return v.parseIfRaw().JSONNumber()
+	default:
+		return ""
+	}
+}
+
+func (v Value) numberJSONString() string {
+	switch {
+	case v.stringValue != "":
+		return v.stringValue
+	case v.numberKind == intNumber:
+		return strconv.FormatInt(int64(v.numberBits), 10)
+	case v.numberKind == uintNumber:
+		return strconv.FormatUint(v.numberBits, 10)
+	default:
+		return strconv.FormatFloat(v.numberValue(), 'f', -1, 64)
+	}
+}
+
+func (v Value) numberAsExactValue() any {
+	const maxExactFloat64Int = 1 << 53
+	switch {
+	case v.stringValue != "":
+		return json.Number(v.stringValue)
+	case v.numberKind == intNumber:
+		if n := int64(v.numberBits); n > maxExactFloat64Int || n < -maxExactFloat64Int {
+			return n
+		}
+	case v.numberKind == uintNumber:
+		return v.numberBits
+	}
+	return v.numberValue()
+}
+
+// numberEqual compares two numeric Values. Integers within the range of int64 or uint64 are compared
+// exactly; since a float64 with such a value is always stored as an integer, and any other number can
+// never be equal to such an integer, all other numbers are compared as float64 values.
+func (v Value) numberEqual(other Value) bool {
+	if v.numberKind != other.numberKind {
+		return false
+	}
+	if v.numberKind == floatNumber {
+		return v.numberValue() == other.numberValue()
+	}
+	return v.numberBits == other.numberBits
+}
+
+func (v Value) numberIsInt() bool {
+	return v.numberKind != floatNumber
+}
+
+// float64Number is used by Float64 to store integral values exactly.
+func float64Number(value float64) Value {
+	switch {
+	case value >= math.MinInt64 && value < math.MaxInt64 && value == float64(int64(value)):
+		// Note that value < math.MaxInt64 means value < 2^63, since math.MaxInt64 is rounded up here.
+		// A negative zero becomes Int(0).
+		return Int64(int64(value))
+	case value >= math.MaxInt64 && value < math.MaxUint64 && value == float64(uint64(value)):
+		return Uint64(uint64(value))
+	default:
+		return Value{valueType: uint8(NumberType), numberBits: math.Float64bits(value)}
+	}
+}
+
+// numberValue returns the value of a number as a float64. For an integer, this is only an
+// approximation if the integer cannot be exactly represented as a float64.
+func (v Value) numberValue() float64 {
+	switch v.numberKind {
+	case intNumber:
+		return float64(int64(v.numberBits))
+	case uintNumber:
+		return float64(v.numberBits)
+	default:
+		return math.Float64frombits(v.numberBits)
+	}
+}
+
+// parseJSONNumberParts splits a JSON number into its sign, its significant digits, and a decimal
+// exponent, so that the absolute value is digits * 10^exponent. Leading and trailing zeroes are
+// removed from the digits, so a zero value has no digits. The last return value is false if the
+// string is not a valid JSON number.
+func parseJSONNumberParts(s string) (negative bool, digits string, exponent int, ok bool) {
+	pos := 0
+	if pos < len(s) && s[pos] == '-' {
+		negative = true
+		pos++
+	}
+	intStart := pos
+	for pos < len(s) && isDigit(s[pos]) {
+		pos++
+	}
+	intPart := s[intStart:pos]
+	if len(intPart) == 0 || (len(intPart) > 1 && intPart[0] == '0') {
+		return false, "", 0, false
+	}
+	fracPart := ""
+	if pos < len(s) && s[pos] == '.' {
+		pos++
+		fracStart := pos
+		for pos < len(s) && isDigit(s[pos]) {
+			pos++
+		}
+		fracPart = s[fracStart:pos]
+		if len(fracPart) == 0 {
+			return false, "", 0, false
+		}
+	}
+	if pos < len(s) && (s[pos] == 'e' || s[pos] == 'E') {
+		pos++
+		expNegative := false
+		if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+			expNegative = s[pos] == '-'
+			pos++
+		}
+		if pos == len(s) {
+			return false, "", 0, false
+		}
+		for ; pos < len(s) && isDigit(s[pos]); pos++ {
+			if exponent < 1e9 { // beyond this, the exact magnitude no longer matters to us
+				exponent = exponent*10 + int(s[pos]-'0')
+			}
+		}
+		if expNegative {
+			exponent = -exponent
+		}
+	}
+	if pos != len(s) {
+		return false, "", 0, false
+	}
+	exponent -= len(fracPart)
+	digits = strings.TrimLeft(intPart+fracPart, "0")
+	trimmed := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(trimmed)
+	return negative, trimmed, exponent, true
+}
+
+// exactIntegerValue returns an integer Value for the number described by the parameters, if it is
+// an integer within the range of int64 or uint64.
+func exactIntegerValue(negative bool, digits string, exponent int) (Value, bool) {
+	if digits == "" {
+		return Int64(0), true
+	}
+	if exponent < 0 || len(digits)+exponent > 20 { // math.MaxUint64 has 20 digits
+		return Value{}, false
+	}
+	text := digits + strings.Repeat("0", exponent)
+	if negative {
+		if n, err := strconv.ParseInt("-"+text, 10, 64); err == nil {
+			return Int64(n), true
+		}
+		return Value{}, false
+	}
+	if n, err := strconv.ParseUint(text, 10, 64); err == nil {
+		return Uint64(n), true
+	}
+	return Value{}, false
+}
+
+// truncateNumberText returns the integer part of a valid JSON number as a Value. If it is outside of
+// the range of int64 and uint64, the result is the closest float64 instead.
+func truncateNumberText(s string) Value {
+	negative, digits, exponent, _ := parseJSONNumberParts(s)
+	if exponent < 0 {
+		if -exponent >= len(digits) {
+			return Int64(0)
+		}
+		digits, exponent = digits[:len(digits)+exponent], 0
+	}
+	if v, ok := exactIntegerValue(negative, digits, exponent); ok {
+		return v
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return Float64(f)
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
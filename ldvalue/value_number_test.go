@@ -0,0 +1,245 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	maxExactFloat64Int = 1 << 53
+	bigInt64           = maxExactFloat64Int + 1 // 9007199254740993
+)
+
+func TestInt64AndUint64Values(t *testing.T) {
+	for _, p := range []struct {
+		value Value
+		json  string
+	}{
+		{Int64(bigInt64), "9007199254740993"},
+		{Int64(-bigInt64), "-9007199254740993"},
+		{Int64(math.MaxInt64), "9223372036854775807"},
+		{Int64(math.MinInt64), "-9223372036854775808"},
+		{Uint64(math.MaxUint64), "18446744073709551615"},
+		{Uint64(bigInt64), "9007199254740993"},
+	} {
+		t.Run(p.json, func(t *testing.T) {
+			assert.Equal(t, NumberType, p.value.Type())
+			assert.True(t, p.value.IsNumber())
+			assert.True(t, p.value.IsInt())
+			assert.Equal(t, p.json, p.value.JSONString())
+			assert.Equal(t, p.json, string(p.value.JSONNumber()))
+			bytes, err := json.Marshal(p.value)
+			assert.NoError(t, err)
+			assert.Equal(t, p.json, string(bytes))
+			assert.Equal(t, p.json, jsonStreamString(p.value))
+			assert.Equal(t, p.value, Parse([]byte(p.json)))
+		})
+	}
+
+	assert.Equal(t, Int64(5), Uint64(5))
+	assert.Equal(t, float64(bigInt64-1), Int64(bigInt64).Float64Value())
+}
+
+func TestIntegralFloat64IsStoredAsInteger(t *testing.T) {
+	assert.Equal(t, Int(2), Float64(2))
+	assert.Equal(t, Int64(-maxExactFloat64Int), Float64(-maxExactFloat64Int))
+	assert.Equal(t, Int64(math.MinInt64), Float64(math.MinInt64))
+	assert.Equal(t, Uint64(1<<63), Float64(1<<63))
+	assert.True(t, Float64(1<<63).IsInt())
+	assert.False(t, Float64(1<<64).IsInt())
+	assert.False(t, Float64(2.5).IsInt())
+	assert.False(t, Float64(math.NaN()).IsInt())
+	assert.True(t, Float64(math.Copysign(0, -1)).Equal(Int(0)))
+}
+
+func TestNumber(t *testing.T) {
+	for _, p := range []struct {
+		text     string
+		expected Value
+		json     string
+	}{
+		{"123", Int(123), "123"},
+		{"-0", Int(0), "0"},
+		{"0.0", Int(0), "0"},
+		{"1.5e3", Int(1500), "1500"},
+		{"2.50E+1", Int(25), "25"},
+		{"9007199254740993", Int64(bigInt64), "9007199254740993"},
+		{"90071992547409.93e2", Int64(bigInt64), "9007199254740993"},
+		{"18446744073709551615", Uint64(math.MaxUint64), "18446744073709551615"},
+		{"0.5", Float64(0.5), "0.5"},
+		{"-1.25", Float64(-1.25), "-1.25"},
+		{"0.50", Value{valueType: uint8(NumberType), numberBits: math.Float64bits(0.5), stringValue: "0.50"}, "0.50"},
+		{"1e-7", Value{valueType: uint8(NumberType), numberBits: math.Float64bits(1e-7), stringValue: "1e-7"}, "1e-7"},
+		{"18446744073709551616", Value{valueType: uint8(NumberType), numberBits: math.Float64bits(1 << 64),
+			stringValue: "18446744073709551616"}, "18446744073709551616"},
+		{"0.1000000000000000000001", Value{valueType: uint8(NumberType), numberBits: math.Float64bits(0.1),
+			stringValue: "0.1000000000000000000001"}, "0.1000000000000000000001"},
+	} {
+		t.Run(p.text, func(t *testing.T) {
+			v := Number(json.Number(p.text))
+			assert.Equal(t, p.expected, v)
+			assert.Equal(t, p.json, v.JSONString())
+			assert.Equal(t, p.json, jsonStreamString(v))
+		})
+	}
+
+	for _, bad := range []string{"", "-", "01", "1.", ".5", "1e", "1e+", "+1", "0x10", "NaN", "Inf", "1 ", "1_000"} {
+		assert.Equal(t, Null(), Number(json.Number(bad)), "input: %q", bad)
+	}
+}
+
+func TestInt64ValueAndUint64Value(t *testing.T) {
+	for _, p := range []struct {
+		name      string
+		value     Value
+		int64Val  int64
+		int64OK   bool
+		uint64Val uint64
+		uint64OK  bool
+	}{
+		{"small int", Int(3), 3, true, 3, true},
+		{"negative int", Int(-3), -3, true, 0, false},
+		{"large int", Int64(bigInt64), bigInt64, true, bigInt64, true},
+		{"large uint", Uint64(math.MaxUint64), math.MaxInt64, false, math.MaxUint64, true},
+		{"float", Float64(2.75), 2, true, 2, true},
+		{"negative float", Float64(-2.75), -2, true, 0, false},
+		{"negative fraction", Float64(-0.5), 0, true, 0, true},
+		{"float too large", Float64(1e20), math.MaxInt64, false, math.MaxUint64, false},
+		{"float too small", Float64(-1e20), math.MinInt64, false, 0, false},
+		{"NaN", Float64(math.NaN()), 0, false, 0, false},
+		{"lossless fraction", Number("9007199254740993.5"), bigInt64, true, bigInt64, true},
+		{"lossless small fraction", Number("0.0000000000000000001"), 0, true, 0, true},
+		{"lossless too large", Number("1e30"), math.MaxInt64, false, math.MaxUint64, false},
+		{"raw", Raw(json.RawMessage("9007199254740993")), bigInt64, true, bigInt64, true},
+		{"string", String("1"), 0, false, 0, false},
+		{"null", Null(), 0, false, 0, false},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			n, ok := p.value.Int64Value()
+			assert.Equal(t, p.int64Val, n)
+			assert.Equal(t, p.int64OK, ok)
+			u, ok := p.value.Uint64Value()
+			assert.Equal(t, p.uint64Val, u)
+			assert.Equal(t, p.uint64OK, ok)
+		})
+	}
+
+	assert.Equal(t, math.MaxInt, Uint64(math.MaxUint64).IntValue())
+	assert.Equal(t, math.MinInt, Float64(-1e20).IntValue())
+}
+
+func TestNumberEqual(t *testing.T) {
+	equal := [][2]Value{
+		{Int(2), Float64(2)},
+		{Int64(bigInt64), Parse([]byte("9007199254740993"))},
+		{Uint64(1 << 63), Float64(1 << 63)},
+		{Number("0.50"), Float64(0.5)},
+		{Number("0.1000000000000000000001"), Float64(0.1)},
+		{Number("1e400"), Float64(math.Inf(1))},
+	}
+	for _, p := range equal {
+		assert.True(t, p[0].Equal(p[1]), "%s, %s", p[0], p[1])
+		assert.True(t, p[1].Equal(p[0]), "%s, %s", p[1], p[0])
+	}
+	notEqual := [][2]Value{
+		{Int64(bigInt64), Float64(maxExactFloat64Int)},
+		{Int64(bigInt64), Int64(bigInt64 - 1)},
+		{Int64(-1), Uint64(math.MaxUint64)},
+		{Uint64(math.MaxUint64), Float64(math.MaxUint64 - 2047)},
+		{Number("9007199254740993.5"), Int64(bigInt64 + 1)},
+		{Float64(math.NaN()), Float64(math.NaN())},
+		{Int(1), String("1")},
+	}
+	for _, p := range notEqual {
+		assert.False(t, p[0].Equal(p[1]), "%s, %s", p[0], p[1])
+		assert.False(t, p[1].Equal(p[0]), "%s, %s", p[1], p[0])
+	}
+}
+
+func TestParsePreservesIntegers(t *testing.T) {
+	v := Parse([]byte(`{"id": 9007199254740993, "ids": [18446744073709551615, -9007199254740993, 1.5], "s": "x"}`))
+	assert.Equal(t, ObjectBuild().
+		Set("id", Int64(bigInt64)).
+		Set("ids", ArrayOf(Uint64(math.MaxUint64), Int64(-bigInt64), Float64(1.5))).
+		SetString("s", "x").
+		Build(), v)
+
+	assert.Equal(t, Float64(0.1), Parse([]byte(`0.1000000000000000000001`)))
+	assert.Equal(t, Float64(1e20), Parse([]byte(`100000000000000000000`)))
+	assert.Equal(t, String("12345678901234567"), Parse([]byte(`"12345678901234567"`)))
+
+	var v1 Value
+	assert.Error(t, v1.UnmarshalJSON([]byte(`12345678901234567 1`)))
+	assert.Error(t, v1.UnmarshalJSON([]byte(`[12345678901234567`)))
+	assert.Equal(t, Null(), Parse([]byte(`12345678901234567,`)))
+}
+
+func TestParseLossless(t *testing.T) {
+	data := `{"a":[0.1000000000000000000001,1e400,9007199254740993,0.5,1.50]}`
+	v := ParseLossless([]byte(data))
+	assert.Equal(t, ObjectBuild().Set("a", ArrayOf(
+		Number("0.1000000000000000000001"), Number("1e400"), Int64(bigInt64), Float64(0.5), Number("1.50"),
+	)).Build(), v)
+	assert.Equal(t, data, v.JSONString())
+
+	assert.Equal(t, Null(), ParseLossless([]byte(`[1,`)))
+}
+
+func TestCopyArbitraryValuePreservesIntegers(t *testing.T) {
+	big := int64(bigInt64)
+	bigUnsigned := uint64(math.MaxUint64)
+	assert.Equal(t, Int64(bigInt64), CopyArbitraryValue(big))
+	assert.Equal(t, Int64(bigInt64), CopyArbitraryValue(&big))
+	assert.Equal(t, Uint64(math.MaxUint64), CopyArbitraryValue(bigUnsigned))
+	assert.Equal(t, Uint64(math.MaxUint64), CopyArbitraryValue(&bigUnsigned))
+	assert.Equal(t, Int64(bigInt64), CopyArbitraryValue(int(bigInt64)))
+	assert.Equal(t, Uint64(math.MaxUint64), CopyArbitraryValue(uint(math.MaxUint64)))
+	assert.Equal(t, Number("0.50"), CopyArbitraryValue(json.Number("0.50")))
+	assert.Equal(t, ArrayOf(Int64(bigInt64), Int64(-1)), CopyArbitraryValue([]int64{bigInt64, -1}))
+	assert.Equal(t, ArrayOf(Uint64(math.MaxUint64)), CopyArbitraryValue([]uint64{math.MaxUint64}))
+	assert.Equal(t, ObjectBuild().Set("a", Int64(bigInt64)).Build(), CopyArbitraryValue(map[string]int64{"a": bigInt64}))
+	assert.Equal(t, ObjectBuild().Set("a", Uint64(math.MaxUint64)).Build(),
+		CopyArbitraryValue(map[string]uint64{"a": math.MaxUint64}))
+}
+
+func TestNumberAsArbitraryValue(t *testing.T) {
+	assert.Equal(t, float64(2), Int(2).AsArbitraryValue())
+	assert.Equal(t, float64(bigInt64), Int64(bigInt64).AsArbitraryValue())
+	assert.Equal(t, float64(math.MaxUint64), Uint64(math.MaxUint64).AsArbitraryValue())
+	assert.Equal(t, 1.5, Float64(1.5).AsArbitraryValue())
+	assert.Equal(t, 1.5, Number("1.50").AsArbitraryValue())
+}
+
+func TestNumberAsArbitraryValueWithExactNumbers(t *testing.T) {
+	assert.Equal(t, float64(2), Int(2).AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, float64(maxExactFloat64Int), Int64(maxExactFloat64Int).AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, int64(bigInt64), Int64(bigInt64).AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, int64(-bigInt64), Int64(-bigInt64).AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, uint64(math.MaxUint64), Uint64(math.MaxUint64).AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, 1.5, Float64(1.5).AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, json.Number("1.50"), Number("1.50").AsArbitraryValueWithExactNumbers())
+	assert.Equal(t, "x", String("x").AsArbitraryValueWithExactNumbers())
+
+	v := ObjectBuild().Set("a", ArrayOf(Int64(bigInt64), Int(1))).Build()
+	assert.Equal(t, map[string]any{"a": []any{float64(bigInt64), float64(1)}}, v.AsArbitraryValue())
+	assert.Equal(t, map[string]any{"a": []any{int64(bigInt64), float64(1)}}, v.AsArbitraryValueWithExactNumbers())
+}
+
+func TestJSONNumber(t *testing.T) {
+	assert.Equal(t, json.Number("2"), Int(2).JSONNumber())
+	assert.Equal(t, json.Number("1.5"), Float64(1.5).JSONNumber())
+	assert.Equal(t, json.Number("1.50"), Number("1.50").JSONNumber())
+	assert.Equal(t, json.Number("3"), Raw(json.RawMessage("3")).JSONNumber())
+	assert.Equal(t, json.Number(""), String("3").JSONNumber())
+}
+
+func jsonStreamString(v Value) string {
+	w := jwriter.NewWriter()
+	v.WriteToJSONWriter(&w)
+	return string(w.Bytes())
+}
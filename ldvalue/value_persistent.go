@@ -0,0 +1,381 @@
+package ldvalue
+
+import "math/bits"
+
+// This file contains the persistent data structures that are used by ValueMap.With, ValueMap.Without,
+// and ValueArray.Append. A ValueMap or ValueArray normally wraps a plain Go map or slice, which is
+// efficient to build and read but must be copied entirely to produce a modified version. The first
+// time one of those methods is called, the data is converted to one of these structures instead;
+// after that, each update only copies the small part of the structure that changed, and shares the
+// rest with the previous version. Like everything else in a Value, these are never modified in place.
+
+const (
+	persistentBits  = 5
+	persistentWidth = 1 << persistentBits
+	persistentMask  = persistentWidth - 1
+)
+
+// persistentMap is a hash array mapped trie (HAMT). Each node uses 5 bits of the key's hash to select
+// one of up to 32 entries; a bitmap indicates which of those entries exist, so that the entries can
+// be stored compactly. Keys whose hashes are entirely equal are stored in a collision node at the
+// bottom of the trie.
+type persistentMap struct {
+	root  *hamtNode
+	count int
+}
+
+type hamtNode struct {
+	bitmap  uint32
+	entries []hamtEntry
+}
+
+// hamtEntry is either a key-value pair, or (if child is non-nil) a subtree.
+type hamtEntry struct {
+	hash  uint32
+	key   string
+	value Value
+	child *hamtNode
+}
+
+// hamtMaxShift is the largest shift that is used to select an entry within a node. A node at a
+// greater shift is a collision node, whose entries all have the same hash.
+const hamtMaxShift = 30
+
+func hamtHash(key string) uint32 {
+	// This is 32-bit FNV-1a, which is fast and does not allocate. Collisions are handled correctly,
+	// just less efficiently.
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return h
+}
+
+func hamtBit(hash uint32, shift uint) uint32 {
+	return 1 << ((hash >> shift) & persistentMask)
+}
+
+func (n *hamtNode) index(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func newPersistentMap(data map[string]Value) *persistentMap {
+	entries := make([]hamtEntry, 0, len(data))
+	for k, v := range data {
+		entries = append(entries, hamtEntry{hash: hamtHash(k), key: k, value: v})
+	}
+	return &persistentMap{root: buildHAMTNode(entries, 0), count: len(entries)}
+}
+
+// buildHAMTNode creates a node containing all of the specified entries at once, which is more
+// efficient than adding them one at a time.
+func buildHAMTNode(entries []hamtEntry, shift uint) *hamtNode {
+	if shift > hamtMaxShift {
+		return &hamtNode{entries: entries}
+	}
+	var starts [persistentWidth + 1]int
+	for _, e := range entries {
+		starts[(e.hash>>shift)&persistentMask+1]++
+	}
+	node := &hamtNode{}
+	for i := 0; i < persistentWidth; i++ {
+		if starts[i+1] > 0 {
+			node.bitmap |= 1 << i
+		}
+		starts[i+1] += starts[i]
+	}
+	sorted := make([]hamtEntry, len(entries))
+	next := starts
+	for _, e := range entries {
+		i := (e.hash >> shift) & persistentMask
+		sorted[next[i]] = e
+		next[i]++
+	}
+	node.entries = make([]hamtEntry, 0, bits.OnesCount32(node.bitmap))
+	for i := 0; i < persistentWidth; i++ {
+		switch bucket := sorted[starts[i]:starts[i+1]]; len(bucket) {
+		case 0:
+		case 1:
+			node.entries = append(node.entries, bucket[0])
+		default:
+			node.entries = append(node.entries, hamtEntry{child: buildHAMTNode(bucket, shift+persistentBits)})
+		}
+	}
+	return node
+}
+
+func (m *persistentMap) get(key string) (Value, bool) {
+	return m.root.get(hamtHash(key), key)
+}
+
+func (n *hamtNode) get(hash uint32, key string) (Value, bool) {
+	for shift := uint(0); n != nil; shift += persistentBits {
+		if shift > hamtMaxShift {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+		bit := hamtBit(hash, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		e := &n.entries[n.index(bit)]
+		if e.child == nil {
+			if e.hash == hash && e.key == key {
+				return e.value, true
+			}
+			break
+		}
+		n = e.child
+	}
+	return Null(), false
+}
+
+func (m *persistentMap) with(key string, value Value) *persistentMap {
+	root, added := m.root.with(hamtEntry{hash: hamtHash(key), key: key, value: value}, 0)
+	if added {
+		return &persistentMap{root: root, count: m.count + 1}
+	}
+	return &persistentMap{root: root, count: m.count}
+}
+
+func (n *hamtNode) with(leaf hamtEntry, shift uint) (*hamtNode, bool) {
+	if n == nil {
+		return &hamtNode{bitmap: hamtBit(leaf.hash, shift), entries: []hamtEntry{leaf}}, true
+	}
+	if shift > hamtMaxShift {
+		for i, e := range n.entries {
+			if e.key == leaf.key {
+				return n.replacing(i, leaf), false
+			}
+		}
+		entries := make([]hamtEntry, len(n.entries), len(n.entries)+1)
+		copy(entries, n.entries)
+		return &hamtNode{entries: append(entries, leaf)}, true
+	}
+	bit := hamtBit(leaf.hash, shift)
+	i := n.index(bit)
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry, len(n.entries)+1)
+		copy(entries, n.entries[:i])
+		entries[i] = leaf
+		copy(entries[i+1:], n.entries[i:])
+		return &hamtNode{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+	e := n.entries[i]
+	switch {
+	case e.child != nil:
+		child, added := e.child.with(leaf, shift+persistentBits)
+		return n.replacing(i, hamtEntry{child: child}), added
+	case e.key == leaf.key:
+		return n.replacing(i, leaf), false
+	default:
+		return n.replacing(i, hamtEntry{child: hamtPair(e, leaf, shift+persistentBits)}), true
+	}
+}
+
+// hamtPair creates a node containing two key-value pairs whose hashes are the same up to this shift.
+func hamtPair(a, b hamtEntry, shift uint) *hamtNode {
+	if shift > hamtMaxShift {
+		return &hamtNode{entries: []hamtEntry{a, b}}
+	}
+	bitA, bitB := hamtBit(a.hash, shift), hamtBit(b.hash, shift)
+	switch {
+	case bitA == bitB:
+		return &hamtNode{bitmap: bitA, entries: []hamtEntry{{child: hamtPair(a, b, shift+persistentBits)}}}
+	case bitA < bitB:
+		return &hamtNode{bitmap: bitA | bitB, entries: []hamtEntry{a, b}}
+	default:
+		return &hamtNode{bitmap: bitA | bitB, entries: []hamtEntry{b, a}}
+	}
+}
+
+func (m *persistentMap) without(key string) (*persistentMap, bool) {
+	root, removed := m.root.without(hamtHash(key), key, 0)
+	if !removed {
+		return m, false
+	}
+	return &persistentMap{root: root, count: m.count - 1}, true
+}
+
+// without returns a node that does not contain the key, or nil if the node would be empty.
+func (n *hamtNode) without(hash uint32, key string, shift uint) (*hamtNode, bool) {
+	if n == nil {
+		return nil, false
+	}
+	if shift > hamtMaxShift {
+		for i, e := range n.entries {
+			if e.key == key {
+				return n.removing(i, 0), true
+			}
+		}
+		return n, false
+	}
+	bit := hamtBit(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := n.index(bit)
+	e := n.entries[i]
+	if e.child == nil {
+		if e.key != key {
+			return n, false
+		}
+		return n.removing(i, bit), true
+	}
+	child, removed := e.child.without(hash, key, shift+persistentBits)
+	switch {
+	case !removed:
+		return n, false
+	case child == nil:
+		return n.removing(i, bit), true
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// A subtree that only contains one key-value pair can be replaced by that pair.
+		return n.replacing(i, child.entries[0]), true
+	default:
+		return n.replacing(i, hamtEntry{child: child}), true
+	}
+}
+
+func (n *hamtNode) replacing(i int, e hamtEntry) *hamtNode {
+	entries := make([]hamtEntry, len(n.entries))
+	copy(entries, n.entries)
+	entries[i] = e
+	return &hamtNode{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode) removing(i int, bit uint32) *hamtNode {
+	if len(n.entries) == 1 {
+		return nil
+	}
+	entries := make([]hamtEntry, 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:i]...)
+	entries = append(entries, n.entries[i+1:]...)
+	return &hamtNode{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+func (n *hamtNode) forEach(fn func(key string, value Value) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.forEach(fn) {
+				return false
+			}
+		} else if !fn(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// persistentVector is a trie in which each node has up to 32 children, and the leaves contain up to
+// 32 values each; the index of a value determines its path through the trie. The last 1 to 32 values
+// are stored separately in tail, so that appending a value usually only requires copying the tail.
+type persistentVector struct {
+	count int
+	shift uint // the shift for the root node; the values are in nodes at shift 0
+	root  *vectorNode
+	tail  []Value
+}
+
+type vectorNode struct {
+	children []*vectorNode
+	values   []Value
+}
+
+func newPersistentVector(data []Value) *persistentVector {
+	// The leaves can share the original slice, since that is also never modified.
+	v := &persistentVector{count: len(data), shift: persistentBits}
+	tailOffset := v.tailOffset()
+	v.tail = data[tailOffset:len(data):len(data)]
+	nodes := make([]*vectorNode, 0, tailOffset/persistentWidth)
+	for i := 0; i < tailOffset; i += persistentWidth {
+		nodes = append(nodes, &vectorNode{values: data[i : i+persistentWidth : i+persistentWidth]})
+	}
+	for len(nodes) > persistentWidth {
+		parents := make([]*vectorNode, 0, (len(nodes)+persistentMask)/persistentWidth)
+		for i := 0; i < len(nodes); i += persistentWidth {
+			end := i + persistentWidth
+			if end > len(nodes) {
+				end = len(nodes)
+			}
+			parents = append(parents, &vectorNode{children: nodes[i:end:end]})
+		}
+		nodes = parents
+		v.shift += persistentBits
+	}
+	v.root = &vectorNode{children: nodes}
+	return v
+}
+
+func (v *persistentVector) tailOffset() int {
+	if v.count <= persistentWidth {
+		return 0
+	}
+	return ((v.count - 1) >> persistentBits) << persistentBits
+}
+
+func (v *persistentVector) get(index int) Value {
+	tailOffset := v.tailOffset()
+	if index >= tailOffset {
+		return v.tail[index-tailOffset]
+	}
+	n := v.root
+	for shift := v.shift; shift > 0; shift -= persistentBits {
+		n = n.children[(index>>shift)&persistentMask]
+	}
+	return n.values[index&persistentMask]
+}
+
+func (v *persistentVector) append(value Value) *persistentVector {
+	if len(v.tail) < persistentWidth {
+		tail := make([]Value, len(v.tail)+1)
+		copy(tail, v.tail)
+		tail[len(v.tail)] = value
+		return &persistentVector{count: v.count + 1, shift: v.shift, root: v.root, tail: tail}
+	}
+	// The tail is full, so it becomes a leaf of the trie and we start a new tail.
+	leaf := &vectorNode{values: v.tail}
+	ret := &persistentVector{count: v.count + 1, shift: v.shift, tail: []Value{value}}
+	if (v.count >> persistentBits) > (1 << v.shift) {
+		// The trie is full, so it needs another level.
+		ret.root = &vectorNode{children: []*vectorNode{v.root, newVectorPath(v.shift, leaf)}}
+		ret.shift += persistentBits
+	} else {
+		ret.root = v.pushTail(v.shift, v.root, leaf)
+	}
+	return ret
+}
+
+func (v *persistentVector) pushTail(shift uint, parent, leaf *vectorNode) *vectorNode {
+	i := ((v.count - 1) >> shift) & persistentMask
+	child := leaf
+	if shift > persistentBits {
+		if i < len(parent.children) {
+			child = v.pushTail(shift-persistentBits, parent.children[i], leaf)
+		} else {
+			child = newVectorPath(shift-persistentBits, leaf)
+		}
+	}
+	children := make([]*vectorNode, len(parent.children), len(parent.children)+1)
+	copy(children, parent.children)
+	if i < len(children) {
+		children[i] = child
+	} else {
+		children = append(children, child)
+	}
+	return &vectorNode{children: children}
+}
+
+func newVectorPath(shift uint, leaf *vectorNode) *vectorNode {
+	if shift == 0 {
+		return leaf
+	}
+	return &vectorNode{children: []*vectorNode{newVectorPath(shift-persistentBits, leaf)}}
+}
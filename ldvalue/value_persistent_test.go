@@ -0,0 +1,208 @@
+package ldvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/launchdarkly/go-jsonstream/v3/jwriter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueMapWith(t *testing.T) {
+	m0 := ValueMapBuild().Set("a", Int(1)).Build()
+	m1 := m0.With("b", Int(2))
+	m2 := m1.With("a", Int(3))
+
+	assert.Equal(t, ValueMapBuild().Set("a", Int(1)).Build(), m0)
+	assert.True(t, m1.Equal(ValueMapBuild().Set("a", Int(1)).Set("b", Int(2)).Build()))
+	assert.True(t, m2.Equal(ValueMapBuild().Set("a", Int(3)).Set("b", Int(2)).Build()))
+	assert.Equal(t, 2, m2.Count())
+	assert.Equal(t, Int(1), m1.Get("a"))
+	assert.Equal(t, Int(3), m2.Get("a"))
+
+	fromNil := ValueMap{}.With("a", Int(1))
+	assert.True(t, fromNil.IsDefined())
+	assert.True(t, fromNil.Equal(ValueMapBuild().Set("a", Int(1)).Build()))
+}
+
+func TestValueMapWithout(t *testing.T) {
+	m0 := ValueMapBuild().Set("a", Int(1)).Set("b", Int(2)).Build()
+	m1 := m0.Without("a")
+	assert.Equal(t, 2, m0.Count())
+	assert.True(t, m1.Equal(ValueMapBuild().Set("b", Int(2)).Build()))
+
+	assert.Equal(t, m0, m0.Without("c"))
+	assert.Equal(t, ValueMap{}, ValueMap{}.Without("c"))
+
+	empty := m1.Without("b")
+	assert.True(t, empty.IsDefined())
+	assert.Equal(t, 0, empty.Count())
+	assert.Nil(t, empty.Keys(nil))
+	assert.Equal(t, "{}", empty.JSONString())
+	assert.True(t, empty.Equal(ValueMapBuild().Build()))
+	assert.False(t, empty.Equal(ValueMap{}))
+	assert.True(t, empty.With("c", Int(3)).Equal(ValueMapBuild().Set("c", Int(3)).Build()))
+}
+
+func TestPersistentValueMapHasSameBehaviorAsPlainMap(t *testing.T) {
+	m := ValueMapBuild().Set("a", Int(1)).Build().With("b", String("x")).With("c", ArrayOf(Bool(true)))
+	plain := m.AsMap()
+	assert.Equal(t, map[string]Value{"a": Int(1), "b": String("x"), "c": ArrayOf(Bool(true))}, plain)
+	assert.Equal(t, map[string]any{"a": float64(1), "b": "x", "c": []any{true}}, m.AsArbitraryValueMap())
+
+	keys := m.Keys(nil)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+	value, ok := m.TryGet("c")
+	assert.True(t, ok)
+	assert.Equal(t, ArrayOf(Bool(true)), value)
+	value, ok = m.TryGet("d")
+	assert.False(t, ok)
+	assert.Equal(t, Null(), value)
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal([]byte(m.JSONString()), &parsed))
+	assert.Equal(t, m.AsArbitraryValueMap(), parsed)
+	w := jwriter.NewWriter()
+	m.WriteToJSONWriter(&w)
+	assert.JSONEq(t, m.JSONString(), string(w.Bytes()))
+
+	assert.True(t, m.AsValue().Equal(CopyValueMap(plain).AsValue()))
+	assert.True(t, CopyValueMap(plain).Equal(m))
+	assert.False(t, m.Equal(m.With("a", Int(2))))
+	assert.False(t, m.Equal(m.Without("a")))
+	assert.False(t, m.Without("a").Equal(CopyValueMap(plain)))
+
+	assert.Equal(t, m, m.Transform(func(k string, v Value) (string, Value, bool) { return k, v, true }))
+	transformed := m.Transform(func(k string, v Value) (string, Value, bool) { return k, v, k != "b" })
+	assert.Equal(t, map[string]Value{"a": Int(1), "c": ArrayOf(Bool(true))}, transformed.AsMap())
+
+	built := ValueMapBuildFromMap(m).Set("d", Int(4)).Build()
+	assert.Equal(t, 4, built.Count())
+	assert.Equal(t, 3, m.Count())
+	built = ValueMapBuild().Set("z", Int(0)).SetAllFromValueMap(m).Build()
+	assert.Equal(t, 4, built.Count())
+}
+
+func TestValueMapWithManyKeys(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //nolint:gosec // deterministic test data
+	expected := make(map[string]Value)
+	m := CopyValueMap(map[string]Value{"initial": Int(-1)})
+	expected["initial"] = Int(-1)
+	var versions []ValueMap
+	var expectedVersions []map[string]Value
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("key%d", r.Intn(2000))
+		if r.Intn(3) == 0 {
+			m = m.Without(key)
+			delete(expected, key)
+		} else {
+			m = m.With(key, Int(i))
+			expected[key] = Int(i)
+		}
+		if i%500 == 0 {
+			versions = append(versions, m)
+			expectedVersions = append(expectedVersions, CopyValueMap(expected).AsMap())
+		}
+	}
+	assert.Equal(t, expected, m.AsMap())
+	assert.Equal(t, len(expected), m.Count())
+	for i, v := range versions {
+		assert.Equal(t, expectedVersions[i], v.AsMap())
+	}
+}
+
+func TestHAMTHashCollisions(t *testing.T) {
+	const hash = 12345
+	var root *hamtNode
+	for _, key := range []string{"a", "b", "c"} {
+		root, _ = root.with(hamtEntry{hash: hash, key: key, value: String(key)}, 0)
+	}
+	root, _ = root.with(hamtEntry{hash: hash + 1, key: "d", value: String("d")}, 0)
+	root, added := root.with(hamtEntry{hash: hash, key: "b", value: String("B")}, 0)
+	assert.False(t, added)
+	for key, expected := range map[string]Value{"a": String("a"), "b": String("B"), "c": String("c")} {
+		value, ok := root.get(hash, key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, value)
+	}
+	_, ok := root.get(hash, "d")
+	assert.False(t, ok)
+
+	root, removed := root.without(hash, "a", 0)
+	assert.True(t, removed)
+	_, removed = root.without(hash, "x", 0)
+	assert.False(t, removed)
+	root, _ = root.without(hash, "b", 0)
+	value, ok := root.get(hash, "c")
+	assert.True(t, ok)
+	assert.Equal(t, String("c"), value)
+	root, _ = root.without(hash, "c", 0)
+	root, _ = root.without(hash+1, "d", 0)
+	assert.Nil(t, root)
+}
+
+func TestValueArrayAppend(t *testing.T) {
+	a0 := ValueArrayOf(Int(1))
+	a1 := a0.Append(Int(2))
+	a2 := a0.Append(Int(3))
+	assert.Equal(t, ValueArrayOf(Int(1)), a0)
+	assert.True(t, a1.Equal(ValueArrayOf(Int(1), Int(2))))
+	assert.True(t, a2.Equal(ValueArrayOf(Int(1), Int(3))))
+	assert.False(t, a1.Equal(a2))
+
+	fromNil := ValueArray{}.Append(Int(1))
+	assert.True(t, fromNil.Equal(ValueArrayOf(Int(1))))
+	assert.True(t, ValueArrayOf().Append(Int(1)).Equal(ValueArrayOf(Int(1))))
+}
+
+func TestPersistentValueArrayHasSameBehaviorAsPlainArray(t *testing.T) {
+	a := ValueArrayOf(Int(1)).Append(String("x")).Append(ObjectBuild().Set("a", Bool(true)).Build())
+	assert.Equal(t, 3, a.Count())
+	assert.Equal(t, String("x"), a.Get(1))
+	assert.Equal(t, Null(), a.Get(3))
+	assert.Equal(t, Null(), a.Get(-1))
+	assert.Equal(t, []Value{Int(1), String("x"), ObjectBuild().Set("a", Bool(true)).Build()}, a.AsSlice())
+	assert.Equal(t, []any{float64(1), "x", map[string]any{"a": true}}, a.AsArbitraryValueSlice())
+	assert.Equal(t, `[1,"x",{"a":true}]`, a.JSONString())
+	assert.Equal(t, `[1,"x",{"a":true}]`, a.AsValue().JSONString())
+	w := jwriter.NewWriter()
+	a.WriteToJSONWriter(&w)
+	assert.Equal(t, `[1,"x",{"a":true}]`, string(w.Bytes()))
+
+	assert.Equal(t, a, a.Transform(func(i int, v Value) (Value, bool) { return v, true }))
+	transformed := a.Transform(func(i int, v Value) (Value, bool) { return v, i != 1 })
+	assert.Equal(t, ValueArrayOf(Int(1), ObjectBuild().Set("a", Bool(true)).Build()), transformed)
+
+	assert.Equal(t, 4, ValueArrayBuildFromArray(a).Add(Int(4)).Build().Count())
+	assert.Equal(t, 4, ValueArrayBuild().Add(Int(0)).AddAllFromValueArray(a).Build().Count())
+}
+
+func TestValueArrayAppendManyValues(t *testing.T) {
+	for _, initialSize := range []int{0, 1, 31, 32, 33, 1024, 1056, 1057, 40000} {
+		t.Run(fmt.Sprint(initialSize), func(t *testing.T) {
+			expected := make([]Value, 0, initialSize+2000)
+			for i := 0; i < initialSize; i++ {
+				expected = append(expected, Int(i))
+			}
+			a := CopyValueArray(expected)
+			snapshot := a.Append(String("snapshot"))
+			for i := initialSize; i < initialSize+2000; i++ {
+				a = a.Append(Int(i))
+				expected = append(expected, Int(i))
+			}
+			require.Equal(t, len(expected), a.Count())
+			for i, v := range expected {
+				require.Equal(t, v, a.Get(i), "index %d", i)
+			}
+			assert.Equal(t, initialSize+1, snapshot.Count())
+			assert.Equal(t, String("snapshot"), snapshot.Get(initialSize))
+		})
+	}
+}
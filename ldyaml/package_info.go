@@ -0,0 +1,10 @@
+// Package ldyaml converts between YAML documents and the LaunchDarkly SDK's [ldvalue.Value] type,
+// so that flag variation values and contexts can be written in YAML.
+//
+// Because the JSON data model is a subset of YAML's, the conversion in that direction is always
+// possible. In the other direction, YAML features that have no equivalent in JSON, such as
+// mapping keys that are not strings, cause an error.
+//
+// This package uses the third-party library gopkg.in/yaml.v3. It is not used by any other package
+// in go-sdk-common, so applications that do not import ldyaml do not include that code.
+package ldyaml
@@ -0,0 +1,339 @@
+package ldyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse converts a YAML document to a Value.
+//
+// YAML mappings, sequences, and scalars become JSON objects, arrays, and simple values. Integers
+// are stored exactly (see [ldvalue.Int64]), and floats such as "1.5" become non-integer numbers.
+// However, a float with an integral value, such as "1.0" or "1e3", becomes the same Value as the
+// corresponding integer, because a Value does not distinguish between those (see
+// [ldvalue.Float64]). Values with the
+// YAML types !!timestamp and !!binary become strings containing their original text. Anchors and
+// aliases are supported, including merge keys ("<<: *anchor").
+//
+// An empty document produces [ldvalue.Null](). If the data is not valid YAML, or contains more than
+// one document, the error comes from the YAML parser. If the document cannot be represented in JSON
+// (for instance, if a mapping key is not a string, if a mapping key appears twice, or if a number
+// is NaN or infinite), the error is of type [lderrors.ErrYAMLConversion].
+func Parse(data []byte) (ldvalue.Value, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := decoder.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return ldvalue.Null(), nil
+		}
+		return ldvalue.Null(), err
+	}
+	var another yaml.Node
+	if err := decoder.Decode(&another); !errors.Is(err, io.EOF) {
+		if err == nil {
+			return ldvalue.Null(), conversionError(&another, "expected a single YAML document, but found more than one")
+		}
+		return ldvalue.Null(), err
+	}
+	c := converter{anchored: make(map[*yaml.Node]ldvalue.Value), inProgress: make(map[*yaml.Node]bool)}
+	return c.convert(&doc)
+}
+
+// ParseContext converts a YAML document to a Context, by first converting it with [Parse] and then
+// interpreting the result in the same way as the JSON representation of a Context. See
+// [ldcontext.Context.UnmarshalJSON].
+func ParseContext(data []byte) (ldcontext.Context, error) {
+	var c ldcontext.Context
+	v, err := Parse(data)
+	if err != nil {
+		return c, err
+	}
+	err = c.UnmarshalJSON([]byte(v.JSONString()))
+	return c, err
+}
+
+// Marshal converts a Value to a YAML document.
+//
+// The output is intended to be easy to read and to compare: object properties are sorted by key,
+// nested values are in block style with two-space indentation, and multi-line strings use YAML's
+// literal style. Strings that would otherwise be interpreted as another type of value, such as
+// "true" or "1", are quoted. Converting the output back with [Parse] produces a Value that is
+// equal to the original one. A number with an integral value is written as a YAML integer, even if
+// it was parsed from a float.
+//
+// If the Value contains an unparsed JSON value created with [ldvalue.Raw] that is not valid JSON,
+// the error is [lderrors.ErrValueRawJSONInvalid].
+func Marshal(v ldvalue.Value) ([]byte, error) {
+	node, err := toNode(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalContext converts a Context to a YAML document, by converting its JSON representation (see
+// [ldcontext.Context.MarshalJSON]) with [Marshal].
+func MarshalContext(c ldcontext.Context) ([]byte, error) {
+	data, err := c.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(ldvalue.ParseLossless(data))
+}
+
+type converter struct {
+	// anchored contains the converted values of nodes that have anchors, so that each alias does not
+	// need to be converted separately; this also protects against documents that use nested aliases
+	// to expand to a huge size.
+	anchored   map[*yaml.Node]ldvalue.Value
+	inProgress map[*yaml.Node]bool
+}
+
+func conversionError(n *yaml.Node, format string, args ...any) error {
+	return lderrors.ErrYAMLConversion{Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (c *converter) convert(n *yaml.Node) (ldvalue.Value, error) {
+	if n.Kind == yaml.AliasNode {
+		if c.inProgress[n.Alias] {
+			return ldvalue.Null(), conversionError(n, "alias *%s refers to a value that contains it", n.Value)
+		}
+		if v, ok := c.anchored[n.Alias]; ok {
+			return v, nil
+		}
+		return c.convert(n.Alias)
+	}
+	if n.Anchor == "" {
+		return c.convertNode(n)
+	}
+	c.inProgress[n] = true
+	v, err := c.convertNode(n)
+	delete(c.inProgress, n)
+	if err == nil {
+		c.anchored[n] = v
+	}
+	return v, err
+}
+
+func (c *converter) convertNode(n *yaml.Node) (ldvalue.Value, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return ldvalue.Null(), nil
+		}
+		return c.convert(n.Content[0])
+	case yaml.SequenceNode:
+		if tag := n.ShortTag(); tag != "!!seq" {
+			return ldvalue.Null(), conversionError(n, "unsupported tag %s", tag)
+		}
+		builder := ldvalue.ArrayBuildWithCapacity(len(n.Content))
+		for _, item := range n.Content {
+			v, err := c.convert(item)
+			if err != nil {
+				return ldvalue.Null(), err
+			}
+			builder.Add(v)
+		}
+		return builder.Build(), nil
+	case yaml.MappingNode:
+		if tag := n.ShortTag(); tag != "!!map" {
+			return ldvalue.Null(), conversionError(n, "unsupported tag %s", tag)
+		}
+		return c.convertMapping(n)
+	default:
+		return convertScalar(n)
+	}
+}
+
+func (c *converter) convertMapping(n *yaml.Node) (ldvalue.Value, error) {
+	builder := ldvalue.ObjectBuildWithCapacity(len(n.Content) / 2)
+	keys := make(map[string]bool, len(n.Content)/2)
+	var merged []ldvalue.Value
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keyNode, valueNode := n.Content[i], n.Content[i+1]
+		for keyNode.Kind == yaml.AliasNode {
+			keyNode = keyNode.Alias
+		}
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+			values, err := c.convertMergeValue(valueNode)
+			if err != nil {
+				return ldvalue.Null(), err
+			}
+			merged = append(merged, values...)
+			continue
+		}
+		if keyNode.Kind != yaml.ScalarNode {
+			return ldvalue.Null(), conversionError(keyNode, "mapping key must be a string, not a collection")
+		}
+		if tag := keyNode.ShortTag(); tag != "!!str" {
+			return ldvalue.Null(), conversionError(keyNode,
+				"mapping key must be a string, but %q is %s (quote it to make it a string)", keyNode.Value, tag)
+		}
+		if keys[keyNode.Value] {
+			return ldvalue.Null(), conversionError(keyNode, "mapping key %q appears more than once", keyNode.Value)
+		}
+		keys[keyNode.Value] = true
+		v, err := c.convert(valueNode)
+		if err != nil {
+			return ldvalue.Null(), err
+		}
+		builder.Set(keyNode.Value, v)
+	}
+	// As defined in https://yaml.org/type/merge.html, keys in the mapping itself override merged keys,
+	// and keys in earlier merged mappings override those in later ones.
+	for _, m := range merged {
+		for _, key := range m.Keys(nil) {
+			if !keys[key] {
+				keys[key] = true
+				builder.Set(key, m.GetByKey(key))
+			}
+		}
+	}
+	return builder.Build(), nil
+}
+
+func (c *converter) convertMergeValue(n *yaml.Node) ([]ldvalue.Value, error) {
+	target := n
+	for target.Kind == yaml.AliasNode {
+		target = target.Alias
+	}
+	items := []*yaml.Node{n}
+	if target.Kind == yaml.SequenceNode {
+		items = target.Content
+	}
+	values := make([]ldvalue.Value, 0, len(items))
+	for _, item := range items {
+		v, err := c.convert(item)
+		if err != nil {
+			return nil, err
+		}
+		if v.Type() != ldvalue.ObjectType {
+			return nil, conversionError(item, "the value of a merge key must be a mapping or a sequence of mappings")
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func convertScalar(n *yaml.Node) (ldvalue.Value, error) {
+	switch tag := n.ShortTag(); tag {
+	case "!!null":
+		return ldvalue.Null(), nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return ldvalue.Null(), conversionError(n, "invalid boolean %q", n.Value)
+		}
+		return ldvalue.Bool(b), nil
+	case "!!int":
+		var i any
+		if err := n.Decode(&i); err == nil {
+			switch i := i.(type) {
+			case int:
+				return ldvalue.Int64(int64(i)), nil
+			case int64:
+				return ldvalue.Int64(i), nil
+			case uint64:
+				return ldvalue.Uint64(i), nil
+			}
+		}
+		return ldvalue.Null(), conversionError(n, "integer %s is out of range", n.Value)
+	case "!!float":
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return ldvalue.Null(), conversionError(n, "invalid number %q", n.Value)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return ldvalue.Null(), conversionError(n, "%s cannot be represented in JSON", n.Value)
+		}
+		return ldvalue.Float64(f), nil
+	case "!!str", "!!timestamp", "!!binary":
+		return ldvalue.String(n.Value), nil
+	default:
+		return ldvalue.Null(), conversionError(n, "unsupported tag %s", tag)
+	}
+}
+
+func toNode(v ldvalue.Value) (*yaml.Node, error) {
+	switch v.Type() {
+	case ldvalue.NullType:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	case ldvalue.BoolType:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: v.String()}, nil
+	case ldvalue.NumberType:
+		return numberNode(v), nil
+	case ldvalue.StringType:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.StringValue()}, nil
+	case ldvalue.ArrayType:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: make([]*yaml.Node, 0, v.Count())}
+		for i := 0; i < v.Count(); i++ {
+			item, err := toNode(v.GetByIndex(i))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		return node, nil
+	case ldvalue.ObjectType:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: make([]*yaml.Node, 0, v.Count()*2)}
+		for _, key := range v.AsValueMap().SortedKeys(nil) {
+			value, err := toNode(v.GetByKey(key))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		}
+		return node, nil
+	default: // RawType
+		if !json.Valid(v.AsRaw()) {
+			return nil, lderrors.ErrValueRawJSONInvalid{}
+		}
+		return toNode(ldvalue.ParseLossless(v.AsRaw()))
+	}
+}
+
+func numberNode(v ldvalue.Value) *yaml.Node {
+	f := v.Float64Value()
+	switch {
+	case math.IsNaN(f):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: ".nan"}
+	case math.IsInf(f, 1):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: ".inf"}
+	case math.IsInf(f, -1):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "-.inf"}
+	}
+	// An integer within the range of int64 or uint64 is stored exactly, and its JSON representation
+	// is also a valid YAML integer. Any other number must be written as a YAML float, which needs a
+	// decimal point or an exponent; the JSON representation has one unless the number is integral.
+	_, isInt64 := v.Int64Value()
+	_, isUint64 := v.Uint64Value()
+	if v.IsInt() && (isInt64 || isUint64) {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: string(v.JSONNumber())}
+	}
+	text := string(v.JSONNumber())
+	if !strings.ContainsAny(text, ".eE") {
+		text = strconv.FormatFloat(f, 'g', -1, 64) // always uses an exponent for integral values this large
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: text}
+}
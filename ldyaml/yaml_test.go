@@ -0,0 +1,275 @@
+package ldyaml
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/lderrors"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScalars(t *testing.T) {
+	for _, p := range []struct {
+		yaml     string
+		expected ldvalue.Value
+	}{
+		{"null", ldvalue.Null()},
+		{"~", ldvalue.Null()},
+		{"", ldvalue.Null()},
+		{"# just a comment", ldvalue.Null()},
+		{"true", ldvalue.Bool(true)},
+		{"false", ldvalue.Bool(false)},
+		{"yes", ldvalue.String("yes")}, // YAML 1.2 has only two boolean values
+		{"1", ldvalue.Int(1)},
+		{"-12", ldvalue.Int(-12)},
+		{"0x1f", ldvalue.Int(31)},
+		{"0o17", ldvalue.Int(15)},
+		{"9007199254740993", ldvalue.Int64(9007199254740993)},
+		{"18446744073709551615", ldvalue.Uint64(math.MaxUint64)},
+		{"1.5", ldvalue.Float64(1.5)},
+		{"1e3", ldvalue.Int(1000)},
+		{"-0.25", ldvalue.Float64(-0.25)},
+		{"abc", ldvalue.String("abc")},
+		{`"1"`, ldvalue.String("1")},
+		{`'true'`, ldvalue.String("true")},
+		{"!!str 1", ldvalue.String("1")},
+		{"!!float 1", ldvalue.Int(1)},
+		{"2001-12-14", ldvalue.String("2001-12-14")},
+		{"2001-12-14T21:59:43.10-05:00", ldvalue.String("2001-12-14T21:59:43.10-05:00")},
+		{"!!binary aGVsbG8=", ldvalue.String("aGVsbG8=")},
+		{"|\n  line 1\n  line 2\n", ldvalue.String("line 1\nline 2\n")},
+	} {
+		t.Run(p.yaml, func(t *testing.T) {
+			v, err := Parse([]byte(p.yaml))
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, v)
+		})
+	}
+}
+
+func TestParseIntegersAndFloatsAreDistinguished(t *testing.T) {
+	v, err := Parse([]byte("a: 2\nb: 2.5\nc: 9007199254740993\n"))
+	require.NoError(t, err)
+	assert.True(t, v.GetByKey("a").IsInt())
+	assert.False(t, v.GetByKey("b").IsInt())
+	assert.Equal(t, ldvalue.Int64(9007199254740993), v.GetByKey("c"))
+	assert.Equal(t, "9007199254740993", v.GetByKey("c").JSONString())
+}
+
+func TestParseIntegralFloatsBecomeIntegers(t *testing.T) {
+	v, err := Parse([]byte("a: 1.0\nb: 1e3\n"))
+	require.NoError(t, err)
+	assert.True(t, v.GetByKey("a").IsInt())
+	assert.True(t, v.GetByKey("b").IsInt())
+
+	output, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 1\nb: 1000\n", string(output))
+}
+
+func TestParseCollections(t *testing.T) {
+	v, err := Parse([]byte(`
+name: My flag
+variations:
+  - value: 1
+  - value: [a, b]
+  - {value: {nested: true}}
+empty-list: []
+empty-map: {}
+`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "My flag",
+		"variations": [{"value": 1}, {"value": ["a", "b"]}, {"value": {"nested": true}}],
+		"empty-list": [],
+		"empty-map": {}
+	}`, v.JSONString())
+}
+
+func TestParseAnchorsAndAliases(t *testing.T) {
+	v, err := Parse([]byte(`
+base: &base
+  color: red
+  size: 1
+list: &list [1, 2]
+copy: *base
+lists: [*list, *list]
+derived:
+  <<: *base
+  size: 2
+multi:
+  <<: [{a: 1, b: 1}, {b: 2, c: 2}]
+  c: 3
+`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"base": {"color": "red", "size": 1},
+		"list": [1, 2],
+		"copy": {"color": "red", "size": 1},
+		"lists": [[1, 2], [1, 2]],
+		"derived": {"color": "red", "size": 2},
+		"multi": {"a": 1, "b": 1, "c": 3}
+	}`, v.JSONString())
+}
+
+func TestParseManyNestedAliasesDoesNotExpandExponentially(t *testing.T) {
+	doc := "a0: &a0 [x, x]\n"
+	for i := 1; i < 40; i++ {
+		doc += "a" + itoa(i) + ": &a" + itoa(i) + " [*a" + itoa(i-1) + ", *a" + itoa(i-1) + "]\n"
+	}
+	v, err := Parse([]byte(doc))
+	require.NoError(t, err)
+	assert.Equal(t, 2, v.GetByKey("a39").Count())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, p := range []struct {
+		name, yaml      string
+		line, column    int
+		messageContains string
+	}{
+		{"integer key", "a: 1\n1: b\n", 2, 1, `"1" is !!int`},
+		{"boolean key", "true: b\n", 1, 1, `"true" is !!bool`},
+		{"null key", "~: b\n", 1, 1, `is !!null`},
+		{"collection key", "? [a]\n: b\n", 1, 3, "not a collection"},
+		{"duplicate key", "a: 1\nb: 2\na: 3\n", 3, 1, `"a" appears more than once`},
+		{"NaN", "a: .nan\n", 1, 4, ".nan cannot be represented"},
+		{"infinity", "a: [-.inf]\n", 1, 5, "-.inf cannot be represented"},
+		{"unknown tag", "a: !foo bar\n", 1, 4, "unsupported tag !foo"},
+		{"unknown collection tag", "a: !foo [1]\n", 1, 4, "unsupported tag !foo"},
+		{"integer out of range", "a: !!int 99999999999999999999\n", 1, 4, "out of range"},
+		{"bad merge", "a: {<<: 1}\n", 1, 9, "merge key"},
+		{"multiple documents", "a: 1\n---\nb: 2\n", 2, 1, "more than one"},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			_, err := Parse([]byte(p.yaml))
+			require.Error(t, err)
+			require.IsType(t, lderrors.ErrYAMLConversion{}, err)
+			e := err.(lderrors.ErrYAMLConversion)
+			assert.Equal(t, p.line, e.Line, "line")
+			assert.Equal(t, p.column, e.Column, "column")
+			assert.Contains(t, e.Message, p.messageContains)
+		})
+	}
+
+	_, err := Parse([]byte("a: [1\n"))
+	assert.Error(t, err)
+	_, err = Parse([]byte("a: &x [*x]\n"))
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	v := ldvalue.ObjectBuild().
+		Set("string", ldvalue.String("x")).
+		Set("numeric-string", ldvalue.String("1")).
+		Set("bool-string", ldvalue.String("true")).
+		Set("multiline", ldvalue.String("line 1\nline 2\n")).
+		Set("int", ldvalue.Int(2)).
+		Set("big", ldvalue.Uint64(math.MaxUint64)).
+		Set("float", ldvalue.Float64(2.5)).
+		Set("huge", ldvalue.Float64(1e300)).
+		Set("null", ldvalue.Null()).
+		Set("bool", ldvalue.Bool(true)).
+		Set("array", ldvalue.ArrayOf(ldvalue.Int(1), ldvalue.ObjectBuild().Set("b", ldvalue.Int(2)).Set("a", ldvalue.Int(1)).Build())).
+		Set("empty", ldvalue.ArrayOf()).
+		Set("raw", ldvalue.Raw(json.RawMessage(`{"z":[9007199254740993]}`))).
+		Build()
+	data, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `array:
+  - 1
+  - a: 1
+    b: 2
+big: 18446744073709551615
+bool: true
+bool-string: "true"
+empty: []
+float: 2.5
+huge: 1e+300
+int: 2
+multiline: |
+  line 1
+  line 2
+"null": null
+numeric-string: "1"
+raw:
+  z:
+    - 9007199254740993
+string: x
+`, string(data))
+
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(ldvalue.ParseLossless([]byte(v.JSONString()))), "round trip: %s", parsed)
+}
+
+func TestMarshalSimpleValues(t *testing.T) {
+	for _, p := range []struct {
+		value    ldvalue.Value
+		expected string
+	}{
+		{ldvalue.Null(), "null\n"},
+		{ldvalue.Int(-3), "-3\n"},
+		{ldvalue.Float64(1e20), "1e+20\n"},
+		{ldvalue.Float64(math.NaN()), ".nan\n"},
+		{ldvalue.Float64(math.Inf(1)), ".inf\n"},
+		{ldvalue.Float64(math.Inf(-1)), "-.inf\n"},
+		{ldvalue.String(""), "\"\"\n"},
+		{ldvalue.String("null"), "\"null\"\n"},
+	} {
+		t.Run(p.expected, func(t *testing.T) {
+			data, err := Marshal(p.value)
+			require.NoError(t, err)
+			assert.Equal(t, p.expected, string(data))
+		})
+	}
+}
+
+func TestMarshalInvalidRawValue(t *testing.T) {
+	_, err := Marshal(ldvalue.ArrayOf(ldvalue.Raw(json.RawMessage(`{`))))
+	assert.Equal(t, lderrors.ErrValueRawJSONInvalid{}, err)
+}
+
+func TestContextConversion(t *testing.T) {
+	c, err := ParseContext([]byte(`
+kind: multi
+user:
+  key: user-key
+  name: Lucy
+  tags: [a, b]
+org:
+  key: org-key
+  _meta:
+    privateAttributes: [/address/city]
+  address: {city: Oakland}
+`))
+	require.NoError(t, err)
+	expected := ldcontext.NewMulti(
+		ldcontext.NewBuilder("user-key").Name("Lucy").SetValue("tags", ldvalue.ArrayOf(ldvalue.String("a"), ldvalue.String("b"))).Build(),
+		ldcontext.NewBuilder("org-key").Kind("org").SetValue("address", ldvalue.ObjectBuild().Set("city", ldvalue.String("Oakland")).Build()).
+			Private("/address/city").Build(),
+	)
+	assert.Equal(t, expected, c)
+
+	data, err := MarshalContext(c)
+	require.NoError(t, err)
+	roundTrip, err := ParseContext(data)
+	require.NoError(t, err)
+	assert.Equal(t, c, roundTrip)
+
+	_, err = ParseContext([]byte("kind: user\n"))
+	assert.Error(t, err)
+	_, err = ParseContext([]byte("1: user\n"))
+	assert.IsType(t, lderrors.ErrYAMLConversion{}, err)
+	_, err = MarshalContext(ldcontext.Context{})
+	assert.Error(t, err)
+}
+
+func itoa(i int) string {
+	return ldvalue.Int(i).JSONString()
+}